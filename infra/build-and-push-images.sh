@@ -30,8 +30,9 @@ WORKER_ECR_URI="${AWS_ACCOUNT_ID}.dkr.ecr.${REGION}.amazonaws.com/${WORKER_REPO}
 # Directorios base
 SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
 PROJECT_ROOT="$(cd "${SCRIPT_DIR}/.." && pwd)"
-API_DIR="${PROJECT_ROOT}/services/api"
-WORKER_DIR="${PROJECT_ROOT}/services/worker"
+SERVICES_DIR="${PROJECT_ROOT}/services"
+API_DIR="${SERVICES_DIR}/api"
+WORKER_DIR="${SERVICES_DIR}/worker"
 
 # Función para imprimir mensajes
 log_info() {
@@ -95,11 +96,13 @@ build_and_push_api() {
         return 1
     fi
 
-    cd "${API_DIR}"
+    # El contexto es services/ para incluir el módulo compartido contracts
+    cd "${SERVICES_DIR}"
 
     # Construir imagen
     log_info "Construyendo imagen Docker..."
-    docker build -t ${API_REPO}:latest \
+    docker build -f api/Dockerfile \
+                 -t ${API_REPO}:latest \
                  -t ${API_ECR_URI}:latest \
                  -t ${API_ECR_URI}:$(date +%Y%m%d-%H%M%S) \
                  .
@@ -147,11 +150,13 @@ build_and_push_worker() {
         return 1
     fi
 
-    cd "${WORKER_DIR}"
+    # El contexto es services/ para incluir el módulo compartido contracts
+    cd "${SERVICES_DIR}"
 
     # Construir imagen
     log_info "Construyendo imagen Docker..."
-    docker build -t ${WORKER_REPO}:latest \
+    docker build -f worker/Dockerfile \
+                 -t ${WORKER_REPO}:latest \
                  -t ${WORKER_ECR_URI}:latest \
                  -t ${WORKER_ECR_URI}:$(date +%Y%m%d-%H%M%S) \
                  .
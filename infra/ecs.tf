@@ -246,9 +246,15 @@ resource "aws_ecs_task_definition" "worker_service" {
       name      = "worker-service"
       image     = "${aws_ecr_repository.worker_service.repository_url}:latest"
       essential = true
+      # SIGTERM -> SIGKILL: cubre workerStopTimeout (30s) más el margen de
+      # cancelación del lifecycle manager (5s)
+      stopTimeout = 60
 
+      # El worker atiende un solo namespace; para otro namespace se despliega
+      # otro servicio con su propio TEMPORAL_NAMESPACE
       environment = [
         { name = "TEMPORAL_HOST_PORT", value = "frontend.temporal:7233" },
+        { name = "TEMPORAL_NAMESPACE", value = "default" },
         { name = "TASK_QUEUE", value = "hello-world-queue" },
         { name = "DD_SERVICE", value = "temporal-worker" },
         { name = "DD_ENV", value = "production" },
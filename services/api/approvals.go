@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"go.temporal.io/api/workflowservice/v1"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// PendingApproval es un workflow esperando aprobación manual
+type PendingApproval struct {
+	WorkflowID string                  `json:"workflowId"`
+	RunID      string                  `json:"runId"`
+	Approval   contracts.ApprovalState `json:"approval"`
+}
+
+// ApprovalDecisionRequest es el payload para aprobar o rechazar un workflow
+type ApprovalDecisionRequest struct {
+	WorkflowID string `json:"workflowId"`
+	RunID      string `json:"runId"`
+	Approved   bool   `json:"approved"`
+	Approver   string `json:"approver"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+// validateApprovalPolicy revisa los plazos enviados; los campos vacíos toman
+// el valor por defecto del worker
+func validateApprovalPolicy(p contracts.ApprovalPolicy) error {
+	var escalateAfter, deadline time.Duration
+	var err error
+	if p.EscalateAfter != "" {
+		if escalateAfter, err = time.ParseDuration(p.EscalateAfter); err != nil {
+			return err
+		}
+		if escalateAfter <= 0 {
+			return errors.New("escalateAfter must be positive")
+		}
+	}
+	if p.Deadline != "" {
+		if deadline, err = time.ParseDuration(p.Deadline); err != nil {
+			return err
+		}
+	}
+	if escalateAfter > 0 && deadline > 0 && deadline < escalateAfter {
+		return errors.New("deadline must not be before escalateAfter")
+	}
+	return nil
+}
+
+// ListApprovalsResponse es una página de aprobaciones pendientes;
+// NextPageToken vacío indica que no hay más resultados
+type ListApprovalsResponse struct {
+	Approvals     []PendingApproval `json:"approvals"`
+	NextPageToken string            `json:"nextPageToken,omitempty"`
+}
+
+// pendingApprovalsQuery busca por el search attribute que publica WorkflowA,
+// así el listado no consulta cada ejecución en curso
+var pendingApprovalsQuery = fmt.Sprintf("WorkflowType = '%s' AND ExecutionStatus = 'Running' AND %s IN ('%s', '%s')",
+	contracts.WorkflowA, contracts.ApprovalStatusSearchAttribute, contracts.ApprovalPending, contracts.ApprovalEscalated)
+
+// listApprovalsHandler lista los workflows que esperan aprobación manual,
+// paginado como /workflows (pageSize y nextPageToken)
+func (s *Server) listApprovalsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pageSize, pageToken, ok := parsePageParams(w, r.URL.Query())
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := s.backendFor(r).ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+		Query:         pendingApprovalsQuery,
+		PageSize:      int32(pageSize),
+		NextPageToken: pageToken,
+	})
+	if err != nil {
+		log.Printf("Error listing workflows: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to list workflows", err.Error())
+		return
+	}
+
+	response := ListApprovalsResponse{Approvals: []PendingApproval{}}
+	for _, execution := range resp.GetExecutions() {
+		workflowID := execution.GetExecution().GetWorkflowId()
+
+		// El detalle viaja en el memo que actualiza el workflow junto con ApprovalStatus
+		var state contracts.ApprovalState
+		payload, ok := execution.GetMemo().GetFields()[contracts.ApprovalStateMemoKey]
+		if !ok {
+			log.Printf("Approval state of %s missing from memo", workflowID)
+			continue
+		}
+		if err := contracts.DataConverter().FromPayload(payload, &state); err != nil {
+			log.Printf("Error decoding approval state of %s: %v", workflowID, err)
+			continue
+		}
+
+		response.Approvals = append(response.Approvals, PendingApproval{
+			WorkflowID: workflowID,
+			RunID:      execution.GetExecution().GetRunId(),
+			Approval:   state,
+		})
+	}
+	if token := resp.GetNextPageToken(); len(token) > 0 {
+		response.NextPageToken = base64.URLEncoding.EncodeToString(token)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// approvalDecisionHandler envía la señal de aprobación o rechazo
+func (s *Server) approvalDecisionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ApprovalDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+	if req.WorkflowID == "" || req.Approver == "" {
+		respondWithError(w, http.StatusBadRequest, "workflowId and approver are required", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	decision := contracts.ApprovalDecision{
+		Approved: req.Approved,
+		Approver: req.Approver,
+		Comment:  req.Comment,
+	}
+	if err := s.backendFor(r).SignalWorkflow(ctx, req.WorkflowID, req.RunID, contracts.ApprovalSignalName, decision); err != nil {
+		log.Printf("Error signaling workflow %s: %v", req.WorkflowID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to send approval decision", err.Error())
+		return
+	}
+
+	log.Printf("Approval decision sent - ID: %s, approved: %t, approver: %s", req.WorkflowID, req.Approved, req.Approver)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"workflowId": req.WorkflowID,
+		"message":    "Approval decision sent",
+	})
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+)
+
+// Backend son las operaciones sobre workflows que usan los handlers. Las
+// implementan el cliente de Temporal (client.Client) y el simulador en
+// memoria (-backend simulator), que permite usar el API sin un cluster.
+type Backend interface {
+	ExecuteWorkflow(ctx context.Context, options client.StartWorkflowOptions, workflow interface{}, args ...interface{}) (client.WorkflowRun, error)
+	GetWorkflow(ctx context.Context, workflowID, runID string) client.WorkflowRun
+	DescribeWorkflowExecution(ctx context.Context, workflowID, runID string) (*workflowservice.DescribeWorkflowExecutionResponse, error)
+	GetWorkflowHistory(ctx context.Context, workflowID, runID string, isLongPoll bool, filterType enumspb.HistoryEventFilterType) client.HistoryEventIterator
+	// GetWorkflowHistoryPage lee una página del historial con su page token,
+	// que GetWorkflowHistory oculta detrás del iterador
+	GetWorkflowHistoryPage(ctx context.Context, request *workflowservice.GetWorkflowExecutionHistoryRequest) (*workflowservice.GetWorkflowExecutionHistoryResponse, error)
+	ListWorkflow(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*workflowservice.ListWorkflowExecutionsResponse, error)
+	QueryWorkflow(ctx context.Context, workflowID, runID, queryType string, args ...interface{}) (converter.EncodedValue, error)
+	SignalWorkflow(ctx context.Context, workflowID, runID, signalName string, arg interface{}) error
+	CancelWorkflow(ctx context.Context, workflowID, runID string) error
+	Close()
+}
+
+// temporalNamespace es el Backend real: el cliente de Temporal de un namespace
+type temporalNamespace struct {
+	client.Client
+	namespace string
+}
+
+var _ Backend = (*temporalNamespace)(nil)
+
+func (t *temporalNamespace) GetWorkflowHistoryPage(ctx context.Context, request *workflowservice.GetWorkflowExecutionHistoryRequest) (*workflowservice.GetWorkflowExecutionHistoryResponse, error) {
+	request.Namespace = t.namespace
+	return t.WorkflowService().GetWorkflowExecutionHistory(ctx, request)
+}
+
+// Backends que acepta -backend
+const (
+	temporalBackend  = "temporal"
+	simulatorBackend = "simulator"
+)
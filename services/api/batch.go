@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// BatchRequest es la parte batch de StartWorkflowRequest; inicia BatchWorkflow
+type BatchRequest struct {
+	// Items es la lista inline; los strings se envían tal cual y el resto
+	// de los valores serializados como JSON
+	Items []interface{} `json:"items,omitempty"`
+	// ItemsRef referencia una lista en el directorio de batches del worker
+	ItemsRef       string `json:"itemsRef,omitempty"`
+	Activity       string `json:"activity,omitempty"`
+	MaxParallelism int    `json:"maxParallelism,omitempty"`
+	ItemsPerRun    int    `json:"itemsPerRun,omitempty"`
+}
+
+// Validate revisa que el batch tenga items o una referencia, no ambos, y que
+// la activity sea una de contracts.BatchItemActivities
+func (b BatchRequest) Validate() error {
+	if (len(b.Items) == 0) == (b.ItemsRef == "") {
+		return errors.New("exactly one of items or itemsRef is required")
+	}
+	if b.Activity != "" && !contracts.IsBatchItemActivity(b.Activity) {
+		return fmt.Errorf("activity %q is not allowed in batches, use one of %v", b.Activity, contracts.BatchItemActivities)
+	}
+	if b.MaxParallelism < 0 || b.ItemsPerRun < 0 {
+		return errors.New("maxParallelism and itemsPerRun must not be negative")
+	}
+	return nil
+}
+
+// toInput construye el input de BatchWorkflow para el workflow batchID
+func (b BatchRequest) toInput(batchID string) (contracts.BatchInput, error) {
+	input := contracts.BatchInput{
+		BatchID:        batchID,
+		ItemsRef:       b.ItemsRef,
+		Activity:       b.Activity,
+		MaxParallelism: b.MaxParallelism,
+		ItemsPerRun:    b.ItemsPerRun,
+	}
+	for _, item := range b.Items {
+		if s, ok := item.(string); ok {
+			input.Items = append(input.Items, s)
+			continue
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			return contracts.BatchInput{}, err
+		}
+		input.Items = append(input.Items, string(data))
+	}
+	return input, nil
+}
+
+// batchProgressHandler consulta el avance de un BatchWorkflow en curso.
+// Sin runId se consulta el run actual, que tras continue-as-new es el último.
+func (s *Server) batchProgressHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	workflowID := r.URL.Query().Get("workflowId")
+	if workflowID == "" {
+		respondWithError(w, http.StatusBadRequest, "workflowId parameter is required", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	value, err := s.backendFor(r).QueryWorkflow(ctx, workflowID, r.URL.Query().Get("runId"), contracts.BatchProgressQueryName)
+	if err != nil {
+		log.Printf("Error querying batch progress of %s: %v", workflowID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to query batch progress", err.Error())
+		return
+	}
+
+	var progress contracts.BatchProgress
+	if err := value.Get(&progress); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to decode batch progress", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(progress)
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.temporal.io/api/serviceerror"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/contracts/temporalconn"
+)
+
+// clusterHeader elige el cluster de Temporal del request; sin él se usa el
+// cluster por defecto
+const clusterHeader = "X-Temporal-Cluster"
+
+// ClustersConfig es el archivo de API_CLUSTERS_FILE (YAML o JSON):
+//
+//	default: temporal-aws-poc
+//	failover: infra-2
+//	clusters:
+//	  - name: temporal-aws-poc
+//	    hostPort: frontend.temporal:7233
+//	  - name: infra-2
+//	    hostPort: temporal.infra-2.internal:7233
+//	    namespaces: [default, staging]
+//	    tls:
+//	      caFile: /certs/infra-2/ca.pem
+//	      certFile: /certs/infra-2/client.pem
+//	      keyFile: /certs/infra-2/client-key.pem
+//
+// Sin archivo hay un único cluster, "default", con TEMPORAL_HOST_PORT y las
+// variables TEMPORAL_TLS_*. Con archivo esas variables no se usan: un
+// cluster sin sección tls se conecta en texto plano.
+type ClustersConfig struct {
+	// Default es el cluster de los requests sin X-Temporal-Cluster; vacío
+	// usa el primero de la lista
+	Default string `json:"default,omitempty"`
+	// Failover es opcional: mientras el cluster por defecto no responde a
+	// los health checks, los inicios de workflow sin cluster explícito se
+	// hacen en este
+	Failover string          `json:"failover,omitempty"`
+	Clusters []ClusterConfig `json:"clusters"`
+}
+
+// ClusterConfig es un cluster de Temporal
+type ClusterConfig struct {
+	Name     string `json:"name"`
+	HostPort string `json:"hostPort"`
+	// Namespaces limita los namespaces que se usan en el cluster; vacío los
+	// permite todos (siempre sujeto a la credencial del llamador)
+	Namespaces []string          `json:"namespaces,omitempty"`
+	TLS        *ClusterTLSConfig `json:"tls,omitempty"`
+}
+
+// ClusterTLSConfig son los ajustes de TLS y credenciales de un cluster; los
+// mismos que las variables TEMPORAL_TLS_* y TEMPORAL_API_KEY
+type ClusterTLSConfig struct {
+	Enabled    bool   `json:"enabled,omitempty"`
+	CAFile     string `json:"caFile,omitempty"`
+	CertFile   string `json:"certFile,omitempty"`
+	KeyFile    string `json:"keyFile,omitempty"`
+	ServerName string `json:"serverName,omitempty"`
+	// La API key no va en el archivo: se lee de otro archivo o de una
+	// variable de entorno
+	APIKeyFile string `json:"apiKeyFile,omitempty"`
+	APIKeyEnv  string `json:"apiKeyEnv,omitempty"`
+}
+
+// loadClustersConfig lee y valida el archivo de clusters
+func loadClustersConfig(path string) (ClustersConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ClustersConfig{}, err
+	}
+
+	var config ClustersConfig
+	if err := contracts.DecodeYAMLStrict(data, &config); err != nil {
+		return ClustersConfig{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return config, config.validate()
+}
+
+func (c ClustersConfig) validate() error {
+	if len(c.Clusters) == 0 {
+		return fmt.Errorf("at least one cluster is required")
+	}
+	seen := map[string]bool{}
+	for _, cluster := range c.Clusters {
+		if cluster.Name == "" {
+			return fmt.Errorf("cluster name is required")
+		}
+		if seen[cluster.Name] {
+			return fmt.Errorf("cluster %s is declared twice", cluster.Name)
+		}
+		seen[cluster.Name] = true
+		if cluster.HostPort == "" {
+			return fmt.Errorf("cluster %s: hostPort is required", cluster.Name)
+		}
+		if tls := cluster.TLS; tls != nil && tls.APIKeyFile != "" && tls.APIKeyEnv != "" {
+			return fmt.Errorf("cluster %s: apiKeyFile and apiKeyEnv are mutually exclusive", cluster.Name)
+		}
+	}
+	if c.Default != "" && !seen[c.Default] {
+		return fmt.Errorf("default cluster %s is not declared", c.Default)
+	}
+	if c.Failover != "" {
+		if !seen[c.Failover] {
+			return fmt.Errorf("failover cluster %s is not declared", c.Failover)
+		}
+		if c.Failover == c.defaultName() {
+			return fmt.Errorf("failover cluster must differ from the default cluster")
+		}
+	}
+	return nil
+}
+
+func (c ClustersConfig) defaultName() string {
+	if c.Default != "" {
+		return c.Default
+	}
+	return c.Clusters[0].Name
+}
+
+// security convierte los ajustes del archivo en un temporalconn.Security
+func (t *ClusterTLSConfig) security(getenv func(string) string) (temporalconn.Security, error) {
+	if t == nil {
+		return temporalconn.Security{}, nil
+	}
+	security := temporalconn.Security{
+		TLS:        t.Enabled,
+		CAFile:     t.CAFile,
+		CertFile:   t.CertFile,
+		KeyFile:    t.KeyFile,
+		ServerName: t.ServerName,
+	}
+	if t.APIKeyEnv != "" {
+		security.APIKey = getenv(t.APIKeyEnv)
+	}
+	if t.APIKeyFile != "" {
+		key, err := temporalconn.ReadAPIKeyFile(t.APIKeyFile)
+		if err != nil {
+			return temporalconn.Security{}, fmt.Errorf("apiKeyFile: %w", err)
+		}
+		security.APIKey = key
+	}
+	return security, security.Validate()
+}
+
+// temporalCluster es un cluster con su conexión y sus clientes por namespace
+type temporalCluster struct {
+	name string
+	// connection es nil con el simulador, que siempre está disponible
+	connection *temporalConnection
+	namespaces *namespaceClients
+	// allowed son los namespaces del cluster; vacío permite todos
+	allowed []string
+}
+
+// ready indica si el cluster responde
+func (c *temporalCluster) ready() bool {
+	return c.connection == nil || c.connection.Ready()
+}
+
+// serves indica si el namespace se puede usar en el cluster
+func (c *temporalCluster) serves(namespace string) bool {
+	if len(c.allowed) == 0 {
+		return true
+	}
+	for _, allowed := range c.allowed {
+		if allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterRegistry son los clusters de Temporal que la API conoce
+type clusterRegistry struct {
+	clusters    map[string]*temporalCluster
+	names       []string
+	defaultName string
+	failover    string
+}
+
+// newClusterRegistry crea los clientes (sin conectar) de todos los clusters.
+// Un cluster sin sección tls usa envSecurity: la de las variables de entorno
+// para el cluster que se arma sin archivo y vacía (texto plano) con archivo.
+func newClusterRegistry(config ClustersConfig, backoff temporalconn.Backoff, envSecurity temporalconn.Security, getenv func(string) string) (*clusterRegistry, error) {
+	registry := &clusterRegistry{
+		clusters:    map[string]*temporalCluster{},
+		defaultName: config.defaultName(),
+		failover:    config.Failover,
+	}
+	for _, clusterConfig := range config.Clusters {
+		security := envSecurity
+		if clusterConfig.TLS != nil {
+			var err error
+			if security, err = clusterConfig.TLS.security(getenv); err != nil {
+				return nil, fmt.Errorf("cluster %s: %w", clusterConfig.Name, err)
+			}
+		}
+		connection, err := newTemporalConnection(clusterConfig.HostPort, security, backoff)
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: %w", clusterConfig.Name, err)
+		}
+		log.Printf("Cluster %s: %s (%s)", clusterConfig.Name, clusterConfig.HostPort, security)
+
+		registry.names = append(registry.names, clusterConfig.Name)
+		registry.clusters[clusterConfig.Name] = &temporalCluster{
+			name:       clusterConfig.Name,
+			connection: connection,
+			namespaces: newNamespaceClients(connection.client),
+			allowed:    clusterConfig.Namespaces,
+		}
+	}
+	return registry, nil
+}
+
+// run mantiene la conexión de todos los clusters hasta que ctx se cancela.
+// El canal recibe el error de cada cluster que agota el tiempo máximo sin
+// conectar; ese cluster queda not-ready.
+func (r *clusterRegistry) run(ctx context.Context) <-chan error {
+	errs := make(chan error, len(r.names))
+	for _, name := range r.names {
+		if connection := r.clusters[name].connection; connection != nil {
+			name := name
+			go func() {
+				if err := connection.run(ctx); err != nil {
+					errs <- fmt.Errorf("cluster %s: %w", name, err)
+				}
+			}()
+		}
+	}
+	return errs
+}
+
+// close cierra los clientes de todos los clusters
+func (r *clusterRegistry) close() {
+	for _, name := range r.names {
+		cluster := r.clusters[name]
+		cluster.namespaces.close()
+		if cluster.connection != nil {
+			cluster.connection.client.Close()
+		}
+	}
+}
+
+// resolve devuelve el cluster pedido o el por defecto. Con failover, un
+// inicio sin cluster explícito va al secundario mientras el por defecto no
+// responde y el secundario sí.
+func (r *clusterRegistry) resolve(name string, start bool) (*temporalCluster, error) {
+	if name != "" {
+		cluster, ok := r.clusters[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown cluster %q (known: %s)", name, strings.Join(r.names, ", "))
+		}
+		return cluster, nil
+	}
+
+	primary := r.clusters[r.defaultName]
+	if start && r.failover != "" && !primary.ready() {
+		if secondary := r.clusters[r.failover]; secondary.ready() {
+			log.Printf("Cluster %s is unavailable, starting workflow on failover cluster %s", primary.name, secondary.name)
+			return secondary, nil
+		}
+	}
+	return primary, nil
+}
+
+// locate busca la ejecución en los clusters que sirven el namespace,
+// empezando por current. Devuelve current si no la encuentra en ninguno, así
+// el handler responde el error de siempre (o 503 si current no responde).
+func (r *clusterRegistry) locate(ctx context.Context, current *temporalCluster, namespace, workflowID, runID string) *temporalCluster {
+	candidates := []*temporalCluster{current}
+	for _, name := range r.names {
+		if cluster := r.clusters[name]; cluster != current && cluster.serves(namespace) {
+			candidates = append(candidates, cluster)
+		}
+	}
+
+	for _, cluster := range candidates {
+		if !cluster.ready() {
+			continue
+		}
+		backend, err := cluster.namespaces.get(namespace)
+		if err != nil {
+			continue
+		}
+		_, err = backend.DescribeWorkflowExecution(ctx, workflowID, runID)
+		if err == nil {
+			return cluster
+		}
+		var notFound *serviceerror.NotFound
+		if !errors.As(err, &notFound) {
+			// Otro error del cluster: el handler lo informa al llamador
+			return current
+		}
+	}
+	return current
+}
+
+type requestClusterKey struct{}
+
+// withCluster resuelve el cluster del request (header X-Temporal-Cluster);
+// start habilita el failover, que solo aplica a los inicios de workflow
+func (s *Server) withCluster(start bool, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cluster, err := s.clusters.resolve(r.Header.Get(clusterHeader), start)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid cluster", err.Error())
+			return
+		}
+		w.Header().Set(clusterHeader, cluster.name)
+		next(w, r.WithContext(context.WithValue(r.Context(), requestClusterKey{}, cluster)))
+	}
+}
+
+// clusterFor devuelve el cluster del request
+func (s *Server) clusterFor(r *http.Request) *temporalCluster {
+	if cluster, ok := r.Context().Value(requestClusterKey{}).(*temporalCluster); ok {
+		return cluster
+	}
+	return s.clusters.clusters[s.clusters.defaultName]
+}
+
+// locateWorkflow, con failover configurado, busca en qué cluster está la
+// ejecución del request cuando el llamador no envía X-Temporal-Cluster: un
+// workflow iniciado durante un failover vive en el secundario y las llamadas
+// posteriores sin header irían al cluster por defecto, que puede seguir caído.
+// Va antes de requireTemporal y solo busca en namespaces que la credencial
+// puede usar; withNamespace hace después las validaciones de siempre.
+func (s *Server) locateWorkflow(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.clusters.failover == "" || r.Header.Get(clusterHeader) != "" {
+			next(w, r)
+			return
+		}
+		namespace := s.namespaceName(r)
+		credential := s.credentials.authenticate(r)
+		workflowID, runID := requestWorkflowID(r)
+		if credential == nil || !credential.allows(namespace) || workflowID == "" {
+			next(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		current := s.clusterFor(r)
+		cluster := s.clusters.locate(ctx, current, namespace, workflowID, runID)
+		if cluster != current {
+			log.Printf("Workflow %s found on cluster %s", workflowID, cluster.name)
+			w.Header().Set(clusterHeader, cluster.name)
+			r = r.WithContext(context.WithValue(r.Context(), requestClusterKey{}, cluster))
+		}
+		next(w, r)
+	}
+}
+
+// requestWorkflowID lee workflowId y runId de la query o, si no están, del
+// body JSON, que se deja intacto para el handler
+func requestWorkflowID(r *http.Request) (workflowID, runID string) {
+	query := r.URL.Query()
+	if workflowID = query.Get("workflowId"); workflowID != "" || r.Body == nil {
+		return workflowID, query.Get("runId")
+	}
+
+	data, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return "", ""
+	}
+	var body struct {
+		WorkflowID string `json:"workflowId"`
+		RunID      string `json:"runId"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return "", ""
+	}
+	return body.WorkflowID, body.RunID
+}
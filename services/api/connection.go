@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.temporal.io/sdk/client"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/contracts/temporalconn"
+)
+
+// Intervalos de la conexión con Temporal
+const (
+	// healthCheckInterval es cada cuánto se comprueba una conexión establecida
+	healthCheckInterval = 15 * time.Second
+	// healthCheckTimeout es el tiempo máximo de cada comprobación
+	healthCheckTimeout = 5 * time.Second
+)
+
+// defaultDialBackoff no tiene tiempo máximo sin conectar al arrancar: la API
+// sirve y responde not-ready mientras tanto (TEMPORAL_DIAL_MAX_WAIT lo fija)
+var defaultDialBackoff = temporalconn.Backoff{Initial: time.Second, Max: 30 * time.Second}
+
+// temporalConnection mantiene el cliente de Temporal y su estado. El cliente
+// es lazy: existe desde el arranque y los handlers lo usan siempre; run
+// reintenta hasta que el frontend responde y luego vigila la conexión, que
+// gRPC restablece por su cuenta si se pierde.
+type temporalConnection struct {
+	client   client.Client
+	hostPort string
+	backoff  temporalconn.Backoff
+	// interval es healthCheckInterval; los tests lo acortan
+	interval time.Duration
+	// onConnect se ejecuta la primera vez que la conexión queda establecida
+	onConnect func(ctx context.Context, c client.Client)
+
+	connected      atomic.Bool
+	dialSuccesses  atomic.Int64
+	dialFailures   atomic.Int64
+	reconnections  atomic.Int64
+	everConnected  bool
+	disconnectedAt time.Time
+}
+
+func newTemporalConnection(hostPort string, security temporalconn.Security, backoff temporalconn.Backoff) (*temporalConnection, error) {
+	options := client.Options{HostPort: hostPort, DataConverter: contracts.DataConverter()}
+	if err := security.Apply(&options); err != nil {
+		return nil, err
+	}
+	c, err := client.NewLazyClient(options)
+	if err != nil {
+		return nil, err
+	}
+	return &temporalConnection{client: c, hostPort: hostPort, backoff: backoff, interval: healthCheckInterval}, nil
+}
+
+// run bloquea hasta que ctx se cancela y devuelve nil. Si nunca conecta
+// dentro de MaxWait deja de reintentar y devuelve el error: la conexión
+// queda not-ready y quien llama decide si el proceso termina.
+func (t *temporalConnection) run(ctx context.Context) error {
+	t.disconnectedAt = time.Now()
+	attempt := 0
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		_, err := t.client.CheckHealth(checkCtx, &client.CheckHealthRequest{})
+		cancel()
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		wait := t.interval
+		if err == nil {
+			t.markConnected(ctx)
+			attempt = 0
+		} else {
+			attempt++
+			t.markDisconnected(err, attempt)
+			wait = t.backoff.Delay(attempt)
+			if !t.everConnected && t.backoff.MaxWait > 0 && time.Since(t.disconnectedAt)+wait > t.backoff.MaxWait {
+				return fmt.Errorf("unable to connect to Temporal at %s after %d attempts: %w", t.hostPort, attempt, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (t *temporalConnection) markConnected(ctx context.Context) {
+	if t.connected.Load() {
+		return
+	}
+	t.dialSuccesses.Add(1)
+	if t.everConnected {
+		t.reconnections.Add(1)
+		log.Printf("Connection to Temporal at %s restored after %s", t.hostPort, time.Since(t.disconnectedAt).Round(time.Second))
+	} else {
+		log.Printf("Successfully connected to Temporal server at %s", t.hostPort)
+		t.everConnected = true
+		if t.onConnect != nil {
+			t.onConnect(ctx, t.client)
+		}
+	}
+	t.connected.Store(true)
+}
+
+func (t *temporalConnection) markDisconnected(err error, attempt int) {
+	t.dialFailures.Add(1)
+	if t.connected.Swap(false) {
+		t.disconnectedAt = time.Now()
+		log.Printf("Lost connection to Temporal at %s: %v", t.hostPort, err)
+	}
+	log.Printf("Temporal at %s is not reachable (attempt %d): %v; retrying in %s", t.hostPort, attempt, err, t.backoff.Delay(attempt))
+}
+
+// Ready indica si Temporal responde
+func (t *temporalConnection) Ready() bool {
+	return t.connected.Load()
+}
+
+// requireTemporal responde 503 mientras no hay conexión con el cluster del
+// request, en lugar de dejar que cada llamada del cliente agote su timeout
+func (s *Server) requireTemporal(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cluster := s.clusterFor(r)
+		if !cluster.ready() {
+			w.Header().Set("Retry-After", "5")
+			respondWithError(w, http.StatusServiceUnavailable, "Temporal unavailable",
+				"Not connected to Temporal cluster "+cluster.name+", retry later")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// readyHandler responde 200 solo mientras hay conexión con el cluster por
+// defecto (o, con failover, con el secundario)
+func (s *Server) readyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clusters := map[string]string{}
+	for _, name := range s.clusters.names {
+		clusters[name] = "disconnected"
+		if s.clusters.clusters[name].ready() {
+			clusters[name] = "connected"
+		}
+	}
+	ready := clusters[s.clusters.defaultName] == "connected" ||
+		(s.clusters.failover != "" && clusters[s.clusters.failover] == "connected")
+
+	status := http.StatusOK
+	body := map[string]interface{}{"status": "ready", "clusters": clusters}
+	if !ready {
+		status = http.StatusServiceUnavailable
+		body["status"] = "not ready"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// metricsHandler expone el estado de las conexiones en formato de texto de
+// Prometheus, con el cluster como label
+func (s *Server) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintln(w, "# TYPE api_temporal_connected gauge")
+	for _, name := range s.clusters.names {
+		connected := 0
+		if s.clusters.clusters[name].ready() {
+			connected = 1
+		}
+		fmt.Fprintf(w, "api_temporal_connected{cluster=%q} %d\n", name, connected)
+	}
+	fmt.Fprintln(w, "# TYPE api_temporal_dial_attempts_total counter")
+	for _, name := range s.clusters.names {
+		connection := s.clusters.clusters[name].connection
+		if connection == nil {
+			continue
+		}
+		fmt.Fprintf(w, "api_temporal_dial_attempts_total{cluster=%q,result=\"failure\"} %d\n", name, connection.dialFailures.Load())
+		fmt.Fprintf(w, "api_temporal_dial_attempts_total{cluster=%q,result=\"success\"} %d\n", name, connection.dialSuccesses.Load())
+	}
+	fmt.Fprintln(w, "# TYPE api_temporal_reconnects_total counter")
+	for _, name := range s.clusters.names {
+		if connection := s.clusters.clusters[name].connection; connection != nil {
+			fmt.Fprintf(w, "api_temporal_reconnects_total{cluster=%q} %d\n", name, connection.reconnections.Load())
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
+
+	"github.com/temporal-aws-poc/contracts/temporalconn"
+)
+
+// healthClient responde los health checks con la secuencia results; al
+// agotarla repite el último
+type healthClient struct {
+	client.Client
+	mu      sync.Mutex
+	results []error
+}
+
+func (c *healthClient) CheckHealth(context.Context, *client.CheckHealthRequest) (*client.CheckHealthResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	err := c.results[0]
+	if len(c.results) > 1 {
+		c.results = c.results[1:]
+	}
+	return &client.CheckHealthResponse{}, err
+}
+
+func newTestConnection(results ...error) *temporalConnection {
+	return &temporalConnection{
+		client:   &healthClient{results: results},
+		hostPort: "temporal.test:7233",
+		backoff:  temporalconn.Backoff{Initial: time.Millisecond, Max: time.Millisecond, MaxWait: 50 * time.Millisecond},
+		interval: time.Millisecond,
+	}
+}
+
+var errUnavailable = errors.New("connection refused")
+
+func TestConnectionGivesUpWithoutFirstConnect(t *testing.T) {
+	connection := newTestConnection(errUnavailable)
+
+	err := connection.run(context.Background())
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errUnavailable)
+	assert.Contains(t, err.Error(), "unable to connect to Temporal at temporal.test:7233")
+	assert.False(t, connection.Ready())
+	assert.Positive(t, connection.dialFailures.Load())
+	assert.Zero(t, connection.dialSuccesses.Load())
+}
+
+func TestConnectionKeepsRetryingAfterLosingConnection(t *testing.T) {
+	// Conecta, pierde la conexión más allá de MaxWait y la recupera
+	failures := make([]error, 80)
+	for i := range failures {
+		failures[i] = errUnavailable
+	}
+	results := append(append([]error{nil}, failures...), nil)
+	connection := newTestConnection(results...)
+	connected := 0
+	connection.onConnect = func(context.Context, client.Client) { connected++ }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- connection.run(ctx) }()
+
+	require.Eventually(t, func() bool { return connection.reconnections.Load() == 1 }, 5*time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.True(t, connection.Ready())
+	assert.Equal(t, int64(2), connection.dialSuccesses.Load())
+	assert.Equal(t, int64(len(failures)), connection.dialFailures.Load())
+	assert.Equal(t, 1, connected)
+}
+
+func TestRegistryReportsClusterThatNeverConnects(t *testing.T) {
+	registry := &clusterRegistry{
+		clusters: map[string]*temporalCluster{
+			"primary":   {name: "primary", connection: newTestConnection(errUnavailable)},
+			"secondary": {name: "secondary", connection: newTestConnection(nil)},
+		},
+		names:       []string{"primary", "secondary"},
+		defaultName: "primary",
+	}
+	server := &Server{clusters: registry}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := registry.run(ctx)
+
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, errUnavailable)
+		assert.Contains(t, err.Error(), "cluster primary")
+	case <-time.After(5 * time.Second):
+		t.Fatal("dial error not reported")
+	}
+
+	// Sin el cluster por defecto la API responde not-ready
+	require.Eventually(t, registry.clusters["secondary"].ready, 5*time.Second, time.Millisecond)
+	recorder := httptest.NewRecorder()
+	server.readyHandler(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"primary":"disconnected"`)
+	assert.Contains(t, recorder.Body.String(), `"secondary":"connected"`)
+}
+
+func TestMetricsReportDialAttempts(t *testing.T) {
+	connection := newTestConnection(errUnavailable)
+	require.Error(t, connection.run(context.Background()))
+	server := &Server{clusters: &clusterRegistry{
+		clusters:    map[string]*temporalCluster{"primary": {name: "primary", connection: connection}},
+		names:       []string{"primary"},
+		defaultName: "primary",
+	}}
+
+	recorder := httptest.NewRecorder()
+	server.metricsHandler(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), `api_temporal_connected{cluster="primary"} 0`)
+	assert.Contains(t, string(body), "# TYPE api_temporal_dial_attempts_total counter")
+	assert.Contains(t, string(body), `api_temporal_dial_attempts_total{cluster="primary",result="success"} 0`)
+	assert.Regexp(t, `api_temporal_dial_attempts_total\{cluster="primary",result="failure"\} [1-9]`, string(body))
+	assert.Contains(t, string(body), `api_temporal_reconnects_total{cluster="primary"} 0`)
+}
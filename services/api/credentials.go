@@ -0,0 +1,120 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// allNamespaces en la lista de namespaces de una credencial permite todos
+const allNamespaces = "*"
+
+// Credential es un llamador de la API y los namespaces a los que accede. La
+// key no se guarda: solo su SHA-256 en hexadecimal
+// (echo -n "$KEY" | sha256sum).
+type Credential struct {
+	Name         string   `json:"name"`
+	APIKeySHA256 string   `json:"apiKeySha256"`
+	Namespaces   []string `json:"namespaces"`
+	// Admin habilita los endpoints /admin/namespaces
+	Admin bool `json:"admin,omitempty"`
+}
+
+// CredentialsConfig es el archivo de API_CREDENTIALS_FILE (YAML o JSON):
+//
+//	credentials:
+//	  - name: staging-ci
+//	    apiKeySha256: 5e88489...
+//	    namespaces: [staging]
+//	  - name: ops
+//	    apiKeySha256: 9f86d08...
+//	    namespaces: ["*"]
+//	    admin: true
+type CredentialsConfig struct {
+	Credentials []Credential `json:"credentials"`
+}
+
+// credentialStore autentica a los llamadores. Sin archivo de credenciales
+// todo llamador es anónimo, accede a los namespaces de API_NAMESPACES y no
+// es admin.
+type credentialStore struct {
+	credentials []Credential
+	anonymous   *Credential
+}
+
+func newAnonymousStore(namespaces []string) *credentialStore {
+	return &credentialStore{anonymous: &Credential{Name: "anonymous", Namespaces: namespaces}}
+}
+
+// loadCredentials lee y valida el archivo de credenciales
+func loadCredentials(path string) (*credentialStore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config CredentialsConfig
+	if err := contracts.DecodeYAMLStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	seen := map[string]bool{}
+	for i, credential := range config.Credentials {
+		if credential.Name == "" {
+			return nil, fmt.Errorf("credential %d: name is required", i)
+		}
+		if seen[credential.Name] {
+			return nil, fmt.Errorf("credential %s is declared twice", credential.Name)
+		}
+		seen[credential.Name] = true
+		if _, err := hex.DecodeString(credential.APIKeySHA256); err != nil || len(credential.APIKeySHA256) != sha256.Size*2 {
+			return nil, fmt.Errorf("credential %s: apiKeySha256 must be a hex SHA-256 digest", credential.Name)
+		}
+		if len(credential.Namespaces) == 0 {
+			return nil, fmt.Errorf("credential %s: at least one namespace is required", credential.Name)
+		}
+		config.Credentials[i].APIKeySHA256 = strings.ToLower(credential.APIKeySHA256)
+	}
+	return &credentialStore{credentials: config.Credentials}, nil
+}
+
+// authenticate devuelve la credencial de la API key del request
+// ("Authorization: Bearer <key>" o "X-API-Key: <key>"), o nil si no es válida
+func (s *credentialStore) authenticate(r *http.Request) *Credential {
+	if s.anonymous != nil {
+		return s.anonymous
+	}
+
+	key := r.Header.Get("X-API-Key")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		key = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if key == "" {
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	digest := []byte(hex.EncodeToString(sum[:]))
+	for i := range s.credentials {
+		if subtle.ConstantTimeCompare(digest, []byte(s.credentials[i].APIKeySHA256)) == 1 {
+			return &s.credentials[i]
+		}
+	}
+	return nil
+}
+
+// allows indica si la credencial accede al namespace
+func (c *Credential) allows(namespace string) bool {
+	for _, allowed := range c.Namespaces {
+		if allowed == allNamespaces || allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+
+	"go.temporal.io/sdk/temporal"
+)
+
+// workflowErrorCause busca el error que explica el fallo de un workflow: el
+// ApplicationError que devolvió la última activity fallida de la cadena
+// (ValidationError, PermanentError, TransientError, RateLimitedError).
+// Si no falló ninguna activity se usa el primer ApplicationError del workflow.
+func workflowErrorCause(err error) (errorType string, details interface{}) {
+	var cause *temporal.ApplicationError
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch e := err.(type) {
+		case *temporal.ActivityError:
+			// Los wrappers del workflow quedan por encima de la activity
+			cause = nil
+		case *temporal.ApplicationError:
+			if cause == nil {
+				cause = e
+			}
+		}
+	}
+	if cause == nil {
+		return "", nil
+	}
+
+	if cause.HasDetails() {
+		if err := cause.Details(&details); err != nil {
+			details = nil
+		}
+	}
+	return cause.Type(), details
+}
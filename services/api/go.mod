@@ -2,7 +2,13 @@ module github.com/temporal-aws-poc/api
 
 go 1.21
 
-require go.temporal.io/sdk v1.25.1
+require (
+	github.com/google/uuid v1.5.0
+	github.com/stretchr/testify v1.8.4
+	github.com/temporal-aws-poc/contracts v0.0.0
+	go.temporal.io/api v1.26.0
+	go.temporal.io/sdk v1.25.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -12,15 +18,12 @@ require (
 	github.com/gogo/status v1.1.1 // indirect
 	github.com/golang/mock v1.7.0-rc.1 // indirect
 	github.com/golang/protobuf v1.5.3 // indirect
-	github.com/google/uuid v1.5.0 // indirect
 	github.com/grpc-ecosystem/go-grpc-middleware v1.4.0 // indirect
 	github.com/grpc-ecosystem/grpc-gateway v1.16.0 // indirect
 	github.com/pborman/uuid v1.2.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/robfig/cron v1.2.0 // indirect
 	github.com/stretchr/objx v0.5.0 // indirect
-	github.com/stretchr/testify v1.8.4 // indirect
-	go.temporal.io/api v1.26.0 // indirect
 	go.uber.org/atomic v1.9.0 // indirect
 	golang.org/x/net v0.19.0 // indirect
 	golang.org/x/sys v0.15.0 // indirect
@@ -33,3 +36,6 @@ require (
 	google.golang.org/protobuf v1.32.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// contracts se comparte con el worker desde el mismo repositorio
+replace github.com/temporal-aws-poc/contracts => ../contracts
@@ -3,24 +3,67 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"regexp"
 	"time"
 
 	"go.temporal.io/sdk/client"
+
+	"github.com/temporal-aws-poc/contracts"
 )
 
+// activityProfilePattern es el formato de los nombres de perfil ("slow-io").
+// Si el perfil no existe en el worker el workflow falla al iniciar.
+var activityProfilePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// inputWorkflowTypes son los workflows que se inician con workflowType; su
+// input es el JSON de input como string. DSLWorkflow y BatchWorkflow se
+// inician con pipeline y batch.
+var inputWorkflowTypes = map[string]bool{
+	contracts.WorkflowA: true,
+	contracts.WorkflowB: true,
+	contracts.WorkflowC: true,
+	contracts.WorkflowD: true,
+}
+
 // StartWorkflowRequest define la estructura del payload para iniciar un workflow
 type StartWorkflowRequest struct {
 	WorkflowID string                 `json:"workflowId"`
 	Input      map[string]interface{} `json:"input"`
+	// WorkflowType es opcional; WorkflowA por defecto (ver inputWorkflowTypes)
+	WorkflowType string `json:"workflowType,omitempty"`
+	// ChildWorkflowIDTemplate es opcional; plantilla text/template para los IDs
+	// de los child workflows (campos: WorkflowID, RunID, WorkflowType, Step)
+	ChildWorkflowIDTemplate string `json:"childWorkflowIdTemplate,omitempty"`
+	// Approval es opcional; activa la aprobación manual de WorkflowA (los
+	// campos vacíos toman los valores por defecto del worker)
+	Approval *contracts.ApprovalPolicy `json:"approval,omitempty"`
+	// Pipeline es opcional; si se indica se ejecuta DSLWorkflow con la
+	// definición de ese nombre en lugar de WorkflowA
+	Pipeline string `json:"pipeline,omitempty"`
+	// ActivityProfile es opcional; perfil de ActivityOptions para todas las
+	// activities de la ejecución ("default", "slow-io", "critical")
+	ActivityProfile string `json:"activityProfile,omitempty"`
+	// Batch es opcional; si se indica se ejecuta BatchWorkflow con sus items
+	Batch *BatchRequest `json:"batch,omitempty"`
+	// SLA es opcional; plazo de la ejecución ("30m"). Si se vence el workflow
+	// escala y sigue, y su resultado indica si se cumplió y por cuánto
+	SLA string `json:"sla,omitempty"`
+	// SearchAttributes es opcional; claves de negocio para buscar la ejecución
+	// ("customerId", "orderId", "tenant"), validadas contra el schema declarado
+	SearchAttributes map[string]interface{} `json:"searchAttributes,omitempty"`
 }
 
 // StartWorkflowResponse define la respuesta al iniciar un workflow
 type StartWorkflowResponse struct {
 	WorkflowID string `json:"workflowId"`
 	RunID      string `json:"runId"`
-	Message    string `json:"message"`
+	// Cluster es el cluster de Temporal donde se inició (con failover puede
+	// no ser el por defecto)
+	Cluster string `json:"cluster,omitempty"`
+	Message string `json:"message"`
 }
 
 // WorkflowStatusResponse define la respuesta al consultar el estado
@@ -30,6 +73,14 @@ type WorkflowStatusResponse struct {
 	Status     string `json:"status"`
 	Result     string `json:"result,omitempty"`
 	Error      string `json:"error,omitempty"`
+	// Tipo y detalle de la causa raíz cuando el workflow falló
+	ErrorType    string      `json:"errorType,omitempty"`
+	ErrorDetails interface{} `json:"errorDetails,omitempty"`
+	// Relación padre/hijos del workflow consultado
+	Parent   *WorkflowRef  `json:"parent,omitempty"`
+	Children []WorkflowRef `json:"children,omitempty"`
+	// Search attributes declarados, incluido el paso en curso (currentStep)
+	SearchAttributes map[string]interface{} `json:"searchAttributes,omitempty"`
 }
 
 // ErrorResponse define el formato de error estándar
@@ -73,10 +124,90 @@ func (s *Server) startWorkflowHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// La plantilla se prueba con datos de ejemplo: un campo desconocido
+	// fallaría recién en el worker, al lanzar el primer hijo
+	if req.ChildWorkflowIDTemplate != "" {
+		sample := contracts.ChildWorkflowIDData{
+			WorkflowID:   req.WorkflowID,
+			RunID:        "00000000-0000-0000-0000-000000000000",
+			WorkflowType: contracts.WorkflowA,
+			Step:         "step",
+		}
+		if _, err := contracts.RenderChildWorkflowID(req.ChildWorkflowIDTemplate, sample); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid childWorkflowIdTemplate", err.Error())
+			return
+		}
+	}
+
+	if req.Approval != nil {
+		if err := validateApprovalPolicy(*req.Approval); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid approval policy", err.Error())
+			return
+		}
+	}
+
+	if req.Pipeline != "" && req.Batch != nil {
+		respondWithError(w, http.StatusBadRequest, "pipeline and batch are mutually exclusive", "")
+		return
+	}
+
+	if req.WorkflowType != "" {
+		if req.Pipeline != "" || req.Batch != nil {
+			respondWithError(w, http.StatusBadRequest, "workflowType is mutually exclusive with pipeline and batch", "")
+			return
+		}
+		if !inputWorkflowTypes[req.WorkflowType] {
+			respondWithError(w, http.StatusBadRequest, "Invalid workflowType",
+				fmt.Sprintf("unknown workflow type %q; start %s with pipeline and %s with batch", req.WorkflowType, contracts.DSLWorkflow, contracts.BatchWorkflow))
+			return
+		}
+	}
+
+	if req.Batch != nil {
+		if err := req.Batch.Validate(); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid batch", err.Error())
+			return
+		}
+	}
+
+	if req.SLA != "" {
+		if d, err := time.ParseDuration(req.SLA); err != nil || d <= 0 {
+			respondWithError(w, http.StatusBadRequest, "Invalid sla", "sla must be a positive duration such as 30m or 2h")
+			return
+		}
+	}
+
+	if req.ActivityProfile != "" && !activityProfilePattern.MatchString(req.ActivityProfile) {
+		respondWithError(w, http.StatusBadRequest, "Invalid activityProfile", "profile names use lowercase letters, digits and hyphens")
+		return
+	}
+
+	searchAttributes, err := validateSearchAttributes(req.SearchAttributes)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid searchAttributes", err.Error())
+		return
+	}
+
 	// Opciones del workflow
 	workflowOptions := client.StartWorkflowOptions{
 		ID:        req.WorkflowID,
-		TaskQueue: "hello-world-queue",
+		TaskQueue: contracts.DefaultTaskQueue,
+		Memo:      map[string]interface{}{},
+	}
+	if len(searchAttributes) > 0 {
+		workflowOptions.SearchAttributes = searchAttributes
+	}
+	if req.ChildWorkflowIDTemplate != "" {
+		workflowOptions.Memo[contracts.ChildWorkflowIDTemplateMemoKey] = req.ChildWorkflowIDTemplate
+	}
+	if req.Approval != nil {
+		workflowOptions.Memo[contracts.ApprovalPolicyMemoKey] = req.Approval
+	}
+	if req.ActivityProfile != "" {
+		workflowOptions.Memo[contracts.ActivityProfileMemoKey] = req.ActivityProfile
+	}
+	if req.SLA != "" {
+		workflowOptions.Memo[contracts.SLAMemoKey] = contracts.SLAPolicy{Duration: req.SLA}
 	}
 
 	// Iniciar el workflow
@@ -92,11 +223,33 @@ func (s *Server) startWorkflowHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	inputStr := string(inputBytes)
 
-	workflowRun, err := s.temporalClient.ExecuteWorkflow(
+	// Por defecto WorkflowA (o el workflowType pedido); con pipeline se
+	// interpreta la definición declarativa y con batch se procesa cada item
+	// con BatchWorkflow
+	var workflowType string
+	var workflowInput interface{}
+	if req.Pipeline != "" {
+		workflowType = contracts.DSLWorkflow
+		workflowInput = contracts.PipelineInput{Pipeline: req.Pipeline, Data: inputStr}
+	} else if req.Batch != nil {
+		workflowType = contracts.BatchWorkflow
+		if workflowInput, err = req.Batch.toInput(req.WorkflowID); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid batch items", err.Error())
+			return
+		}
+	} else {
+		workflowType = contracts.WorkflowA
+		if req.WorkflowType != "" {
+			workflowType = req.WorkflowType
+		}
+		workflowInput = inputStr // Pasar el input como string JSON
+	}
+
+	workflowRun, err := s.backendFor(r).ExecuteWorkflow(
 		ctx,
 		workflowOptions,
-		"WorkflowA", // Nombre del workflow
-		inputStr,    // Pasar el input como string JSON
+		workflowType,
+		workflowInput,
 	)
 	if err != nil {
 		log.Printf("Error starting workflow: %v", err)
@@ -104,12 +257,13 @@ func (s *Server) startWorkflowHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("Started workflow - ID: %s, RunID: %s", workflowRun.GetID(), workflowRun.GetRunID())
+	log.Printf("Started workflow %s - ID: %s, RunID: %s", workflowType, workflowRun.GetID(), workflowRun.GetRunID())
 
 	// Respuesta exitosa
 	response := StartWorkflowResponse{
 		WorkflowID: workflowRun.GetID(),
 		RunID:      workflowRun.GetRunID(),
+		Cluster:    s.clusterFor(r).name,
 		Message:    "Workflow started successfully",
 	}
 
@@ -137,7 +291,8 @@ func (s *Server) workflowStatusHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	workflowRun := s.temporalClient.GetWorkflow(ctx, workflowID, runID)
+	backend := s.backendFor(r)
+	workflowRun := backend.GetWorkflow(ctx, workflowID, runID)
 
 	// Intentar obtener el resultado (esto espera si el workflow está corriendo)
 	var result string
@@ -155,6 +310,7 @@ func (s *Server) workflowStatusHandler(w http.ResponseWriter, r *http.Request) {
 		} else {
 			response.Status = "failed"
 			response.Error = err.Error()
+			response.ErrorType, response.ErrorDetails = workflowErrorCause(err)
 		}
 	} else {
 		response.Status = "completed"
@@ -165,9 +321,26 @@ func (s *Server) workflowStatusHandler(w http.ResponseWriter, r *http.Request) {
 	describeCtx, describeCancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer describeCancel()
 
-	description, err := s.temporalClient.DescribeWorkflowExecution(describeCtx, workflowID, runID)
+	description, err := backend.DescribeWorkflowExecution(describeCtx, workflowID, runID)
 	if err == nil {
 		response.Status = description.WorkflowExecutionInfo.Status.String()
+		if response.RunID == "" {
+			response.RunID = description.WorkflowExecutionInfo.GetExecution().GetRunId()
+		}
+		response.SearchAttributes = decodeSearchAttributes(description.WorkflowExecutionInfo.GetSearchAttributes())
+		if parent := description.WorkflowExecutionInfo.GetParentExecution(); parent != nil {
+			response.Parent = &WorkflowRef{
+				WorkflowID: parent.GetWorkflowId(),
+				RunID:      parent.GetRunId(),
+			}
+		}
+	}
+
+	children, err := childWorkflows(describeCtx, backend, workflowID, response.RunID)
+	if err != nil {
+		log.Printf("Error reading child workflows for %s: %v", workflowID, err)
+	} else {
+		response.Children = children
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// fastSteps recorre los tres pasos por defecto en unos milisegundos
+var fastSteps = []SimulatedStep{
+	{Name: contracts.Activity1, Duration: "10ms"},
+	{Name: contracts.Activity2, Duration: "10ms"},
+	{Name: contracts.Activity3, Duration: "10ms"},
+}
+
+// slowSteps deja el workflow en curso durante todo el test
+var slowSteps = []SimulatedStep{
+	{Name: contracts.Activity1, Duration: "10ms"},
+	{Name: contracts.Activity2, Duration: "1h"},
+}
+
+// newTestAPI sirve el API con el simulador como backend
+func newTestAPI(t *testing.T, config SimulatorConfig) string {
+	t.Helper()
+	if config.Default == nil {
+		config.Default = &SimulatedWorkflow{Steps: fastSteps}
+	}
+	server := &Server{
+		clusters:         newSimulatorRegistry(config),
+		defaultNamespace: "default",
+		credentials:      newAnonymousStore([]string{"default", "staging"}),
+	}
+	httpServer := httptest.NewServer(server.routes())
+	t.Cleanup(httpServer.Close)
+	t.Cleanup(server.clusters.close)
+	return httpServer.URL
+}
+
+// call hace un request con body JSON (si no es nil), decodifica la respuesta
+// en out y devuelve el código HTTP
+func call(t *testing.T, method, url string, body, out interface{}) int {
+	t.Helper()
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+func startWorkflow(t *testing.T, api string, req StartWorkflowRequest) StartWorkflowResponse {
+	t.Helper()
+	var resp StartWorkflowResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodPost, api+"/workflows/start", req, &resp))
+	return resp
+}
+
+func TestStartAndStatusWithSimulator(t *testing.T) {
+	api := newTestAPI(t, SimulatorConfig{})
+
+	started := startWorkflow(t, api, StartWorkflowRequest{
+		WorkflowID:       "order-1",
+		Input:            map[string]interface{}{"amount": 10},
+		SearchAttributes: map[string]interface{}{"customerId": "C-42"},
+	})
+	assert.Equal(t, "order-1", started.WorkflowID)
+	assert.NotEmpty(t, started.RunID)
+	assert.Equal(t, "default", started.Cluster)
+
+	// El estado espera a que el workflow termine
+	var status WorkflowStatusResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/status?workflowId=order-1", nil, &status))
+	assert.Equal(t, "Completed", status.Status)
+	assert.Equal(t, "WorkflowA completed (simulated)", status.Result)
+	assert.Equal(t, started.RunID, status.RunID)
+	assert.Equal(t, map[string]interface{}{"customerId": "C-42", "currentStep": contracts.Activity3}, status.SearchAttributes)
+}
+
+func TestStatusOfFailedWorkflow(t *testing.T) {
+	api := newTestAPI(t, SimulatorConfig{Workflows: map[string]SimulatedWorkflow{
+		contracts.WorkflowB: {Steps: fastSteps, Status: "Failed", Error: "payment declined", ErrorType: "PaymentDeclined"},
+	}})
+
+	startWorkflow(t, api, StartWorkflowRequest{WorkflowID: "order-2", WorkflowType: contracts.WorkflowB})
+
+	var status WorkflowStatusResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/status?workflowId=order-2", nil, &status))
+	assert.Equal(t, "Failed", status.Status)
+	assert.Equal(t, "PaymentDeclined", status.ErrorType)
+	assert.Contains(t, status.Error, "payment declined")
+
+	var history HistoryResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/history?workflowId=order-2", nil, &history))
+	require.True(t, history.Closed)
+	last := history.Events[len(history.Events)-2:]
+	assert.Equal(t, "ActivityTaskFailed", last[0].EventType)
+	assert.Equal(t, "Activity3: payment declined", last[0].Details)
+	assert.Equal(t, "WorkflowExecutionFailed", last[1].EventType)
+}
+
+func TestHistoryFollowsSteps(t *testing.T) {
+	api := newTestAPI(t, SimulatorConfig{})
+
+	startWorkflow(t, api, StartWorkflowRequest{WorkflowID: "order-3"})
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/status?workflowId=order-3", nil, nil))
+
+	var history HistoryResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/history?workflowId=order-3", nil, &history))
+	assert.True(t, history.Closed)
+	var steps []string
+	for _, event := range history.Events {
+		if event.EventType == "ActivityTaskCompleted" {
+			steps = append(steps, event.Details)
+		}
+	}
+	assert.Equal(t, []string{contracts.Activity1, contracts.Activity2, contracts.Activity3}, steps)
+	assert.Equal(t, "WorkflowExecutionStarted", history.Events[0].EventType)
+	assert.Equal(t, "WorkflowExecutionCompleted", history.Events[len(history.Events)-1].EventType)
+
+	// Con afterEventId solo llegan los eventos posteriores
+	var tail HistoryResponse
+	after := history.Events[len(history.Events)-2].EventID
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/history?workflowId=order-3&afterEventId="+strconv.FormatInt(after, 10), nil, &tail))
+	require.Len(t, tail.Events, 1)
+	assert.Equal(t, "WorkflowExecutionCompleted", tail.Events[0].EventType)
+}
+
+func TestListFiltersWorkflows(t *testing.T) {
+	api := newTestAPI(t, SimulatorConfig{Workflows: map[string]SimulatedWorkflow{
+		contracts.WorkflowC: {Steps: slowSteps},
+	}})
+
+	startWorkflow(t, api, StartWorkflowRequest{WorkflowID: "done-1", SearchAttributes: map[string]interface{}{"customerId": "C-1"}})
+	startWorkflow(t, api, StartWorkflowRequest{WorkflowID: "slow-1", WorkflowType: contracts.WorkflowC, SearchAttributes: map[string]interface{}{"customerId": "C-1"}})
+	startWorkflow(t, api, StartWorkflowRequest{WorkflowID: "slow-2", WorkflowType: contracts.WorkflowC, SearchAttributes: map[string]interface{}{"customerId": "C-2"}})
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/status?workflowId=done-1", nil, nil))
+	time.Sleep(20 * time.Millisecond)
+
+	ids := func(query string) []string {
+		var list ListWorkflowsResponse
+		require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows?"+query, nil, &list))
+		var ids []string
+		for _, workflow := range list.Workflows {
+			ids = append(ids, workflow.WorkflowID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"slow-2", "slow-1", "done-1"}, ids(""))
+	assert.Equal(t, []string{"slow-2", "slow-1"}, ids("status=Running"))
+	assert.Equal(t, []string{"done-1"}, ids("status=Completed"))
+	assert.Equal(t, []string{"slow-1", "done-1"}, ids("customerId=C-1"))
+	assert.Equal(t, []string{"slow-2", "slow-1"}, ids("currentStep=Activity2"))
+	assert.Equal(t, []string{"done-1"}, ids("workflowType=WorkflowA&customerId=C-1"))
+
+	// Paginado
+	var page ListWorkflowsResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows?pageSize=2", nil, &page))
+	require.Len(t, page.Workflows, 2)
+	require.NotEmpty(t, page.NextPageToken)
+	var last ListWorkflowsResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows?pageSize=2&nextPageToken="+page.NextPageToken, nil, &last))
+	require.Len(t, last.Workflows, 1)
+	assert.Equal(t, "done-1", last.Workflows[0].WorkflowID)
+	assert.Empty(t, last.NextPageToken)
+}
+
+func TestCancelRunningWorkflow(t *testing.T) {
+	api := newTestAPI(t, SimulatorConfig{Default: &SimulatedWorkflow{Steps: slowSteps}})
+
+	startWorkflow(t, api, StartWorkflowRequest{WorkflowID: "order-4"})
+	require.Equal(t, http.StatusOK, call(t, http.MethodPost, api+"/workflows/cancel", CancelWorkflowRequest{WorkflowID: "order-4"}, nil))
+
+	var status WorkflowStatusResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/status?workflowId=order-4", nil, &status))
+	assert.Equal(t, "Canceled", status.Status)
+
+	var history HistoryResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/history?workflowId=order-4", nil, &history))
+	var types []string
+	for _, event := range history.Events {
+		types = append(types, event.EventType)
+	}
+	assert.Contains(t, types, "WorkflowExecutionCancelRequested")
+	assert.Equal(t, "WorkflowExecutionCanceled", types[len(types)-1])
+
+	// Un workflow cerrado no se puede cancelar de nuevo
+	assert.Equal(t, http.StatusInternalServerError, call(t, http.MethodPost, api+"/workflows/cancel", CancelWorkflowRequest{WorkflowID: "order-4"}, nil))
+}
+
+func TestSignalQueryAndApprovals(t *testing.T) {
+	api := newTestAPI(t, SimulatorConfig{Workflows: map[string]SimulatedWorkflow{
+		contracts.WorkflowA: {
+			Steps:   slowSteps,
+			Queries: map[string]interface{}{contracts.ApprovalQueryName: map[string]interface{}{"status": contracts.ApprovalPending, "field": "amount", "value": 1500}},
+		},
+		contracts.BatchWorkflow: {
+			Steps:   slowSteps,
+			Queries: map[string]interface{}{contracts.BatchProgressQueryName: contracts.BatchProgress{BatchID: "batch-1", Total: 10, Processed: 4}},
+		},
+	}})
+
+	startWorkflow(t, api, StartWorkflowRequest{WorkflowID: "order-5"})
+	startWorkflow(t, api, StartWorkflowRequest{WorkflowID: "batch-1", Batch: &BatchRequest{Items: []interface{}{"a", "b"}}})
+	startWorkflow(t, api, StartWorkflowRequest{WorkflowID: "order-6"})
+
+	// El listado pagina y solo trae las ejecuciones con ApprovalStatus pendiente
+	var page ListApprovalsResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/approvals?pageSize=1", nil, &page))
+	require.Len(t, page.Approvals, 1)
+	assert.Equal(t, "order-6", page.Approvals[0].WorkflowID)
+	require.NotEmpty(t, page.NextPageToken)
+
+	var next ListApprovalsResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/approvals?pageSize=1&nextPageToken="+page.NextPageToken, nil, &next))
+	require.Len(t, next.Approvals, 1)
+	assert.Equal(t, "order-5", next.Approvals[0].WorkflowID)
+	assert.Equal(t, contracts.ApprovalPending, next.Approvals[0].Approval.Status)
+	assert.Equal(t, 1500.0, next.Approvals[0].Approval.Value)
+	assert.Empty(t, next.NextPageToken)
+
+	assert.Equal(t, http.StatusBadRequest, call(t, http.MethodGet, api+"/approvals?pageSize=0", nil, nil))
+
+	decision := ApprovalDecisionRequest{WorkflowID: "order-5", Approved: true, Approver: "ana"}
+	require.Equal(t, http.StatusOK, call(t, http.MethodPost, api+"/approvals/decision", decision, nil))
+	var history HistoryResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/history?workflowId=order-5", nil, &history))
+	var signals []string
+	for _, event := range history.Events {
+		if event.EventType == "WorkflowExecutionSignaled" {
+			signals = append(signals, event.Details)
+		}
+	}
+	assert.Equal(t, []string{contracts.ApprovalSignalName}, signals)
+
+	var progress contracts.BatchProgress
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/batches/progress?workflowId=batch-1", nil, &progress))
+	assert.Equal(t, contracts.BatchProgress{BatchID: "batch-1", Total: 10, Processed: 4}, progress)
+
+	// Las queries sin respuesta configurada fallan como en un workflow que no
+	// las registra
+	query := QueryWorkflowRequest{WorkflowID: "order-5", QueryType: "unknown"}
+	assert.Equal(t, http.StatusInternalServerError, call(t, http.MethodPost, api+"/workflows/query", query, nil))
+}
+
+func TestStartValidationAndDuplicates(t *testing.T) {
+	api := newTestAPI(t, SimulatorConfig{Default: &SimulatedWorkflow{Steps: slowSteps}})
+
+	var errResp ErrorResponse
+	assert.Equal(t, http.StatusBadRequest, call(t, http.MethodPost, api+"/workflows/start", StartWorkflowRequest{}, &errResp))
+	assert.Equal(t, "workflowId is required", errResp.Error)
+	assert.Equal(t, http.StatusBadRequest, call(t, http.MethodPost, api+"/workflows/start", StartWorkflowRequest{WorkflowID: "x", WorkflowType: "Nope"}, &errResp))
+	assert.Equal(t, "Invalid workflowType", errResp.Error)
+	assert.Equal(t, http.StatusBadRequest, call(t, http.MethodPost, api+"/workflows/start",
+		StartWorkflowRequest{WorkflowID: "x", SearchAttributes: map[string]interface{}{"currentStep": "Activity1"}}, &errResp))
+	assert.Equal(t, "Invalid searchAttributes", errResp.Error)
+	// Las plantillas de IDs de hijos se ejecutan, no solo se parsean
+	assert.Equal(t, http.StatusBadRequest, call(t, http.MethodPost, api+"/workflows/start",
+		StartWorkflowRequest{WorkflowID: "x", ChildWorkflowIDTemplate: "{{.Foo}}"}, &errResp))
+	assert.Equal(t, "Invalid childWorkflowIdTemplate", errResp.Error)
+	assert.Equal(t, http.StatusBadRequest, call(t, http.MethodPost, api+"/workflows/start",
+		StartWorkflowRequest{WorkflowID: "x", ChildWorkflowIDTemplate: "{{if .Step}}{{end}}"}, &errResp))
+	// Un batch solo puede ejecutar las activities permitidas por item
+	assert.Equal(t, http.StatusBadRequest, call(t, http.MethodPost, api+"/workflows/start",
+		StartWorkflowRequest{WorkflowID: "x", Batch: &BatchRequest{Items: []interface{}{"a"}, Activity: "CompensateActivity1"}}, &errResp))
+	assert.Equal(t, "Invalid batch", errResp.Error)
+
+	// Un workflow ID en curso no se puede volver a iniciar
+	startWorkflow(t, api, StartWorkflowRequest{WorkflowID: "order-6"})
+	assert.Equal(t, http.StatusInternalServerError, call(t, http.MethodPost, api+"/workflows/start", StartWorkflowRequest{WorkflowID: "order-6"}, &errResp))
+	assert.Equal(t, "Failed to start workflow", errResp.Error)
+}
+
+func TestNamespacesAreIsolated(t *testing.T) {
+	api := newTestAPI(t, SimulatorConfig{})
+
+	startWorkflow(t, api+"/namespaces/staging", StartWorkflowRequest{WorkflowID: "order-7"})
+
+	var list ListWorkflowsResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows", nil, &list))
+	assert.Empty(t, list.Workflows)
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/namespaces/staging/workflows", nil, &list))
+	require.Len(t, list.Workflows, 1)
+	assert.Equal(t, "order-7", list.Workflows[0].WorkflowID)
+
+	assert.Equal(t, http.StatusForbidden, call(t, http.MethodGet, api+"/namespaces/prod/workflows", nil, nil))
+}
+
+func TestReadyWithSimulator(t *testing.T) {
+	api := newTestAPI(t, SimulatorConfig{})
+
+	var ready map[string]interface{}
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/ready", nil, &ready))
+	assert.Equal(t, "ready", ready["status"])
+}
+
+func TestFollowUpCallsFindWorkflowOnFailoverCluster(t *testing.T) {
+	config := SimulatorConfig{Default: &SimulatedWorkflow{Steps: slowSteps}}
+	server := &Server{
+		clusters: &clusterRegistry{
+			clusters: map[string]*temporalCluster{
+				"primary":   newSimulatorCluster("primary", config),
+				"secondary": newSimulatorCluster("secondary", config),
+			},
+			names:       []string{"primary", "secondary"},
+			defaultName: "primary",
+			failover:    "secondary",
+		},
+		defaultNamespace: "default",
+		credentials:      newAnonymousStore([]string{"default"}),
+	}
+	httpServer := httptest.NewServer(server.routes())
+	t.Cleanup(httpServer.Close)
+	t.Cleanup(server.clusters.close)
+	api := httpServer.URL
+
+	// Inicio durante un failover: el workflow queda en el secundario
+	body, err := json.Marshal(StartWorkflowRequest{WorkflowID: "order-8"})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, api+"/workflows/start", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set(clusterHeader, "secondary")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Sin header, el historial y la cancelación lo encuentran en el secundario
+	resp, err = http.Get(api + "/workflows/history?workflowId=order-8")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "secondary", resp.Header.Get(clusterHeader))
+
+	require.Equal(t, http.StatusOK, call(t, http.MethodPost, api+"/workflows/cancel", CancelWorkflowRequest{WorkflowID: "order-8"}, nil))
+	var status WorkflowStatusResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/status?workflowId=order-8", nil, &status))
+	assert.Equal(t, "Canceled", status.Status)
+
+	// Un workflow que no existe en ningún cluster sigue respondiendo el error
+	// del cluster por defecto
+	resp, err = http.Get(api + "/workflows/history?workflowId=missing")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.NotEqual(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "primary", resp.Header.Get(clusterHeader))
+}
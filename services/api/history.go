@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/workflowservice/v1"
+)
+
+// HistoryEvent es un evento del historial, resumido para mostrarlo
+type HistoryEvent struct {
+	EventID   int64     `json:"eventId"`
+	EventTime time.Time `json:"eventTime"`
+	EventType string    `json:"eventType"`
+	// Details es un resumen del evento: la activity o el child workflow al
+	// que se refiere, la señal recibida o el mensaje de error
+	Details string `json:"details,omitempty"`
+}
+
+// HistoryResponse es una página del historial de una ejecución. Closed
+// indica que el último evento cierra la ejecución; NextPageToken vacío indica
+// que no hay más eventos (sin wait, que no hay más por ahora).
+type HistoryResponse struct {
+	WorkflowID    string         `json:"workflowId"`
+	RunID         string         `json:"runId,omitempty"`
+	Events        []HistoryEvent `json:"events"`
+	Closed        bool           `json:"closed"`
+	NextPageToken string         `json:"nextPageToken,omitempty"`
+}
+
+// historyPageSize es el máximo de eventos por respuesta
+const historyPageSize = 500
+
+// historyCursor es el nextPageToken del historial: el token del servidor y
+// los nombres de las activities programadas que no cerraron todavía, que los
+// eventos de páginas siguientes necesitan para su detalle
+type historyCursor struct {
+	Token      []byte           `json:"token"`
+	Activities map[int64]string `json:"activities,omitempty"`
+}
+
+// workflowHistoryHandler devuelve el historial de una ejecución de a una
+// página del servidor; nextPageToken sigue desde donde quedó la anterior. Con
+// wait=true una ejecución en curso siempre devuelve token y el request con
+// ese token espera eventos nuevos, para seguirla sin volver a leer el
+// historial. afterEventId descarta los eventos anteriores de la página.
+func (s *Server) workflowHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	workflowID := r.URL.Query().Get("workflowId")
+	runID := r.URL.Query().Get("runId")
+	if workflowID == "" {
+		respondWithError(w, http.StatusBadRequest, "workflowId parameter is required", "")
+		return
+	}
+	var afterEventID int64
+	if v := r.URL.Query().Get("afterEventId"); v != "" {
+		var err error
+		if afterEventID, err = strconv.ParseInt(v, 10, 64); err != nil || afterEventID < 0 {
+			respondWithError(w, http.StatusBadRequest, "Invalid afterEventId", "afterEventId must be a non-negative event ID")
+			return
+		}
+	}
+	wait := r.URL.Query().Get("wait") == "true"
+	cursor := historyCursor{}
+	if v := r.URL.Query().Get("nextPageToken"); v != "" {
+		data, err := base64.URLEncoding.DecodeString(v)
+		if err == nil {
+			err = json.Unmarshal(data, &cursor)
+		}
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid nextPageToken", err.Error())
+			return
+		}
+	}
+	if cursor.Activities == nil {
+		cursor.Activities = map[int64]string{}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response := HistoryResponse{WorkflowID: workflowID, RunID: runID, Events: []HistoryEvent{}}
+	page, err := s.backendFor(r).GetWorkflowHistoryPage(ctx, &workflowservice.GetWorkflowExecutionHistoryRequest{
+		Execution:       &commonpb.WorkflowExecution{WorkflowId: workflowID, RunId: runID},
+		MaximumPageSize: historyPageSize,
+		NextPageToken:   cursor.Token,
+		WaitNewEvent:    wait,
+	})
+	switch {
+	case err != nil && wait && ctx.Err() == context.DeadlineExceeded:
+		// Sin eventos nuevos durante la espera: el mismo token sigue valiendo
+		page = &workflowservice.GetWorkflowExecutionHistoryResponse{NextPageToken: cursor.Token}
+	case err != nil:
+		log.Printf("Error reading history of %s: %v", workflowID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to read workflow history", err.Error())
+		return
+	}
+
+	for _, event := range page.GetHistory().GetEvents() {
+		if attrs := event.GetActivityTaskScheduledEventAttributes(); attrs != nil {
+			cursor.Activities[event.GetEventId()] = attrs.GetActivityType().GetName()
+		}
+		response.Closed = isCloseEvent(event.GetEventType())
+		details := eventDetails(event, cursor.Activities)
+		if scheduledEventID, ok := closedActivity(event); ok {
+			delete(cursor.Activities, scheduledEventID)
+		}
+		if event.GetEventId() <= afterEventID {
+			continue
+		}
+
+		summary := HistoryEvent{
+			EventID:   event.GetEventId(),
+			EventType: event.GetEventType().String(),
+			Details:   details,
+		}
+		if event.GetEventTime() != nil {
+			summary.EventTime = *event.GetEventTime()
+		}
+		response.Events = append(response.Events, summary)
+	}
+
+	if token := page.GetNextPageToken(); len(token) > 0 {
+		cursor.Token = token
+		data, err := json.Marshal(cursor)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to encode nextPageToken", err.Error())
+			return
+		}
+		response.NextPageToken = base64.URLEncoding.EncodeToString(data)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// closedActivity devuelve el evento que programó la activity que el evento
+// cierra
+func closedActivity(event *historypb.HistoryEvent) (int64, bool) {
+	switch event.GetEventType() {
+	case enumspb.EVENT_TYPE_ACTIVITY_TASK_COMPLETED:
+		return event.GetActivityTaskCompletedEventAttributes().GetScheduledEventId(), true
+	case enumspb.EVENT_TYPE_ACTIVITY_TASK_FAILED:
+		return event.GetActivityTaskFailedEventAttributes().GetScheduledEventId(), true
+	case enumspb.EVENT_TYPE_ACTIVITY_TASK_TIMED_OUT:
+		return event.GetActivityTaskTimedOutEventAttributes().GetScheduledEventId(), true
+	case enumspb.EVENT_TYPE_ACTIVITY_TASK_CANCELED:
+		return event.GetActivityTaskCanceledEventAttributes().GetScheduledEventId(), true
+	}
+	return 0, false
+}
+
+// eventDetails resume los eventos que sirven para seguir el avance de una
+// ejecución; el resto no lleva detalle
+func eventDetails(event *historypb.HistoryEvent, activityNames map[int64]string) string {
+	switch event.GetEventType() {
+	case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED:
+		return event.GetWorkflowExecutionStartedEventAttributes().GetWorkflowType().GetName()
+	case enumspb.EVENT_TYPE_ACTIVITY_TASK_SCHEDULED:
+		return activityNames[event.GetEventId()]
+	case enumspb.EVENT_TYPE_ACTIVITY_TASK_STARTED:
+		attrs := event.GetActivityTaskStartedEventAttributes()
+		return fmt.Sprintf("%s (attempt %d)", activityNames[attrs.GetScheduledEventId()], attrs.GetAttempt())
+	case enumspb.EVENT_TYPE_ACTIVITY_TASK_COMPLETED:
+		return activityNames[event.GetActivityTaskCompletedEventAttributes().GetScheduledEventId()]
+	case enumspb.EVENT_TYPE_ACTIVITY_TASK_FAILED:
+		attrs := event.GetActivityTaskFailedEventAttributes()
+		return fmt.Sprintf("%s: %s", activityNames[attrs.GetScheduledEventId()], attrs.GetFailure().GetMessage())
+	case enumspb.EVENT_TYPE_ACTIVITY_TASK_TIMED_OUT:
+		attrs := event.GetActivityTaskTimedOutEventAttributes()
+		return fmt.Sprintf("%s: %s", activityNames[attrs.GetScheduledEventId()], attrs.GetFailure().GetMessage())
+	case enumspb.EVENT_TYPE_START_CHILD_WORKFLOW_EXECUTION_INITIATED:
+		attrs := event.GetStartChildWorkflowExecutionInitiatedEventAttributes()
+		return fmt.Sprintf("%s %s", attrs.GetWorkflowType().GetName(), attrs.GetWorkflowId())
+	case enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_STARTED,
+		enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_COMPLETED,
+		enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_FAILED,
+		enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_CANCELED,
+		enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_TIMED_OUT,
+		enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_TERMINATED:
+		if attrs := event.GetChildWorkflowExecutionStartedEventAttributes(); attrs != nil {
+			return fmt.Sprintf("%s %s", attrs.GetWorkflowType().GetName(), attrs.GetWorkflowExecution().GetWorkflowId())
+		}
+		return childID(event)
+	case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_SIGNALED:
+		return event.GetWorkflowExecutionSignaledEventAttributes().GetSignalName()
+	case enumspb.EVENT_TYPE_TIMER_STARTED:
+		if timeout := event.GetTimerStartedEventAttributes().GetStartToFireTimeout(); timeout != nil {
+			return timeout.String()
+		}
+	case enumspb.EVENT_TYPE_MARKER_RECORDED:
+		return event.GetMarkerRecordedEventAttributes().GetMarkerName()
+	case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_FAILED:
+		return event.GetWorkflowExecutionFailedEventAttributes().GetFailure().GetMessage()
+	case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CANCEL_REQUESTED:
+		return event.GetWorkflowExecutionCancelRequestedEventAttributes().GetCause()
+	case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CONTINUED_AS_NEW:
+		return "new run " + event.GetWorkflowExecutionContinuedAsNewEventAttributes().GetNewExecutionRunId()
+	}
+	return ""
+}
+
+// isCloseEvent indica si el evento cierra la ejecución
+func isCloseEvent(eventType enumspb.EventType) bool {
+	switch eventType {
+	case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_COMPLETED,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_FAILED,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_TIMED_OUT,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CANCELED,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_TERMINATED,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CONTINUED_AS_NEW:
+		return true
+	}
+	return false
+}
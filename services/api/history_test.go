@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	failurepb "go.temporal.io/api/failure/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+)
+
+// loadHistory lee un historial exportado de Temporal (temporal workflow show
+// --output json), con la forma real de los eventos
+func loadHistory(t *testing.T, path string) *historypb.History {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	history, err := client.HistoryFromJSON(f, client.HistoryJSONOptions{})
+	require.NoError(t, err)
+	return history
+}
+
+func TestEventDetailsOfRecordedHistory(t *testing.T) {
+	history := loadHistory(t, "testdata/histories/workflow-a.json")
+
+	activityNames := map[int64]string{}
+	details := map[int64]string{}
+	var closing []int64
+	for _, event := range history.GetEvents() {
+		if attrs := event.GetActivityTaskScheduledEventAttributes(); attrs != nil {
+			activityNames[event.GetEventId()] = attrs.GetActivityType().GetName()
+		}
+		details[event.GetEventId()] = eventDetails(event, activityNames)
+		if isCloseEvent(event.GetEventType()) {
+			closing = append(closing, event.GetEventId())
+		}
+	}
+
+	assert.Equal(t, "WorkflowA", details[1])
+	assert.Equal(t, "Activity1", details[5])
+	assert.Equal(t, "Activity1 (attempt 1)", details[6])
+	assert.Equal(t, "Activity1", details[7])
+	assert.Equal(t, "Version", details[17])
+	child := "WorkflowB test-demo-a-002-workflow-b-5b0e2a7c-7f5d-4a3e-9c44-2f1a6c1d9e01"
+	assert.Equal(t, child, details[19])
+	assert.Equal(t, child, details[20])
+	assert.Equal(t, "test-demo-a-002-workflow-b-5b0e2a7c-7f5d-4a3e-9c44-2f1a6c1d9e01", details[24])
+	assert.Empty(t, details[2], "workflow tasks carry no details")
+	// Solo el cierre del propio workflow cierra la ejecución, no el del hijo
+	assert.Equal(t, []int64{34}, closing)
+}
+
+func TestEventDetailsOfServerEvents(t *testing.T) {
+	activityNames := map[int64]string{5: "Activity2"}
+	timeout := 10 * time.Second
+	tests := []struct {
+		name  string
+		event *historypb.HistoryEvent
+		want  string
+	}{
+		{
+			name: "activity failed",
+			event: &historypb.HistoryEvent{
+				EventType: enumspb.EVENT_TYPE_ACTIVITY_TASK_FAILED,
+				Attributes: &historypb.HistoryEvent_ActivityTaskFailedEventAttributes{ActivityTaskFailedEventAttributes: &historypb.ActivityTaskFailedEventAttributes{
+					ScheduledEventId: 5,
+					StartedEventId:   6,
+					Failure: &failurepb.Failure{
+						Message:     "payment declined",
+						FailureInfo: &failurepb.Failure_ApplicationFailureInfo{ApplicationFailureInfo: &failurepb.ApplicationFailureInfo{Type: "PaymentDeclined", NonRetryable: true}},
+					},
+					RetryState: enumspb.RETRY_STATE_NON_RETRYABLE_FAILURE,
+				}},
+			},
+			want: "Activity2: payment declined",
+		},
+		{
+			name: "activity timed out",
+			event: &historypb.HistoryEvent{
+				EventType: enumspb.EVENT_TYPE_ACTIVITY_TASK_TIMED_OUT,
+				Attributes: &historypb.HistoryEvent_ActivityTaskTimedOutEventAttributes{ActivityTaskTimedOutEventAttributes: &historypb.ActivityTaskTimedOutEventAttributes{
+					ScheduledEventId: 5,
+					Failure: &failurepb.Failure{
+						Message:     "activity StartToClose timeout",
+						FailureInfo: &failurepb.Failure_TimeoutFailureInfo{TimeoutFailureInfo: &failurepb.TimeoutFailureInfo{TimeoutType: enumspb.TIMEOUT_TYPE_START_TO_CLOSE}},
+					},
+					RetryState: enumspb.RETRY_STATE_MAXIMUM_ATTEMPTS_REACHED,
+				}},
+			},
+			want: "Activity2: activity StartToClose timeout",
+		},
+		{
+			name: "child failed",
+			event: &historypb.HistoryEvent{
+				EventType: enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_FAILED,
+				Attributes: &historypb.HistoryEvent_ChildWorkflowExecutionFailedEventAttributes{ChildWorkflowExecutionFailedEventAttributes: &historypb.ChildWorkflowExecutionFailedEventAttributes{
+					WorkflowExecution: &commonpb.WorkflowExecution{WorkflowId: "order-1-workflow-b", RunId: "run-b"},
+					WorkflowType:      &commonpb.WorkflowType{Name: "WorkflowB"},
+					Failure:           &failurepb.Failure{Message: "child failed"},
+				}},
+			},
+			want: "order-1-workflow-b",
+		},
+		{
+			name: "signal",
+			event: &historypb.HistoryEvent{
+				EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_SIGNALED,
+				Attributes: &historypb.HistoryEvent_WorkflowExecutionSignaledEventAttributes{WorkflowExecutionSignaledEventAttributes: &historypb.WorkflowExecutionSignaledEventAttributes{
+					SignalName: "approval",
+					Identity:   "api",
+				}},
+			},
+			want: "approval",
+		},
+		{
+			name: "timer",
+			event: &historypb.HistoryEvent{
+				EventType: enumspb.EVENT_TYPE_TIMER_STARTED,
+				Attributes: &historypb.HistoryEvent_TimerStartedEventAttributes{TimerStartedEventAttributes: &historypb.TimerStartedEventAttributes{
+					TimerId:            "7",
+					StartToFireTimeout: &timeout,
+				}},
+			},
+			want: "10s",
+		},
+		{
+			name: "workflow failed",
+			event: &historypb.HistoryEvent{
+				EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_FAILED,
+				Attributes: &historypb.HistoryEvent_WorkflowExecutionFailedEventAttributes{WorkflowExecutionFailedEventAttributes: &historypb.WorkflowExecutionFailedEventAttributes{
+					Failure:    &failurepb.Failure{Message: "Activity2 failed: payment declined", Cause: &failurepb.Failure{Message: "payment declined"}},
+					RetryState: enumspb.RETRY_STATE_RETRY_POLICY_NOT_SET,
+				}},
+			},
+			want: "Activity2 failed: payment declined",
+		},
+		{
+			name: "continued as new",
+			event: &historypb.HistoryEvent{
+				EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CONTINUED_AS_NEW,
+				Attributes: &historypb.HistoryEvent_WorkflowExecutionContinuedAsNewEventAttributes{WorkflowExecutionContinuedAsNewEventAttributes: &historypb.WorkflowExecutionContinuedAsNewEventAttributes{
+					NewExecutionRunId: "run-2",
+					WorkflowType:      &commonpb.WorkflowType{Name: "BatchWorkflow"},
+				}},
+			},
+			want: "new run run-2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, eventDetails(tt.event, activityNames))
+		})
+	}
+}
+
+func TestIsCloseEvent(t *testing.T) {
+	for _, eventType := range []enumspb.EventType{
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_COMPLETED,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_FAILED,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_TIMED_OUT,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CANCELED,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_TERMINATED,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CONTINUED_AS_NEW,
+	} {
+		assert.True(t, isCloseEvent(eventType), eventType.String())
+	}
+	for _, eventType := range []enumspb.EventType{
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CANCEL_REQUESTED,
+		enumspb.EVENT_TYPE_WORKFLOW_TASK_FAILED,
+		enumspb.EVENT_TYPE_ACTIVITY_TASK_FAILED,
+		enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_COMPLETED,
+		enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_TERMINATED,
+	} {
+		assert.False(t, isCloseEvent(eventType), eventType.String())
+	}
+}
+
+// recordedBackend sirve un historial exportado en páginas de pageSize
+// eventos, como el servidor cuando el historial no entra en una respuesta
+type recordedBackend struct {
+	Backend
+	history  *historypb.History
+	pageSize int
+	requests []*workflowservice.GetWorkflowExecutionHistoryRequest
+}
+
+func (b *recordedBackend) GetWorkflowHistoryPage(ctx context.Context, request *workflowservice.GetWorkflowExecutionHistoryRequest) (*workflowservice.GetWorkflowExecutionHistoryResponse, error) {
+	b.requests = append(b.requests, request)
+	start := 0
+	if len(request.GetNextPageToken()) > 0 {
+		start, _ = strconv.Atoi(string(request.GetNextPageToken()))
+	}
+	events := b.history.GetEvents()
+	end := min(start+b.pageSize, len(events))
+	resp := &workflowservice.GetWorkflowExecutionHistoryResponse{History: &historypb.History{Events: events[start:end]}}
+	if end < len(events) {
+		resp.NextPageToken = []byte(strconv.Itoa(end))
+	}
+	return resp, nil
+}
+
+func TestHistoryPagesKeepActivityNames(t *testing.T) {
+	backend := &recordedBackend{history: loadHistory(t, "testdata/histories/workflow-d.json"), pageSize: 7}
+	namespaces := &namespaceClients{
+		clients: map[string]Backend{"default": backend},
+		create:  func(string) (Backend, error) { return backend, nil },
+	}
+	server := &Server{
+		clusters: &clusterRegistry{
+			clusters:    map[string]*temporalCluster{"default": {name: "default", namespaces: namespaces}},
+			names:       []string{"default"},
+			defaultName: "default",
+		},
+		defaultNamespace: "default",
+		credentials:      newAnonymousStore([]string{"default"}),
+	}
+	httpServer := httptest.NewServer(server.routes())
+	t.Cleanup(httpServer.Close)
+
+	// Las tres activities en paralelo se programan en la primera página y
+	// terminan en las siguientes; el token lleva sus nombres
+	details := map[int64]string{}
+	var pages int
+	var last HistoryResponse
+	query := url.Values{"workflowId": {"test-demo-d-001"}}
+	for {
+		var page HistoryResponse
+		require.Equal(t, http.StatusOK, call(t, http.MethodGet, httpServer.URL+"/workflows/history?"+query.Encode(), nil, &page))
+		pages++
+		assert.LessOrEqual(t, len(page.Events), backend.pageSize)
+		for _, event := range page.Events {
+			details[event.EventID] = event.EventType + " " + event.Details
+		}
+		last = page
+		if page.NextPageToken == "" {
+			break
+		}
+		query.Set("nextPageToken", page.NextPageToken)
+	}
+
+	assert.Equal(t, len(backend.history.GetEvents()), len(details))
+	assert.Equal(t, len(backend.requests), pages)
+	for _, request := range backend.requests {
+		assert.EqualValues(t, historyPageSize, request.GetMaximumPageSize())
+		assert.False(t, request.GetWaitNewEvent())
+	}
+	assert.Equal(t, "TimerStarted 10s", details[5])
+	assert.Equal(t, "ActivityTaskScheduled Activity1", details[6])
+	var completed []string
+	for _, detail := range details {
+		if activity, ok := strings.CutPrefix(detail, "ActivityTaskCompleted "); ok {
+			completed = append(completed, activity)
+		}
+	}
+	assert.ElementsMatch(t, []string{"Activity1", "Activity2", "Activity4", "Activity3"}, completed)
+	assert.True(t, last.Closed)
+
+	assert.Equal(t, http.StatusBadRequest, call(t, http.MethodGet, httpServer.URL+"/workflows/history?workflowId=x&nextPageToken=%25%25", nil, nil))
+}
+
+func TestHistoryWaitKeepsTokenWhileRunning(t *testing.T) {
+	api := newTestAPI(t, SimulatorConfig{Default: &SimulatedWorkflow{Steps: slowSteps}})
+	startWorkflow(t, api, StartWorkflowRequest{WorkflowID: "order-9"})
+
+	// Sin wait, el historial leído completo no deja token
+	var history HistoryResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/history?workflowId=order-9", nil, &history))
+	require.NotEmpty(t, history.Events)
+	assert.Empty(t, history.NextPageToken)
+
+	// Con wait, el token permite seguir desde el último evento sin releerlo
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/history?wait=true&workflowId=order-9", nil, &history))
+	require.NotEmpty(t, history.NextPageToken)
+	lastEventID := history.Events[len(history.Events)-1].EventID
+
+	require.Equal(t, http.StatusOK, call(t, http.MethodPost, api+"/workflows/cancel", CancelWorkflowRequest{WorkflowID: "order-9"}, nil))
+	var next HistoryResponse
+	require.Equal(t, http.StatusOK, call(t, http.MethodGet, api+"/workflows/history?wait=true&workflowId=order-9&nextPageToken="+history.NextPageToken, nil, &next))
+	require.NotEmpty(t, next.Events)
+	assert.Greater(t, next.Events[0].EventID, lastEventID)
+	assert.True(t, next.Closed)
+	assert.Empty(t, next.NextPageToken)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+)
+
+// WorkflowRef identifica una ejecución relacionada (padre o hijo)
+type WorkflowRef struct {
+	WorkflowID   string `json:"workflowId"`
+	RunID        string `json:"runId"`
+	WorkflowType string `json:"workflowType,omitempty"`
+	Status       string `json:"status,omitempty"`
+}
+
+// childWorkflows recorre el historial del workflow y devuelve los child
+// workflows que lanzó, con el último estado conocido de cada uno
+func childWorkflows(ctx context.Context, c Backend, workflowID, runID string) ([]WorkflowRef, error) {
+	var children []WorkflowRef
+	index := map[string]int{}
+
+	setStatus := func(workflowID, status string) {
+		if i, ok := index[workflowID]; ok {
+			children[i].Status = status
+		}
+	}
+
+	iter := c.GetWorkflowHistory(ctx, workflowID, runID, false, enumspb.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+	for iter.HasNext() {
+		event, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch event.GetEventType() {
+		case enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_STARTED:
+			attrs := event.GetChildWorkflowExecutionStartedEventAttributes()
+			index[attrs.GetWorkflowExecution().GetWorkflowId()] = len(children)
+			children = append(children, WorkflowRef{
+				WorkflowID:   attrs.GetWorkflowExecution().GetWorkflowId(),
+				RunID:        attrs.GetWorkflowExecution().GetRunId(),
+				WorkflowType: attrs.GetWorkflowType().GetName(),
+				Status:       enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING.String(),
+			})
+		case enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_COMPLETED:
+			setStatus(childID(event), enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED.String())
+		case enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_FAILED:
+			setStatus(childID(event), enumspb.WORKFLOW_EXECUTION_STATUS_FAILED.String())
+		case enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_CANCELED:
+			setStatus(childID(event), enumspb.WORKFLOW_EXECUTION_STATUS_CANCELED.String())
+		case enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_TIMED_OUT:
+			setStatus(childID(event), enumspb.WORKFLOW_EXECUTION_STATUS_TIMED_OUT.String())
+		case enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_TERMINATED:
+			setStatus(childID(event), enumspb.WORKFLOW_EXECUTION_STATUS_TERMINATED.String())
+		}
+	}
+
+	return children, nil
+}
+
+// childID extrae el workflow ID del hijo de un evento de cierre de child workflow
+func childID(event *historypb.HistoryEvent) string {
+	switch event.GetEventType() {
+	case enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_COMPLETED:
+		return event.GetChildWorkflowExecutionCompletedEventAttributes().GetWorkflowExecution().GetWorkflowId()
+	case enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_FAILED:
+		return event.GetChildWorkflowExecutionFailedEventAttributes().GetWorkflowExecution().GetWorkflowId()
+	case enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_CANCELED:
+		return event.GetChildWorkflowExecutionCanceledEventAttributes().GetWorkflowExecution().GetWorkflowId()
+	case enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_TIMED_OUT:
+		return event.GetChildWorkflowExecutionTimedOutEventAttributes().GetWorkflowExecution().GetWorkflowId()
+	case enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_TERMINATED:
+		return event.GetChildWorkflowExecutionTerminatedEventAttributes().GetWorkflowExecution().GetWorkflowId()
+	}
+	return ""
+}
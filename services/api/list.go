@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.temporal.io/api/workflowservice/v1"
+)
+
+// Límites del tamaño de página del listado
+const (
+	defaultListPageSize = 50
+	maxListPageSize     = 500
+)
+
+// listStatuses son los valores de ExecutionStatus aceptados como filtro
+var listStatuses = map[string]bool{
+	"Running":        true,
+	"Completed":      true,
+	"Failed":         true,
+	"Canceled":       true,
+	"Terminated":     true,
+	"ContinuedAsNew": true,
+	"TimedOut":       true,
+}
+
+// WorkflowSummary es una ejecución en la respuesta del listado
+type WorkflowSummary struct {
+	WorkflowID       string                 `json:"workflowId"`
+	RunID            string                 `json:"runId"`
+	WorkflowType     string                 `json:"workflowType"`
+	Status           string                 `json:"status"`
+	StartTime        time.Time              `json:"startTime"`
+	CloseTime        *time.Time             `json:"closeTime,omitempty"`
+	SearchAttributes map[string]interface{} `json:"searchAttributes,omitempty"`
+}
+
+// ListWorkflowsResponse es una página del listado; NextPageToken vacío indica
+// que no hay más resultados
+type ListWorkflowsResponse struct {
+	Workflows     []WorkflowSummary `json:"workflows"`
+	NextPageToken string            `json:"nextPageToken,omitempty"`
+}
+
+// listWorkflowsHandler lista ejecuciones filtrando por tipo, estado y los
+// search attributes declarados, p.ej.
+// /workflows?workflowType=WorkflowA&status=Running&currentStep=Activity3&customerId=C-42
+func (s *Server) listWorkflowsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	query, err := buildListQuery(params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid filter", err.Error())
+		return
+	}
+
+	pageSize, pageToken, ok := parsePageParams(w, params)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := s.backendFor(r).ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+		Query:         query,
+		PageSize:      int32(pageSize),
+		NextPageToken: pageToken,
+	})
+	if err != nil {
+		log.Printf("Error listing workflows: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to list workflows", err.Error())
+		return
+	}
+
+	response := ListWorkflowsResponse{Workflows: []WorkflowSummary{}}
+	for _, execution := range resp.GetExecutions() {
+		summary := WorkflowSummary{
+			WorkflowID:       execution.GetExecution().GetWorkflowId(),
+			RunID:            execution.GetExecution().GetRunId(),
+			WorkflowType:     execution.GetType().GetName(),
+			Status:           execution.GetStatus().String(),
+			SearchAttributes: decodeSearchAttributes(execution.GetSearchAttributes()),
+		}
+		if start := execution.GetStartTime(); start != nil {
+			summary.StartTime = *start
+		}
+		if closeTime := execution.GetCloseTime(); closeTime != nil && !closeTime.IsZero() {
+			summary.CloseTime = closeTime
+		}
+		response.Workflows = append(response.Workflows, summary)
+	}
+	if token := resp.GetNextPageToken(); len(token) > 0 {
+		response.NextPageToken = base64.URLEncoding.EncodeToString(token)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// parsePageParams lee pageSize y nextPageToken del listado; si alguno es
+// inválido responde 400 y devuelve ok en false
+func parsePageParams(w http.ResponseWriter, params url.Values) (pageSize int, pageToken []byte, ok bool) {
+	var err error
+	pageSize = defaultListPageSize
+	if v := params.Get("pageSize"); v != "" {
+		if pageSize, err = strconv.Atoi(v); err != nil || pageSize <= 0 || pageSize > maxListPageSize {
+			respondWithError(w, http.StatusBadRequest, "Invalid pageSize", fmt.Sprintf("pageSize must be between 1 and %d", maxListPageSize))
+			return 0, nil, false
+		}
+	}
+	if v := params.Get("nextPageToken"); v != "" {
+		if pageToken, err = base64.URLEncoding.DecodeString(v); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid nextPageToken", err.Error())
+			return 0, nil, false
+		}
+	}
+	return pageSize, pageToken, true
+}
+
+// buildListQuery arma la consulta de visibilidad a partir de los filtros.
+// Un parámetro desconocido es un error para no listar de más en silencio.
+func buildListQuery(params url.Values) (string, error) {
+	var clauses []string
+	for _, param := range sortedParams(params) {
+		value := params.Get(param)
+		switch param {
+		case "pageSize", "nextPageToken":
+			continue
+		case "workflowType":
+			clauses = append(clauses, fmt.Sprintf("WorkflowType = %s", quoteQueryValue(value)))
+		case "status":
+			if !listStatuses[value] {
+				return "", fmt.Errorf("unknown status %q", value)
+			}
+			clauses = append(clauses, fmt.Sprintf("ExecutionStatus = %s", quoteQueryValue(value)))
+		default:
+			attr, ok := searchAttributeByParam(param)
+			if !ok {
+				return "", fmt.Errorf("unknown filter %q", param)
+			}
+			clauses = append(clauses, fmt.Sprintf("%s = %s", attr.Name, quoteQueryValue(value)))
+		}
+	}
+	return strings.Join(clauses, " AND "), nil
+}
+
+// quoteQueryValue entrecomilla un valor para la consulta de visibilidad
+func quoteQueryValue(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	return "'" + escaped + "'"
+}
+
+// sortedParams devuelve los nombres de parámetros ordenados, así la consulta
+// generada es estable
+func sortedParams(params url.Values) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -1,46 +1,105 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"go.temporal.io/sdk/client"
+
+	"github.com/temporal-aws-poc/contracts/temporalconn"
 )
 
 type Server struct {
-	temporalClient client.Client
+	// clusters son los clusters de Temporal, cada uno con su conexión y un
+	// cliente por namespace creado al primer uso
+	clusters         *clusterRegistry
+	defaultNamespace string
+	credentials      *credentialStore
 }
 
 func main() {
 	log.Println("Starting Temporal API Service...")
 
-	// Conectar al servidor Temporal
-	temporalHostPort := os.Getenv("TEMPORAL_HOST_PORT")
-	if temporalHostPort == "" {
-		temporalHostPort = "localhost:7233"
+	// -backend simulator sirve el API sin un cluster de Temporal, con
+	// workflows simulados en memoria (ver SimulatorConfig)
+	defaultBackend := os.Getenv("API_BACKEND")
+	if defaultBackend == "" {
+		defaultBackend = temporalBackend
 	}
-	log.Printf("Connecting to Temporal at: %s", temporalHostPort)
+	backend := flag.String("backend", defaultBackend, "workflow backend: temporal or simulator (defaults to API_BACKEND)")
+	simulatorFile := flag.String("simulator-config", os.Getenv("API_SIMULATOR_FILE"), "simulated workflows file (defaults to API_SIMULATOR_FILE)")
+	flag.Parse()
 
-	c, err := client.Dial(client.Options{
-		HostPort: temporalHostPort,
-	})
-	if err != nil {
-		log.Fatalf("Unable to create Temporal client: %v", err)
+	var clusters *clusterRegistry
+	switch *backend {
+	case temporalBackend:
+		clusters = newTemporalRegistry()
+	case simulatorBackend:
+		var simulatorConfig SimulatorConfig
+		if *simulatorFile != "" {
+			var err error
+			if simulatorConfig, err = loadSimulatorConfig(*simulatorFile); err != nil {
+				log.Fatalf("Unable to load simulated workflows from %s: %v", *simulatorFile, err)
+			}
+			log.Printf("Simulated workflows loaded from: %s", *simulatorFile)
+		}
+		clusters = newSimulatorRegistry(simulatorConfig)
+		log.Printf("Using the in-memory simulator backend; workflows are not sent to Temporal")
+	default:
+		log.Fatalf("Unknown backend %q (expected %s or %s)", *backend, temporalBackend, simulatorBackend)
+	}
+	defer clusters.close()
+
+	// Namespace de los requests que no indican uno (prefijo /namespaces/<ns>/
+	// o header X-Temporal-Namespace)
+	defaultNamespace := os.Getenv("TEMPORAL_NAMESPACE")
+	if defaultNamespace == "" {
+		defaultNamespace = client.DefaultNamespace
+	}
+
+	// Llamadores y namespaces a los que acceden; sin archivo no hay
+	// autenticación y se accede a los namespaces de API_NAMESPACES. Cada
+	// namespace necesita su propio worker (TEMPORAL_NAMESPACE del worker)
+	credentials := newAnonymousStore(splitList(os.Getenv("API_NAMESPACES"), defaultNamespace))
+	if credentialsFile := os.Getenv("API_CREDENTIALS_FILE"); credentialsFile != "" {
+		var err error
+		if credentials, err = loadCredentials(credentialsFile); err != nil {
+			log.Fatalf("Unable to load API credentials from %s: %v", credentialsFile, err)
+		}
+		log.Printf("API credentials loaded from: %s", credentialsFile)
+	} else {
+		log.Printf("API credentials not configured; namespaces allowed: %v", credentials.anonymous.Namespaces)
 	}
-	defer c.Close()
-	log.Println("Successfully connected to Temporal server")
 
 	server := &Server{
-		temporalClient: c,
+		clusters:         clusters,
+		defaultNamespace: defaultNamespace,
+		credentials:      credentials,
 	}
 
-	// Configurar handlers HTTP
-	http.HandleFunc("/health", server.healthHandler)
-	http.HandleFunc("/workflows/start", server.startWorkflowHandler)
-	http.HandleFunc("/workflows/status", server.workflowStatusHandler)
+	// Al conectar se crea el cliente del namespace por defecto, que registra
+	// los search attributes declarados; sin ellos los inicios con
+	// searchAttributes y los filtros del listado fallan
+	for _, name := range clusters.names {
+		cluster := clusters.clusters[name]
+		if cluster.connection == nil || !cluster.serves(defaultNamespace) {
+			continue
+		}
+		cluster.connection.onConnect = func(context.Context, client.Client) {
+			if _, err := cluster.namespaces.get(defaultNamespace); err != nil {
+				log.Printf("Warning: cluster %s: %v", cluster.name, err)
+			}
+		}
+	}
+	connectionCtx, connectionStop := context.WithCancel(context.Background())
+	defer connectionStop()
+	dialErrors := clusters.run(connectionCtx)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -54,12 +113,113 @@ func main() {
 	// Iniciar servidor HTTP en goroutine
 	go func() {
 		log.Printf("API Server listening on port %s", port)
-		if err := http.ListenAndServe(":"+port, nil); err != nil && err != http.ErrServerClosed {
+		if err := http.ListenAndServe(":"+port, server.routes()); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server failed to start: %v", err)
 		}
 	}()
 
-	// Esperar señal de shutdown
-	<-sigChan
+	// Esperar señal de shutdown. Un cluster que no conecta dentro de
+	// TEMPORAL_DIAL_MAX_WAIT termina el proceso para que el orquestador lo
+	// reinicie
+	select {
+	case <-sigChan:
+	case err := <-dialErrors:
+		log.Fatalf("Giving up on Temporal: %v", err)
+	}
 	log.Println("Shutting down API server gracefully...")
 }
+
+// newTemporalRegistry arma los clusters de Temporal desde el entorno
+func newTemporalRegistry() *clusterRegistry {
+	temporalHostPort := os.Getenv("TEMPORAL_HOST_PORT")
+	if temporalHostPort == "" {
+		temporalHostPort = "localhost:7233"
+	}
+
+	// Los clientes se crean sin conectar: la API sirve desde el arranque y
+	// responde not-ready (y 503 en las rutas de workflows) hasta que Temporal
+	// responde; después cada conexión se vigila y se restablece sola
+	backoff, err := temporalconn.BackoffFromEnv(defaultDialBackoff, os.Getenv)
+	if err != nil {
+		log.Fatalf("Invalid dial configuration: %v", err)
+	}
+
+	// Clusters de Temporal; sin archivo hay uno solo, el de TEMPORAL_HOST_PORT
+	// con las variables TEMPORAL_TLS_*. Los clusters del archivo usan solo su
+	// sección tls, así la API key de un cluster nunca se envía a otro.
+	clustersConfig := ClustersConfig{Clusters: []ClusterConfig{{Name: "default", HostPort: temporalHostPort}}}
+	var security temporalconn.Security
+	if clustersFile := os.Getenv("API_CLUSTERS_FILE"); clustersFile != "" {
+		if clustersConfig, err = loadClustersConfig(clustersFile); err != nil {
+			log.Fatalf("Unable to load Temporal clusters from %s: %v", clustersFile, err)
+		}
+		log.Printf("Temporal clusters loaded from: %s", clustersFile)
+	} else if security, err = temporalconn.SecurityFromEnv(os.Getenv); err != nil {
+		log.Fatalf("Invalid TLS configuration: %v", err)
+	}
+	clusters, err := newClusterRegistry(clustersConfig, backoff, security, os.Getenv)
+	if err != nil {
+		log.Fatalf("Unable to create Temporal clients: %v", err)
+	}
+	log.Printf("Default Temporal cluster: %s", clusters.defaultName)
+	if clusters.failover != "" {
+		log.Printf("Failover Temporal cluster for new workflows: %s", clusters.failover)
+	}
+	return clusters
+}
+
+// routes arma los handlers HTTP del API
+func (s *Server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.healthHandler)
+	mux.HandleFunc("/ready", s.readyHandler)
+	mux.HandleFunc("/metrics", s.metricsHandler)
+	mux.HandleFunc("/admin/namespaces", s.withCluster(false, s.requireTemporal(s.withAdmin(s.namespacesAdminHandler))))
+
+	// Rutas que operan sobre un namespace; también se sirven bajo
+	// /namespaces/<ns>/ (p.ej. /namespaces/staging/workflows/start). El
+	// cluster sale del header X-Temporal-Cluster; solo los inicios hacen
+	// failover y, sin header, las rutas de una ejecución la buscan en todos.
+	routes := http.NewServeMux()
+	routes.HandleFunc("/workflows/start", s.withCluster(true, s.requireTemporal(s.withNamespace(s.startWorkflowHandler))))
+	routes.HandleFunc("/workflows", s.namespaced(s.listWorkflowsHandler))
+	routes.HandleFunc("/workflows/status", s.workflowRoute(s.workflowStatusHandler))
+	routes.HandleFunc("/workflows/signal", s.workflowRoute(s.signalWorkflowHandler))
+	routes.HandleFunc("/workflows/query", s.workflowRoute(s.queryWorkflowHandler))
+	routes.HandleFunc("/workflows/cancel", s.workflowRoute(s.cancelWorkflowHandler))
+	routes.HandleFunc("/workflows/history", s.workflowRoute(s.workflowHistoryHandler))
+	routes.HandleFunc("/approvals", s.namespaced(s.listApprovalsHandler))
+	routes.HandleFunc("/approvals/decision", s.workflowRoute(s.approvalDecisionHandler))
+	routes.HandleFunc("/batches/progress", s.workflowRoute(s.batchProgressHandler))
+	for _, path := range []string{"/workflows/start", "/workflows", "/workflows/status", "/workflows/signal", "/workflows/query", "/workflows/cancel", "/workflows/history", "/approvals", "/approvals/decision", "/batches/progress"} {
+		mux.Handle(path, routes)
+	}
+	mux.HandleFunc(namespacePathPrefix, namespacePrefixHandler(routes))
+	return mux
+}
+
+// namespaced aplica a una ruta la resolución del cluster, la conexión
+// requerida y la resolución del namespace
+func (s *Server) namespaced(next http.HandlerFunc) http.HandlerFunc {
+	return s.withCluster(false, s.requireTemporal(s.withNamespace(next)))
+}
+
+// workflowRoute es namespaced para las rutas de una ejecución: sin cluster
+// explícito, la busca en todos antes de exigir la conexión (ver locateWorkflow)
+func (s *Server) workflowRoute(next http.HandlerFunc) http.HandlerFunc {
+	return s.withCluster(false, s.locateWorkflow(s.requireTemporal(s.withNamespace(next))))
+}
+
+// splitList separa una lista por comas; vacía devuelve fallback
+func splitList(s, fallback string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return []string{fallback}
+	}
+	return items
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// El namespace de un request sale del prefijo /namespaces/<ns>/ de la ruta
+// o, si no lo tiene, del header; sin ninguno se usa el namespace por defecto
+const (
+	namespaceHeader     = "X-Temporal-Namespace"
+	namespacePathPrefix = "/namespaces/"
+)
+
+// namespacePattern es el formato de nombre de namespace que acepta Temporal
+var namespacePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,254}$`)
+
+// namespaceClients crea, la primera vez que se usa cada namespace, su
+// Backend: un cliente que comparte la conexión del cliente base o, con el
+// simulador, un simulador propio del namespace
+type namespaceClients struct {
+	// base es el cliente de Temporal del cluster; nil con el simulador
+	base   client.Client
+	create func(namespace string) (Backend, error)
+
+	mu      sync.Mutex
+	clients map[string]Backend
+}
+
+func newNamespaceClients(base client.Client) *namespaceClients {
+	n := &namespaceClients{base: base, clients: map[string]Backend{}}
+	n.create = n.temporalClient
+	return n
+}
+
+// get devuelve el Backend del namespace, creándolo la primera vez
+func (n *namespaceClients) get(namespace string) (Backend, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if c, ok := n.clients[namespace]; ok {
+		return c, nil
+	}
+	c, err := n.create(namespace)
+	if err != nil {
+		return nil, err
+	}
+	n.clients[namespace] = c
+	return c, nil
+}
+
+// temporalClient crea el cliente del namespace. Al crearlo registra los
+// search attributes declarados, igual que al arrancar con el namespace por
+// defecto.
+func (n *namespaceClients) temporalClient(namespace string) (Backend, error) {
+	c, err := client.NewClientFromExisting(n.base, client.Options{Namespace: namespace, DataConverter: contracts.DataConverter()})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Created Temporal client for namespace %s", namespace)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := registerSearchAttributes(ctx, c, namespace); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}()
+	return &temporalNamespace{Client: c, namespace: namespace}, nil
+}
+
+// close cierra los clientes de todos los namespaces
+func (n *namespaceClients) close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, c := range n.clients {
+		c.Close()
+	}
+}
+
+type requestNamespaceKey struct{}
+
+// requestNamespace es el namespace resuelto de un request y su Backend
+type requestNamespace struct {
+	name    string
+	backend Backend
+}
+
+type pathNamespaceKey struct{}
+
+// namespacePrefixHandler atiende /namespaces/<ns>/<ruta> con las mismas
+// rutas que sin prefijo, fijando el namespace del request
+func namespacePrefixHandler(routes http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		namespace, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, namespacePathPrefix), "/")
+		if !namespacePattern.MatchString(namespace) || rest == "" {
+			respondWithError(w, http.StatusNotFound, "Not found", r.URL.Path)
+			return
+		}
+
+		scoped := r.Clone(context.WithValue(r.Context(), pathNamespaceKey{}, namespace))
+		scoped.URL.Path = "/" + rest
+		routes.ServeHTTP(w, scoped)
+	}
+}
+
+// withNamespace autentica al llamador, resuelve el namespace del request y
+// comprueba que la credencial tenga acceso a él
+func (s *Server) withNamespace(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		credential := s.credentials.authenticate(r)
+		if credential == nil {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized", "A valid API key is required")
+			return
+		}
+
+		namespace := s.namespaceName(r)
+		header := r.Header.Get(namespaceHeader)
+		if path, _ := r.Context().Value(pathNamespaceKey{}).(string); path != "" && header != "" && path != header {
+			respondWithError(w, http.StatusBadRequest, "Conflicting namespace",
+				"path namespace "+path+" does not match "+namespaceHeader+" "+header)
+			return
+		}
+		if !namespacePattern.MatchString(namespace) {
+			respondWithError(w, http.StatusBadRequest, "Invalid namespace", namespace)
+			return
+		}
+		if !credential.allows(namespace) {
+			respondWithError(w, http.StatusForbidden, "Namespace not allowed",
+				"credential "+credential.Name+" cannot access namespace "+namespace)
+			return
+		}
+		cluster := s.clusterFor(r)
+		if !cluster.serves(namespace) {
+			respondWithError(w, http.StatusBadRequest, "Namespace not served by cluster",
+				"cluster "+cluster.name+" does not serve namespace "+namespace)
+			return
+		}
+
+		backend, err := cluster.namespaces.get(namespace)
+		if err != nil {
+			log.Printf("Error creating client for namespace %s: %v", namespace, err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to create namespace client", err.Error())
+			return
+		}
+		ctx := context.WithValue(r.Context(), requestNamespaceKey{}, requestNamespace{name: namespace, backend: backend})
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// namespaceName devuelve el namespace pedido: el del path, el del header o
+// el por defecto. No lo valida; eso lo hace withNamespace.
+func (s *Server) namespaceName(r *http.Request) string {
+	if path, _ := r.Context().Value(pathNamespaceKey{}).(string); path != "" {
+		return path
+	}
+	if header := r.Header.Get(namespaceHeader); header != "" {
+		return header
+	}
+	return s.defaultNamespace
+}
+
+// backendFor devuelve el Backend del namespace del request
+func (s *Server) backendFor(r *http.Request) Backend {
+	if ns, ok := r.Context().Value(requestNamespaceKey{}).(requestNamespace); ok {
+		return ns.backend
+	}
+	c, _ := s.clusterFor(r).namespaces.get(s.defaultNamespace)
+	return c
+}
+
+// withAdmin exige una credencial con admin: true
+func (s *Server) withAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		credential := s.credentials.authenticate(r)
+		if credential == nil {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized", "A valid API key is required")
+			return
+		}
+		if !credential.Admin {
+			respondWithError(w, http.StatusForbidden, "Admin credential required", "credential "+credential.Name+" is not an admin")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// NamespaceInfo es la descripción de un namespace
+type NamespaceInfo struct {
+	Namespace   string `json:"namespace"`
+	ID          string `json:"id,omitempty"`
+	State       string `json:"state,omitempty"`
+	Description string `json:"description,omitempty"`
+	OwnerEmail  string `json:"ownerEmail,omitempty"`
+	// Retention es el tiempo que se conservan las ejecuciones cerradas ("72h0m0s")
+	Retention string `json:"retention"`
+}
+
+// RegisterNamespaceRequest es el payload de POST /admin/namespaces
+type RegisterNamespaceRequest struct {
+	Namespace   string `json:"namespace"`
+	Description string `json:"description,omitempty"`
+	OwnerEmail  string `json:"ownerEmail,omitempty"`
+	// Retention es obligatorio, en formato de time.ParseDuration ("72h")
+	Retention string `json:"retention"`
+}
+
+// namespacesAdminHandler describe (GET ?namespace=) o registra (POST) un namespace
+func (s *Server) namespacesAdminHandler(w http.ResponseWriter, r *http.Request) {
+	if s.clusterFor(r).namespaces.base == nil {
+		respondWithError(w, http.StatusNotImplemented, "Not supported by the simulator",
+			"namespaces are created on first use by the simulator backend")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		s.describeNamespace(w, r)
+	case http.MethodPost:
+		s.registerNamespace(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) describeNamespace(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	if namespace == "" {
+		respondWithError(w, http.StatusBadRequest, "namespace parameter is required", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := s.clusterFor(r).namespaces.base.WorkflowService().DescribeNamespace(ctx, &workflowservice.DescribeNamespaceRequest{
+		Namespace: namespace,
+	})
+	var notFound *serviceerror.NamespaceNotFound
+	if errors.As(err, &notFound) {
+		respondWithError(w, http.StatusNotFound, "Namespace not found", namespace)
+		return
+	}
+	if err != nil {
+		log.Printf("Error describing namespace %s: %v", namespace, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to describe namespace", err.Error())
+		return
+	}
+
+	info := NamespaceInfo{
+		Namespace:   resp.GetNamespaceInfo().GetName(),
+		ID:          resp.GetNamespaceInfo().GetId(),
+		State:       resp.GetNamespaceInfo().GetState().String(),
+		Description: resp.GetNamespaceInfo().GetDescription(),
+		OwnerEmail:  resp.GetNamespaceInfo().GetOwnerEmail(),
+	}
+	if retention := resp.GetConfig().GetWorkflowExecutionRetentionTtl(); retention != nil {
+		info.Retention = retention.String()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(info)
+}
+
+func (s *Server) registerNamespace(w http.ResponseWriter, r *http.Request) {
+	var req RegisterNamespaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+	if !namespacePattern.MatchString(req.Namespace) {
+		respondWithError(w, http.StatusBadRequest, "Invalid namespace", req.Namespace)
+		return
+	}
+	retention, err := time.ParseDuration(req.Retention)
+	if err != nil || retention <= 0 {
+		respondWithError(w, http.StatusBadRequest, "Invalid retention", "retention must be a positive duration such as \"72h\"")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err = s.clusterFor(r).namespaces.base.WorkflowService().RegisterNamespace(ctx, &workflowservice.RegisterNamespaceRequest{
+		Namespace:                        req.Namespace,
+		Description:                      req.Description,
+		OwnerEmail:                       req.OwnerEmail,
+		WorkflowExecutionRetentionPeriod: &retention,
+	})
+	var exists *serviceerror.NamespaceAlreadyExists
+	if errors.As(err, &exists) {
+		respondWithError(w, http.StatusConflict, "Namespace already exists", req.Namespace)
+		return
+	}
+	if err != nil {
+		log.Printf("Error registering namespace %s: %v", req.Namespace, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to register namespace", err.Error())
+		return
+	}
+	log.Printf("Registered namespace %s with retention %s", req.Namespace, retention)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(NamespaceInfo{
+		Namespace:   req.Namespace,
+		Description: req.Description,
+		OwnerEmail:  req.OwnerEmail,
+		Retention:   retention.String(),
+	})
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+)
+
+// SignalWorkflowRequest es el payload de POST /workflows/signal
+type SignalWorkflowRequest struct {
+	WorkflowID string `json:"workflowId"`
+	// RunID es opcional; vacío señala el run actual
+	RunID      string      `json:"runId,omitempty"`
+	SignalName string      `json:"signalName"`
+	Input      interface{} `json:"input,omitempty"`
+}
+
+// QueryWorkflowRequest es el payload de POST /workflows/query
+type QueryWorkflowRequest struct {
+	WorkflowID string        `json:"workflowId"`
+	RunID      string        `json:"runId,omitempty"`
+	QueryType  string        `json:"queryType"`
+	Args       []interface{} `json:"args,omitempty"`
+}
+
+// QueryWorkflowResponse es el resultado de la query tal como lo devuelve el workflow
+type QueryWorkflowResponse struct {
+	WorkflowID string      `json:"workflowId"`
+	QueryType  string      `json:"queryType"`
+	Result     interface{} `json:"result"`
+}
+
+// CancelWorkflowRequest es el payload de POST /workflows/cancel
+type CancelWorkflowRequest struct {
+	WorkflowID string `json:"workflowId"`
+	RunID      string `json:"runId,omitempty"`
+}
+
+// signalWorkflowHandler envía una señal arbitraria a un workflow
+func (s *Server) signalWorkflowHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SignalWorkflowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+	if req.WorkflowID == "" || req.SignalName == "" {
+		respondWithError(w, http.StatusBadRequest, "workflowId and signalName are required", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.backendFor(r).SignalWorkflow(ctx, req.WorkflowID, req.RunID, req.SignalName, req.Input); err != nil {
+		log.Printf("Error signaling workflow %s: %v", req.WorkflowID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to signal workflow", err.Error())
+		return
+	}
+	log.Printf("Signal sent - ID: %s, signal: %s", req.WorkflowID, req.SignalName)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"workflowId": req.WorkflowID,
+		"message":    "Signal sent",
+	})
+}
+
+// queryWorkflowHandler ejecuta una query sobre un workflow y devuelve su resultado
+func (s *Server) queryWorkflowHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req QueryWorkflowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+	if req.WorkflowID == "" || req.QueryType == "" {
+		respondWithError(w, http.StatusBadRequest, "workflowId and queryType are required", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	value, err := s.backendFor(r).QueryWorkflow(ctx, req.WorkflowID, req.RunID, req.QueryType, req.Args...)
+	if err != nil {
+		log.Printf("Error querying %s of %s: %v", req.QueryType, req.WorkflowID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to query workflow", err.Error())
+		return
+	}
+
+	response := QueryWorkflowResponse{WorkflowID: req.WorkflowID, QueryType: req.QueryType}
+	if value.HasValue() {
+		if err := value.Get(&response.Result); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to decode query result", err.Error())
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// cancelWorkflowHandler pide la cancelación de un workflow; el workflow
+// decide cómo terminar (p.ej. compensando con la saga)
+func (s *Server) cancelWorkflowHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req CancelWorkflowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload", err.Error())
+		return
+	}
+	if req.WorkflowID == "" {
+		respondWithError(w, http.StatusBadRequest, "workflowId is required", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.backendFor(r).CancelWorkflow(ctx, req.WorkflowID, req.RunID); err != nil {
+		log.Printf("Error canceling workflow %s: %v", req.WorkflowID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to cancel workflow", err.Error())
+		return
+	}
+	log.Printf("Cancellation requested - ID: %s", req.WorkflowID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"workflowId": req.WorkflowID,
+		"message":    "Cancellation requested",
+	})
+}
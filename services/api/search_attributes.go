@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/client"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/contracts/temporalconn"
+)
+
+// maxKeywordLength acota el largo de los valores Keyword enviados por el caller
+const maxKeywordLength = 256
+
+// searchAttribute es un search attribute declarado. Param es el nombre con el
+// que viaja en el payload de inicio y en los filtros del listado; el tipo
+// sale de contracts.SearchAttributeTypes.
+type searchAttribute struct {
+	Name  string
+	Param string
+	// ReadOnly indica que solo lo escribe el worker (no se acepta al iniciar)
+	ReadOnly bool
+}
+
+// searchAttributeSchema son los search attributes que el API registra al
+// iniciar y acepta al iniciar workflows. CurrentStep lo actualiza el worker
+// con la activity o child workflow en curso y ApprovalStatus WorkflowA con
+// el estado de la aprobación manual.
+var searchAttributeSchema = []searchAttribute{
+	{Name: contracts.CustomerIDSearchAttribute, Param: "customerId"},
+	{Name: contracts.OrderIDSearchAttribute, Param: "orderId"},
+	{Name: contracts.TenantSearchAttribute, Param: "tenant"},
+	{Name: contracts.CurrentStepSearchAttribute, Param: "currentStep", ReadOnly: true},
+	{Name: contracts.ApprovalStatusSearchAttribute, Param: "approvalStatus", ReadOnly: true},
+}
+
+// searchAttributeByParam busca un search attribute declarado por su nombre de parámetro
+func searchAttributeByParam(param string) (searchAttribute, bool) {
+	for _, attr := range searchAttributeSchema {
+		if attr.Param == param {
+			return attr, true
+		}
+	}
+	return searchAttribute{}, false
+}
+
+// validateSearchAttributes revisa los search attributes enviados al iniciar un
+// workflow contra el schema y los devuelve con el nombre registrado en Temporal
+func validateSearchAttributes(values map[string]interface{}) (map[string]interface{}, error) {
+	attributes := make(map[string]interface{}, len(values))
+	for param, value := range values {
+		attr, ok := searchAttributeByParam(param)
+		if !ok {
+			return nil, fmt.Errorf("unknown search attribute %q", param)
+		}
+		if attr.ReadOnly {
+			return nil, fmt.Errorf("search attribute %q is set by the worker", param)
+		}
+
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("search attribute %q must be a string", param)
+		}
+		if s == "" || len(s) > maxKeywordLength {
+			return nil, fmt.Errorf("search attribute %q must have between 1 and %d characters", param, maxKeywordLength)
+		}
+		attributes[attr.Name] = s
+	}
+	return attributes, nil
+}
+
+// decodeSearchAttributes devuelve los search attributes declarados de una
+// ejecución, con su nombre de parámetro; ignora los del sistema
+func decodeSearchAttributes(attributes *commonpb.SearchAttributes) map[string]interface{} {
+	values := map[string]interface{}{}
+	fields := attributes.GetIndexedFields()
+	for _, attr := range searchAttributeSchema {
+		payload, ok := fields[attr.Name]
+		if !ok {
+			continue
+		}
+		var value interface{}
+		if err := contracts.DataConverter().FromPayload(payload, &value); err != nil {
+			log.Printf("Error decoding search attribute %s: %v", attr.Name, err)
+			continue
+		}
+		values[attr.Param] = value
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// registerSearchAttributes registra en el namespace los search attributes
+// declarados que falten. Falla si alguno existe con otro tipo.
+func registerSearchAttributes(ctx context.Context, c client.Client, namespace string) error {
+	names := make([]string, 0, len(searchAttributeSchema))
+	for _, attr := range searchAttributeSchema {
+		names = append(names, attr.Name)
+	}
+	registered, err := temporalconn.RegisterSearchAttributes(ctx, c.OperatorService(), namespace, names)
+	if err != nil {
+		return err
+	}
+	if len(registered) > 0 {
+		log.Printf("Registered search attributes in namespace %s: %v", namespace, registered)
+	}
+	return nil
+}
@@ -0,0 +1,939 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	failurepb "go.temporal.io/api/failure/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/serviceerror"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// SimulatorConfig es el archivo de API_SIMULATOR_FILE (YAML o JSON) con el
+// comportamiento de los workflows simulados, por tipo:
+//
+//	workflows:
+//	  WorkflowA:
+//	    steps:
+//	      - {name: Activity1, duration: 2s}
+//	      - {name: Activity2, duration: 5s}
+//	    queries:
+//	      approval-status: {status: PENDING, field: amount, value: 1500}
+//	  WorkflowB:
+//	    steps: [{name: Activity4, duration: 3s}]
+//	    status: Failed
+//	    error: payment declined
+//	    errorType: PaymentDeclined
+//	default:
+//	  steps: [{name: Activity1, duration: 1s}]
+//
+// Los tipos sin entrada usan default; sin archivo (o sin default) pasan por
+// Activity1, Activity2 y Activity3, un segundo cada una, y terminan Completed.
+type SimulatorConfig struct {
+	Workflows map[string]SimulatedWorkflow `json:"workflows,omitempty"`
+	Default   *SimulatedWorkflow           `json:"default,omitempty"`
+}
+
+// SimulatedWorkflow es el recorrido de un tipo de workflow simulado
+type SimulatedWorkflow struct {
+	// Steps son las activities por las que pasa, en orden; CurrentStep
+	// muestra la que está en curso
+	Steps []SimulatedStep `json:"steps,omitempty"`
+	// Status es el estado final: Completed (por defecto), Failed, TimedOut,
+	// Terminated o Canceled
+	Status string `json:"status,omitempty"`
+	// Result es el resultado de los que terminan Completed; vacío devuelve
+	// "<tipo> completed (simulated)"
+	Result interface{} `json:"result,omitempty"`
+	// Error y ErrorType son el mensaje y el tipo del error de aplicación de
+	// los que terminan Failed; el último paso es el que falla
+	Error     string `json:"error,omitempty"`
+	ErrorType string `json:"errorType,omitempty"`
+	// Queries son las respuestas fijas por tipo de query; las demás fallan
+	// como en un workflow que no las registra. La de aprobación también se
+	// publica en ApprovalStatus y en el memo, como lo hace WorkflowA.
+	Queries map[string]interface{} `json:"queries,omitempty"`
+}
+
+// SimulatedStep es una activity simulada y cuánto tarda ("1s", "250ms")
+type SimulatedStep struct {
+	Name     string `json:"name"`
+	Duration string `json:"duration"`
+}
+
+// simulatedStatuses son los estados finales que acepta el simulador
+var simulatedStatuses = map[string]enumspb.WorkflowExecutionStatus{
+	"Completed":  enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED,
+	"Failed":     enumspb.WORKFLOW_EXECUTION_STATUS_FAILED,
+	"TimedOut":   enumspb.WORKFLOW_EXECUTION_STATUS_TIMED_OUT,
+	"Terminated": enumspb.WORKFLOW_EXECUTION_STATUS_TERMINATED,
+	"Canceled":   enumspb.WORKFLOW_EXECUTION_STATUS_CANCELED,
+}
+
+// defaultSimulatedWorkflow es el recorrido de los tipos sin configuración
+var defaultSimulatedWorkflow = SimulatedWorkflow{
+	Steps: []SimulatedStep{
+		{Name: contracts.Activity1, Duration: "1s"},
+		{Name: contracts.Activity2, Duration: "1s"},
+		{Name: contracts.Activity3, Duration: "1s"},
+	},
+}
+
+// loadSimulatorConfig lee y valida el archivo del simulador
+func loadSimulatorConfig(path string) (SimulatorConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SimulatorConfig{}, err
+	}
+
+	var config SimulatorConfig
+	if err := contracts.DecodeYAMLStrict(data, &config); err != nil {
+		return SimulatorConfig{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return config, config.validate()
+}
+
+func (c SimulatorConfig) validate() error {
+	for workflowType, workflow := range c.Workflows {
+		if err := workflow.validate(); err != nil {
+			return fmt.Errorf("workflow %s: %w", workflowType, err)
+		}
+	}
+	if c.Default != nil {
+		if err := c.Default.validate(); err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+	}
+	return nil
+}
+
+func (w SimulatedWorkflow) validate() error {
+	for i, step := range w.Steps {
+		if step.Name == "" {
+			return fmt.Errorf("step %d: name is required", i+1)
+		}
+		if d, err := time.ParseDuration(step.Duration); err != nil || d < 0 {
+			return fmt.Errorf("step %s: invalid duration %q", step.Name, step.Duration)
+		}
+	}
+	if _, ok := simulatedStatuses[w.status()]; !ok {
+		return fmt.Errorf("unknown status %q", w.Status)
+	}
+	if (w.Error != "" || w.ErrorType != "") && w.status() != "Failed" {
+		return fmt.Errorf("error and errorType are only used with status Failed")
+	}
+	if w.Result != nil && w.status() != "Completed" {
+		return fmt.Errorf("result is only used with status Completed")
+	}
+	return nil
+}
+
+func (w SimulatedWorkflow) status() string {
+	if w.Status == "" {
+		return "Completed"
+	}
+	return w.Status
+}
+
+// workflow devuelve el recorrido de un tipo de workflow
+func (c SimulatorConfig) workflow(workflowType string) SimulatedWorkflow {
+	if workflow, ok := c.Workflows[workflowType]; ok {
+		return workflow
+	}
+	if c.Default != nil {
+		return *c.Default
+	}
+	return defaultSimulatedWorkflow
+}
+
+// newSimulatorRegistry arma un único cluster, "default", siempre disponible,
+// con un simulador por namespace creado al primer uso
+func newSimulatorRegistry(config SimulatorConfig) *clusterRegistry {
+	return &clusterRegistry{
+		clusters:    map[string]*temporalCluster{"default": newSimulatorCluster("default", config)},
+		names:       []string{"default"},
+		defaultName: "default",
+	}
+}
+
+// newSimulatorCluster crea un cluster que simula los workflows de cada
+// namespace en memoria
+func newSimulatorCluster(name string, config SimulatorConfig) *temporalCluster {
+	namespaces := &namespaceClients{
+		clients: map[string]Backend{},
+		create: func(namespace string) (Backend, error) {
+			log.Printf("Created simulator for namespace %s", namespace)
+			return newSimulator(config, namespace), nil
+		},
+	}
+	return &temporalCluster{name: name, namespaces: namespaces}
+}
+
+// simulator es un Backend en memoria: cada workflow iniciado recorre los
+// pasos de su tipo en tiempo real y termina con el estado y el resultado
+// configurados. El estado se calcula a partir del tiempo transcurrido, así
+// que no hay goroutines por workflow.
+type simulator struct {
+	config    SimulatorConfig
+	namespace string
+
+	mu sync.Mutex
+	// runs son las ejecuciones por workflow ID; la última es la actual
+	runs map[string][]*simulatedRun
+	// started son todas las ejecuciones en orden de inicio, para listarlas
+	started []*simulatedRun
+}
+
+var _ Backend = (*simulator)(nil)
+
+func newSimulator(config SimulatorConfig, namespace string) *simulator {
+	return &simulator{config: config, namespace: namespace, runs: map[string][]*simulatedRun{}}
+}
+
+// simulatedRun es una ejecución simulada. Los campos que cambian (señales y
+// cancelación) se protegen con el mutex del simulador.
+type simulatedRun struct {
+	workflowID   string
+	runID        string
+	workflowType string
+	workflow     SimulatedWorkflow
+	steps        []time.Duration
+	startTime    time.Time
+	// searchAttributes son los enviados al iniciar, por nombre registrado
+	searchAttributes map[string]interface{}
+
+	signals         []simulatedSignal
+	cancelRequested time.Time
+	// canceled se cierra al pedir la cancelación, para despertar a Get
+	canceled chan struct{}
+}
+
+type simulatedSignal struct {
+	name  string
+	input *commonpb.Payloads
+	at    time.Time
+}
+
+// closeTime es el momento en que la ejecución termina y su estado final; una
+// cancelación pedida antes del final la adelanta
+func (r *simulatedRun) closeTime() (time.Time, enumspb.WorkflowExecutionStatus) {
+	end := r.startTime
+	for _, d := range r.steps {
+		end = end.Add(d)
+	}
+	if !r.cancelRequested.IsZero() && r.cancelRequested.Before(end) {
+		return r.cancelRequested, enumspb.WORKFLOW_EXECUTION_STATUS_CANCELED
+	}
+	return end, simulatedStatuses[r.workflow.status()]
+}
+
+// status es el estado de la ejecución en now
+func (r *simulatedRun) status(now time.Time) enumspb.WorkflowExecutionStatus {
+	end, status := r.closeTime()
+	if now.Before(end) {
+		return enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING
+	}
+	return status
+}
+
+// currentStep es el último paso que empezó antes de now (y del cierre)
+func (r *simulatedRun) currentStep(now time.Time) string {
+	end, _ := r.closeTime()
+	if end.Before(now) {
+		now = end
+	}
+	current := ""
+	at := r.startTime
+	for i, d := range r.steps {
+		if at.After(now) {
+			break
+		}
+		current = r.workflow.Steps[i].Name
+		at = at.Add(d)
+	}
+	return current
+}
+
+// attributes devuelve los search attributes con CurrentStep, como los
+// actualiza el worker
+func (r *simulatedRun) attributes(now time.Time) map[string]interface{} {
+	attributes := make(map[string]interface{}, len(r.searchAttributes)+1)
+	for name, value := range r.searchAttributes {
+		attributes[name] = value
+	}
+	if step := r.currentStep(now); step != "" {
+		attributes[contracts.CurrentStepSearchAttribute] = step
+	}
+	if state, ok := r.approvalState(); ok {
+		attributes[contracts.ApprovalStatusSearchAttribute] = state.Status
+	}
+	return attributes
+}
+
+// approvalState es la respuesta configurada para la query de aprobación
+func (r *simulatedRun) approvalState() (contracts.ApprovalState, bool) {
+	answer, ok := r.workflow.Queries[contracts.ApprovalQueryName]
+	if !ok {
+		return contracts.ApprovalState{}, false
+	}
+	data, err := json.Marshal(answer)
+	if err != nil {
+		return contracts.ApprovalState{}, false
+	}
+	var state contracts.ApprovalState
+	if err := json.Unmarshal(data, &state); err != nil || state.Status == "" {
+		return contracts.ApprovalState{}, false
+	}
+	return state, true
+}
+
+// info es la ejecución tal como la devuelven describe y el listado
+func (r *simulatedRun) info(now time.Time) *workflowpb.WorkflowExecutionInfo {
+	startTime := r.startTime
+	info := &workflowpb.WorkflowExecutionInfo{
+		Execution:     &commonpb.WorkflowExecution{WorkflowId: r.workflowID, RunId: r.runID},
+		Type:          &commonpb.WorkflowType{Name: r.workflowType},
+		StartTime:     &startTime,
+		Status:        r.status(now),
+		HistoryLength: int64(len(r.history(now))),
+		TaskQueue:     contracts.DefaultTaskQueue,
+		SearchAttributes: &commonpb.SearchAttributes{
+			IndexedFields: map[string]*commonpb.Payload{},
+		},
+	}
+	if info.Status != enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING {
+		closeTime, _ := r.closeTime()
+		info.CloseTime = &closeTime
+	}
+	for name, value := range r.attributes(now) {
+		payload, err := contracts.DataConverter().ToPayload(value)
+		if err != nil {
+			log.Printf("Error encoding search attribute %s: %v", name, err)
+			continue
+		}
+		info.SearchAttributes.IndexedFields[name] = payload
+	}
+	if state, ok := r.approvalState(); ok {
+		payload, err := contracts.DataConverter().ToPayload(state)
+		if err != nil {
+			log.Printf("Error encoding approval state: %v", err)
+		} else {
+			info.Memo = &commonpb.Memo{Fields: map[string]*commonpb.Payload{contracts.ApprovalStateMemoKey: payload}}
+		}
+	}
+	return info
+}
+
+// outcome decodifica en valuePtr el resultado de una ejecución cerrada o
+// devuelve el error con el que terminó
+func (r *simulatedRun) outcome(status enumspb.WorkflowExecutionStatus, valuePtr interface{}) error {
+	var cause error
+	switch status {
+	case enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED:
+		if valuePtr == nil {
+			return nil
+		}
+		payload, err := contracts.DataConverter().ToPayload(r.result())
+		if err != nil {
+			return err
+		}
+		return contracts.DataConverter().FromPayload(payload, valuePtr)
+	case enumspb.WORKFLOW_EXECUTION_STATUS_FAILED:
+		cause = temporal.NewApplicationError(r.errorMessage(), r.workflow.ErrorType)
+	case enumspb.WORKFLOW_EXECUTION_STATUS_TIMED_OUT:
+		cause = temporal.NewTimeoutError(enumspb.TIMEOUT_TYPE_START_TO_CLOSE, nil)
+	case enumspb.WORKFLOW_EXECUTION_STATUS_TERMINATED:
+		cause = errors.New("terminated")
+	case enumspb.WORKFLOW_EXECUTION_STATUS_CANCELED:
+		cause = temporal.NewCanceledError()
+	}
+	// Mismo formato que el error de ejecución del SDK
+	return fmt.Errorf("workflow execution error (type: %s, workflowID: %s, runID: %s): %w",
+		r.workflowType, r.workflowID, r.runID, cause)
+}
+
+func (r *simulatedRun) result() interface{} {
+	if r.workflow.Result != nil {
+		return r.workflow.Result
+	}
+	return r.workflowType + " completed (simulated)"
+}
+
+func (r *simulatedRun) errorMessage() string {
+	if r.workflow.Error != "" {
+		return r.workflow.Error
+	}
+	return r.workflowType + " failed (simulated)"
+}
+
+// simulatedEvent es un evento del historial antes de numerarlo; scheduled es
+// el evento ActivityTaskScheduled al que se refiere, si corresponde
+type simulatedEvent struct {
+	at        time.Time
+	event     *historypb.HistoryEvent
+	scheduled *historypb.HistoryEvent
+}
+
+// history arma el historial de la ejecución hasta now: el inicio, los pasos,
+// las señales, la cancelación y el cierre, ordenados por tiempo
+func (r *simulatedRun) history(now time.Time) []*historypb.HistoryEvent {
+	end, status := r.closeTime()
+	events := []simulatedEvent{{
+		at: r.startTime,
+		event: &historypb.HistoryEvent{
+			EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED,
+			Attributes: &historypb.HistoryEvent_WorkflowExecutionStartedEventAttributes{
+				WorkflowExecutionStartedEventAttributes: &historypb.WorkflowExecutionStartedEventAttributes{
+					WorkflowType: &commonpb.WorkflowType{Name: r.workflowType},
+					TaskQueue:    &taskqueuepb.TaskQueue{Name: contracts.DefaultTaskQueue},
+				},
+			},
+		},
+	}}
+
+	stepStart := r.startTime
+	for i, d := range r.steps {
+		if status == enumspb.WORKFLOW_EXECUTION_STATUS_CANCELED && !stepStart.Before(end) {
+			break
+		}
+		name := r.workflow.Steps[i].Name
+		scheduled := &historypb.HistoryEvent{
+			EventType: enumspb.EVENT_TYPE_ACTIVITY_TASK_SCHEDULED,
+			Attributes: &historypb.HistoryEvent_ActivityTaskScheduledEventAttributes{
+				ActivityTaskScheduledEventAttributes: &historypb.ActivityTaskScheduledEventAttributes{
+					ActivityId:   strconv.Itoa(i + 1),
+					ActivityType: &commonpb.ActivityType{Name: name},
+					TaskQueue:    &taskqueuepb.TaskQueue{Name: contracts.DefaultTaskQueue},
+				},
+			},
+		}
+		started := &historypb.HistoryEvent{
+			EventType: enumspb.EVENT_TYPE_ACTIVITY_TASK_STARTED,
+			Attributes: &historypb.HistoryEvent_ActivityTaskStartedEventAttributes{
+				ActivityTaskStartedEventAttributes: &historypb.ActivityTaskStartedEventAttributes{Attempt: 1},
+			},
+		}
+		events = append(events,
+			simulatedEvent{at: stepStart, event: scheduled},
+			simulatedEvent{at: stepStart, event: started, scheduled: scheduled})
+
+		stepEnd := stepStart.Add(d)
+		if stepEnd.After(end) {
+			// La cancelación llegó con el paso en curso
+			break
+		}
+		closed := &historypb.HistoryEvent{
+			EventType: enumspb.EVENT_TYPE_ACTIVITY_TASK_COMPLETED,
+			Attributes: &historypb.HistoryEvent_ActivityTaskCompletedEventAttributes{
+				ActivityTaskCompletedEventAttributes: &historypb.ActivityTaskCompletedEventAttributes{},
+			},
+		}
+		if status == enumspb.WORKFLOW_EXECUTION_STATUS_FAILED && i == len(r.steps)-1 {
+			closed = &historypb.HistoryEvent{
+				EventType: enumspb.EVENT_TYPE_ACTIVITY_TASK_FAILED,
+				Attributes: &historypb.HistoryEvent_ActivityTaskFailedEventAttributes{
+					ActivityTaskFailedEventAttributes: &historypb.ActivityTaskFailedEventAttributes{
+						Failure: r.failure(),
+					},
+				},
+			}
+		}
+		events = append(events, simulatedEvent{at: stepEnd, event: closed, scheduled: scheduled})
+		stepStart = stepEnd
+	}
+
+	for _, signal := range r.signals {
+		events = append(events, simulatedEvent{
+			at: signal.at,
+			event: &historypb.HistoryEvent{
+				EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_SIGNALED,
+				Attributes: &historypb.HistoryEvent_WorkflowExecutionSignaledEventAttributes{
+					WorkflowExecutionSignaledEventAttributes: &historypb.WorkflowExecutionSignaledEventAttributes{
+						SignalName: signal.name,
+						Input:      signal.input,
+					},
+				},
+			},
+		})
+	}
+	if !r.cancelRequested.IsZero() {
+		events = append(events, simulatedEvent{
+			at: r.cancelRequested,
+			event: &historypb.HistoryEvent{
+				EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CANCEL_REQUESTED,
+				Attributes: &historypb.HistoryEvent_WorkflowExecutionCancelRequestedEventAttributes{
+					WorkflowExecutionCancelRequestedEventAttributes: &historypb.WorkflowExecutionCancelRequestedEventAttributes{},
+				},
+			},
+		})
+	}
+	events = append(events, simulatedEvent{at: end, event: r.closeEvent(status)})
+
+	// El orden de armado desempata los eventos simultáneos
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+	history := make([]*historypb.HistoryEvent, 0, len(events))
+	for _, e := range events {
+		if e.at.After(now) {
+			break
+		}
+		at := e.at
+		e.event.EventTime = &at
+		e.event.EventId = int64(len(history) + 1)
+		if e.scheduled != nil {
+			setScheduledEventID(e.event, e.scheduled.EventId)
+		}
+		history = append(history, e.event)
+	}
+	return history
+}
+
+// closeEvent es el evento que cierra la ejecución con status
+func (r *simulatedRun) closeEvent(status enumspb.WorkflowExecutionStatus) *historypb.HistoryEvent {
+	switch status {
+	case enumspb.WORKFLOW_EXECUTION_STATUS_FAILED:
+		return &historypb.HistoryEvent{
+			EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_FAILED,
+			Attributes: &historypb.HistoryEvent_WorkflowExecutionFailedEventAttributes{
+				WorkflowExecutionFailedEventAttributes: &historypb.WorkflowExecutionFailedEventAttributes{Failure: r.failure()},
+			},
+		}
+	case enumspb.WORKFLOW_EXECUTION_STATUS_TIMED_OUT:
+		return &historypb.HistoryEvent{
+			EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_TIMED_OUT,
+			Attributes: &historypb.HistoryEvent_WorkflowExecutionTimedOutEventAttributes{
+				WorkflowExecutionTimedOutEventAttributes: &historypb.WorkflowExecutionTimedOutEventAttributes{},
+			},
+		}
+	case enumspb.WORKFLOW_EXECUTION_STATUS_TERMINATED:
+		return &historypb.HistoryEvent{
+			EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_TERMINATED,
+			Attributes: &historypb.HistoryEvent_WorkflowExecutionTerminatedEventAttributes{
+				WorkflowExecutionTerminatedEventAttributes: &historypb.WorkflowExecutionTerminatedEventAttributes{Reason: "simulated"},
+			},
+		}
+	case enumspb.WORKFLOW_EXECUTION_STATUS_CANCELED:
+		return &historypb.HistoryEvent{
+			EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CANCELED,
+			Attributes: &historypb.HistoryEvent_WorkflowExecutionCanceledEventAttributes{
+				WorkflowExecutionCanceledEventAttributes: &historypb.WorkflowExecutionCanceledEventAttributes{},
+			},
+		}
+	}
+	result, err := contracts.DataConverter().ToPayloads(r.result())
+	if err != nil {
+		log.Printf("Error encoding simulated result of %s: %v", r.workflowID, err)
+	}
+	return &historypb.HistoryEvent{
+		EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_COMPLETED,
+		Attributes: &historypb.HistoryEvent_WorkflowExecutionCompletedEventAttributes{
+			WorkflowExecutionCompletedEventAttributes: &historypb.WorkflowExecutionCompletedEventAttributes{Result: result},
+		},
+	}
+}
+
+func (r *simulatedRun) failure() *failurepb.Failure {
+	return &failurepb.Failure{
+		Message: r.errorMessage(),
+		FailureInfo: &failurepb.Failure_ApplicationFailureInfo{
+			ApplicationFailureInfo: &failurepb.ApplicationFailureInfo{Type: r.workflow.ErrorType},
+		},
+	}
+}
+
+// setScheduledEventID completa la referencia de un evento de activity al
+// evento que la programó
+func setScheduledEventID(event *historypb.HistoryEvent, id int64) {
+	switch attrs := event.Attributes.(type) {
+	case *historypb.HistoryEvent_ActivityTaskStartedEventAttributes:
+		attrs.ActivityTaskStartedEventAttributes.ScheduledEventId = id
+	case *historypb.HistoryEvent_ActivityTaskCompletedEventAttributes:
+		attrs.ActivityTaskCompletedEventAttributes.ScheduledEventId = id
+	case *historypb.HistoryEvent_ActivityTaskFailedEventAttributes:
+		attrs.ActivityTaskFailedEventAttributes.ScheduledEventId = id
+	}
+}
+
+// find devuelve la ejecución pedida; sin runID, la actual. Requiere el mutex.
+func (s *simulator) find(workflowID, runID string) (*simulatedRun, error) {
+	runs := s.runs[workflowID]
+	if runID == "" && len(runs) > 0 {
+		return runs[len(runs)-1], nil
+	}
+	for _, run := range runs {
+		if run.runID == runID {
+			return run, nil
+		}
+	}
+	return nil, serviceerror.NewNotFound(fmt.Sprintf("workflow execution not found for workflow ID %q and run ID %q", workflowID, runID))
+}
+
+// findRunning es find para las operaciones que requieren la ejecución abierta
+func (s *simulator) findRunning(workflowID, runID string) (*simulatedRun, error) {
+	run, err := s.find(workflowID, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run.status(time.Now()) != enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING {
+		return nil, serviceerror.NewNotFound("workflow execution already completed")
+	}
+	return run, nil
+}
+
+func (s *simulator) ExecuteWorkflow(ctx context.Context, options client.StartWorkflowOptions, workflow interface{}, args ...interface{}) (client.WorkflowRun, error) {
+	workflowType, ok := workflow.(string)
+	if !ok {
+		return nil, fmt.Errorf("the simulator starts workflows by name, got %T", workflow)
+	}
+	if options.ID == "" {
+		options.ID = uuid.NewString()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, err := s.findRunning(options.ID, ""); err == nil {
+		return nil, serviceerror.NewWorkflowExecutionAlreadyStarted("Workflow execution is already running. WorkflowId: "+options.ID, "", current.runID)
+	}
+
+	config := s.config.workflow(workflowType)
+	run := &simulatedRun{
+		workflowID:       options.ID,
+		runID:            uuid.NewString(),
+		workflowType:     workflowType,
+		workflow:         config,
+		startTime:        time.Now(),
+		searchAttributes: map[string]interface{}{},
+		canceled:         make(chan struct{}),
+	}
+	for _, step := range config.Steps {
+		d, _ := time.ParseDuration(step.Duration)
+		run.steps = append(run.steps, d)
+	}
+	for name, value := range options.SearchAttributes {
+		run.searchAttributes[name] = value
+	}
+	s.runs[run.workflowID] = append(s.runs[run.workflowID], run)
+	s.started = append(s.started, run)
+	return &simulatedWorkflowRun{simulator: s, workflowID: run.workflowID, runID: run.runID}, nil
+}
+
+func (s *simulator) GetWorkflow(ctx context.Context, workflowID, runID string) client.WorkflowRun {
+	return &simulatedWorkflowRun{simulator: s, workflowID: workflowID, runID: runID}
+}
+
+func (s *simulator) DescribeWorkflowExecution(ctx context.Context, workflowID, runID string) (*workflowservice.DescribeWorkflowExecutionResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, err := s.find(workflowID, runID)
+	if err != nil {
+		return nil, err
+	}
+	return &workflowservice.DescribeWorkflowExecutionResponse{WorkflowExecutionInfo: run.info(time.Now())}, nil
+}
+
+func (s *simulator) GetWorkflowHistory(ctx context.Context, workflowID, runID string, isLongPoll bool, filterType enumspb.HistoryEventFilterType) client.HistoryEventIterator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, err := s.find(workflowID, runID)
+	if err != nil {
+		return &simulatedHistory{err: err}
+	}
+	now := time.Now()
+	events := run.history(now)
+	if filterType == enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT {
+		if run.status(now) == enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING {
+			return &simulatedHistory{}
+		}
+		events = events[len(events)-1:]
+	}
+	return &simulatedHistory{events: events}
+}
+
+// GetWorkflowHistoryPage pagina como el servidor: el token es la posición
+// del siguiente evento y, con WaitNewEvent, una ejecución en curso siempre
+// devuelve token aunque no haya eventos nuevos (el simulador no espera)
+func (s *simulator) GetWorkflowHistoryPage(ctx context.Context, request *workflowservice.GetWorkflowExecutionHistoryRequest) (*workflowservice.GetWorkflowExecutionHistoryResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, err := s.find(request.GetExecution().GetWorkflowId(), request.GetExecution().GetRunId())
+	if err != nil {
+		return nil, err
+	}
+	start := 0
+	if token := request.GetNextPageToken(); len(token) > 0 {
+		if start, err = strconv.Atoi(string(token)); err != nil || start < 0 {
+			return nil, serviceerror.NewInvalidArgument("invalid next page token")
+		}
+	}
+
+	now := time.Now()
+	events := run.history(now)
+	start = min(start, len(events))
+	end := len(events)
+	if size := int(request.GetMaximumPageSize()); size > 0 {
+		end = min(start+size, end)
+	}
+	resp := &workflowservice.GetWorkflowExecutionHistoryResponse{History: &historypb.History{Events: events[start:end]}}
+	if end < len(events) || (request.GetWaitNewEvent() && run.status(now) == enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING) {
+		resp.NextPageToken = []byte(strconv.Itoa(end))
+	}
+	return resp, nil
+}
+
+// simulatedQueryClause es una condición "Nombre = 'valor'" o
+// "Nombre IN ('a', 'b')" de la consulta
+var simulatedQueryClause = regexp.MustCompile(`^(\w+) (?:= ('(?:[^'\\]|\\.)*')|IN \(('(?:[^'\\]|\\.)*'(?:, '(?:[^'\\]|\\.)*')*)\))`)
+
+// simulatedQueryValue es un valor entre comillas de una condición
+var simulatedQueryValue = regexp.MustCompile(`'((?:[^'\\]|\\.)*)'`)
+
+func (s *simulator) ListWorkflow(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*workflowservice.ListWorkflowExecutionsResponse, error) {
+	filters, err := parseSimulatedQuery(request.GetQuery())
+	if err != nil {
+		return nil, serviceerror.NewInvalidArgument(err.Error())
+	}
+	offset := 0
+	if token := request.GetNextPageToken(); len(token) > 0 {
+		if offset, err = strconv.Atoi(string(token)); err != nil || offset < 0 {
+			return nil, serviceerror.NewInvalidArgument("invalid next page token")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	response := &workflowservice.ListWorkflowExecutionsResponse{}
+	matched := 0
+	// Los más recientes primero, como la visibilidad de Temporal
+	for i := len(s.started) - 1; i >= 0; i-- {
+		run := s.started[i]
+		if !run.matches(filters, now) {
+			continue
+		}
+		matched++
+		if matched <= offset {
+			continue
+		}
+		if request.GetPageSize() > 0 && len(response.Executions) == int(request.GetPageSize()) {
+			response.NextPageToken = []byte(strconv.Itoa(offset + len(response.Executions)))
+			break
+		}
+		response.Executions = append(response.Executions, run.info(now))
+	}
+	return response, nil
+}
+
+// parseSimulatedQuery interpreta las consultas que arma el API: condiciones
+// "Nombre = 'valor'" o "Nombre IN ('a', 'b')" unidas con AND
+func parseSimulatedQuery(query string) (map[string][]string, error) {
+	filters := map[string][]string{}
+	rest := strings.TrimSpace(query)
+	for rest != "" {
+		match := simulatedQueryClause.FindStringSubmatch(rest)
+		if match == nil {
+			return nil, fmt.Errorf("unsupported query %q: the simulator only supports Name = 'value' and Name IN ('value', ...) clauses joined with AND", query)
+		}
+		var values []string
+		for _, value := range simulatedQueryValue.FindAllStringSubmatch(match[2]+match[3], -1) {
+			values = append(values, strings.NewReplacer(`\\`, `\`, `\'`, `'`).Replace(value[1]))
+		}
+		filters[match[1]] = values
+		rest = strings.TrimSpace(rest[len(match[0]):])
+		if rest != "" {
+			if !strings.HasPrefix(rest, "AND ") {
+				return nil, fmt.Errorf("unsupported query %q: the simulator only supports Name = 'value' and Name IN ('value', ...) clauses joined with AND", query)
+			}
+			rest = strings.TrimSpace(strings.TrimPrefix(rest, "AND "))
+		}
+	}
+	return filters, nil
+}
+
+// matches indica si la ejecución cumple todas las condiciones de la consulta
+func (r *simulatedRun) matches(filters map[string][]string, now time.Time) bool {
+	attributes := r.attributes(now)
+	for name, values := range filters {
+		var actual string
+		switch name {
+		case "WorkflowId":
+			actual = r.workflowID
+		case "RunId":
+			actual = r.runID
+		case "WorkflowType":
+			actual = r.workflowType
+		case "ExecutionStatus":
+			actual = r.status(now).String()
+		default:
+			attribute, ok := attributes[name]
+			if !ok {
+				return false
+			}
+			actual = fmt.Sprint(attribute)
+		}
+		if !containsString(values, actual) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsString indica si value está en values
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *simulator) QueryWorkflow(ctx context.Context, workflowID, runID, queryType string, args ...interface{}) (converter.EncodedValue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, err := s.find(workflowID, runID)
+	if err != nil {
+		return nil, err
+	}
+	answer, ok := run.workflow.Queries[queryType]
+	if !ok {
+		known := make([]string, 0, len(run.workflow.Queries))
+		for name := range run.workflow.Queries {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		return nil, serviceerror.NewQueryFailed(fmt.Sprintf("unknown queryType %s. KnownQueryTypes=[%s]", queryType, strings.Join(known, " ")))
+	}
+	payloads, err := contracts.DataConverter().ToPayloads(answer)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewValue(payloads), nil
+}
+
+func (s *simulator) SignalWorkflow(ctx context.Context, workflowID, runID, signalName string, arg interface{}) error {
+	input, err := contracts.DataConverter().ToPayloads(arg)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, err := s.findRunning(workflowID, runID)
+	if err != nil {
+		return err
+	}
+	run.signals = append(run.signals, simulatedSignal{name: signalName, input: input, at: time.Now()})
+	return nil
+}
+
+func (s *simulator) CancelWorkflow(ctx context.Context, workflowID, runID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, err := s.findRunning(workflowID, runID)
+	if err != nil {
+		return err
+	}
+	if run.cancelRequested.IsZero() {
+		run.cancelRequested = time.Now()
+		close(run.canceled)
+	}
+	return nil
+}
+
+func (s *simulator) Close() {}
+
+// simulatedWorkflowRun es el client.WorkflowRun de una ejecución simulada;
+// sin runID sigue a la ejecución actual del workflow ID
+type simulatedWorkflowRun struct {
+	simulator  *simulator
+	workflowID string
+	runID      string
+}
+
+func (w *simulatedWorkflowRun) GetID() string {
+	return w.workflowID
+}
+
+func (w *simulatedWorkflowRun) GetRunID() string {
+	return w.runID
+}
+
+// Get espera a que la ejecución termine (o a que ctx venza) y devuelve su
+// resultado
+func (w *simulatedWorkflowRun) Get(ctx context.Context, valuePtr interface{}) error {
+	for {
+		w.simulator.mu.Lock()
+		run, err := w.simulator.find(w.workflowID, w.runID)
+		var end time.Time
+		var status enumspb.WorkflowExecutionStatus
+		if err == nil {
+			end, status = run.closeTime()
+		}
+		w.simulator.mu.Unlock()
+		if err != nil {
+			return err
+		}
+
+		wait := time.Until(end)
+		if wait <= 0 {
+			return run.outcome(status, valuePtr)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-run.canceled:
+			// La cancelación adelanta el cierre; se recalcula
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+func (w *simulatedWorkflowRun) GetWithOptions(ctx context.Context, valuePtr interface{}, options client.WorkflowRunGetOptions) error {
+	return w.Get(ctx, valuePtr)
+}
+
+// simulatedHistory recorre un historial ya armado
+type simulatedHistory struct {
+	events []*historypb.HistoryEvent
+	err    error
+}
+
+func (h *simulatedHistory) HasNext() bool {
+	return h.err != nil || len(h.events) > 0
+}
+
+func (h *simulatedHistory) Next() (*historypb.HistoryEvent, error) {
+	if h.err != nil {
+		err := h.err
+		h.err = nil
+		return nil, err
+	}
+	if len(h.events) == 0 {
+		return nil, errors.New("no more history events")
+	}
+	event := h.events[0]
+	h.events = h.events[1:]
+	return event, nil
+}
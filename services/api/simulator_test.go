@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSimulatorConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadSimulatorConfig(t *testing.T) {
+	path := writeSimulatorConfig(t, "simulator.yaml", `
+workflows:
+  WorkflowB:
+    steps:
+      - {name: Activity4, duration: 2s}
+    status: Failed
+    error: payment declined
+    errorType: PaymentDeclined
+default:
+  steps: [{name: Activity1, duration: 500ms}]
+  result: {ok: true}
+`)
+	config, err := loadSimulatorConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "PaymentDeclined", config.workflow("WorkflowB").ErrorType)
+	assert.Equal(t, map[string]interface{}{"ok": true}, config.workflow("WorkflowA").Result)
+	assert.Equal(t, defaultSimulatedWorkflow, SimulatorConfig{}.workflow("WorkflowA"))
+}
+
+func TestLoadSimulatorConfigErrors(t *testing.T) {
+	cases := map[string]string{
+		"unknown field":      "workflows:\n  WorkflowA:\n    stages: []\n",
+		"unknown status":     "workflows:\n  WorkflowA:\n    status: Paused\n",
+		"invalid duration":   "default:\n  steps: [{name: Activity1, duration: soon}]\n",
+		"step without name":  "default:\n  steps: [{duration: 1s}]\n",
+		"error on completed": "default:\n  error: boom\n",
+		"result on failed":   "default:\n  status: Failed\n  result: ok\n",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadSimulatorConfig(writeSimulatorConfig(t, "simulator.yaml", content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseSimulatedQuery(t *testing.T) {
+	filters, err := parseSimulatedQuery(`CustomerId = 'O\'Brien AND co' AND ExecutionStatus = 'Running'`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"CustomerId": {"O'Brien AND co"}, "ExecutionStatus": {"Running"}}, filters)
+
+	filters, err = parseSimulatedQuery(`ApprovalStatus IN ('PENDING', 'ESCALATED') AND WorkflowType = 'WorkflowA'`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"ApprovalStatus": {"PENDING", "ESCALATED"}, "WorkflowType": {"WorkflowA"}}, filters)
+
+	filters, err = parseSimulatedQuery("")
+	require.NoError(t, err)
+	assert.Empty(t, filters)
+
+	_, err = parseSimulatedQuery("WorkflowType != 'WorkflowA'")
+	assert.Error(t, err)
+}
@@ -0,0 +1,400 @@
+// Package apiclient es el cliente Go del API de workflows: iniciar,
+// consultar, listar, leer el historial, señalar, hacer queries y cancelar
+// ejecuciones, con reintentos ante 429/503 y espera hasta que una ejecución
+// termina.
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers con los que el API elige namespace y cluster
+const (
+	namespaceHeader = "X-Temporal-Namespace"
+	clusterHeader   = "X-Temporal-Cluster"
+)
+
+// RetryPolicy controla los reintentos ante 429 y 503. El API responde 503
+// mientras no tiene conexión con Temporal, así que el request no se procesó
+// y reintentarlo es seguro.
+type RetryPolicy struct {
+	// MaxAttempts cuenta el primer intento; 1 desactiva los reintentos
+	MaxAttempts     int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+}
+
+// DefaultRetryPolicy reintenta hasta 5 veces entre 500ms y 10s
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:     5,
+	InitialInterval: 500 * time.Millisecond,
+	MaxInterval:     10 * time.Second,
+}
+
+// delay es la espera antes del reintento attempt (1 es el primero): el
+// intervalo se duplica hasta MaxInterval y se aplica entre su mitad y su total
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.InitialInterval
+	for i := 1; i < attempt && d < p.MaxInterval; i++ {
+		d *= 2
+	}
+	if d > p.MaxInterval {
+		d = p.MaxInterval
+	}
+	return d/2 + jitter(d/2)
+}
+
+// DefaultPollInterval es el intervalo base de WaitForCompletion
+const DefaultPollInterval = 2 * time.Second
+
+// Options configura el cliente
+type Options struct {
+	// BaseURL es la URL del API ("http://localhost:8080")
+	BaseURL string
+	// APIKey se envía como "Authorization: Bearer <key>"
+	APIKey string
+	// Namespace y Cluster son opcionales; vacíos usan los del API por defecto
+	Namespace string
+	Cluster   string
+	// HTTPClient es opcional. La consulta de estado puede tardar hasta 10s
+	// con el workflow en curso, así que el timeout no debe ser menor.
+	HTTPClient *http.Client
+	// Retry es opcional; sin MaxAttempts se usa DefaultRetryPolicy
+	Retry RetryPolicy
+	// PollInterval es opcional; intervalo base de WaitForCompletion
+	PollInterval time.Duration
+}
+
+// Client llama al API. Es seguro usarlo desde varias goroutines.
+type Client struct {
+	baseURL *url.URL
+	options Options
+}
+
+// New crea el cliente
+func New(options Options) (*Client, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(options.BaseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", options.BaseURL)
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	if options.Retry.MaxAttempts <= 0 {
+		options.Retry = DefaultRetryPolicy
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultPollInterval
+	}
+	return &Client{baseURL: baseURL, options: options}, nil
+}
+
+// WithCluster devuelve un cliente que envía sus llamadas al cluster dado.
+// Tras un inicio con failover, las llamadas sobre esa ejecución deben usar
+// el cluster de StartResponse.Cluster: c.WithCluster(resp.Cluster). Con
+// cluster vacío devuelve c.
+func (c *Client) WithCluster(cluster string) *Client {
+	if cluster == "" || cluster == c.options.Cluster {
+		return c
+	}
+	options := c.options
+	options.Cluster = cluster
+	return &Client{baseURL: c.baseURL, options: options}
+}
+
+// APIError es una respuesta de error del API
+type APIError struct {
+	StatusCode int `json:"-"`
+	// Code es el campo "error" de la respuesta ("Invalid cluster")
+	Code string `json:"error"`
+	// Message es el detalle ("unknown cluster \"x\"")
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("api: %d %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("api: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// StatusCode devuelve el código HTTP de err si es un *APIError, o 0
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// Start inicia un workflow
+func (c *Client) Start(ctx context.Context, req StartRequest) (*StartResponse, error) {
+	var resp StartResponse
+	if err := c.do(ctx, http.MethodPost, "/workflows/start", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Status devuelve el estado de una ejecución; runID vacío es el run actual
+func (c *Client) Status(ctx context.Context, workflowID, runID string) (*WorkflowStatus, error) {
+	query := url.Values{"workflowId": {workflowID}}
+	if runID != "" {
+		query.Set("runId", runID)
+	}
+	var resp WorkflowStatus
+	if err := c.do(ctx, http.MethodGet, "/workflows/status", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// List devuelve una página de ejecuciones
+func (c *Client) List(ctx context.Context, options ListOptions) (*ListResponse, error) {
+	query := url.Values{}
+	if options.WorkflowType != "" {
+		query.Set("workflowType", options.WorkflowType)
+	}
+	if options.Status != "" {
+		query.Set("status", options.Status)
+	}
+	for param, value := range options.SearchAttributes {
+		query.Set(param, value)
+	}
+	if options.PageSize > 0 {
+		query.Set("pageSize", strconv.Itoa(options.PageSize))
+	}
+	if options.NextPageToken != "" {
+		query.Set("nextPageToken", options.NextPageToken)
+	}
+	var resp ListResponse
+	if err := c.do(ctx, http.MethodGet, "/workflows", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// History devuelve los eventos de una ejecución posteriores a afterEventID
+// (0 devuelve todos), leyendo todas las páginas; runID vacío es el run actual.
+// Para seguir una ejecución en curso conviene HistoryPage con Wait.
+func (c *Client) History(ctx context.Context, workflowID, runID string, afterEventID int64) (*History, error) {
+	query := url.Values{"workflowId": {workflowID}}
+	if runID != "" {
+		query.Set("runId", runID)
+	}
+	if afterEventID > 0 {
+		query.Set("afterEventId", strconv.FormatInt(afterEventID, 10))
+	}
+	var history *History
+	for {
+		var page History
+		if err := c.do(ctx, http.MethodGet, "/workflows/history", query, nil, &page); err != nil {
+			return nil, err
+		}
+		if history == nil {
+			history = &page
+		} else {
+			history.Events = append(history.Events, page.Events...)
+			history.Closed = page.Closed
+		}
+		if page.NextPageToken == "" {
+			history.NextPageToken = ""
+			return history, nil
+		}
+		query.Set("nextPageToken", page.NextPageToken)
+	}
+}
+
+// HistoryPage devuelve una página del historial; options.NextPageToken sigue
+// desde la página anterior
+func (c *Client) HistoryPage(ctx context.Context, workflowID string, options HistoryOptions) (*History, error) {
+	query := url.Values{"workflowId": {workflowID}}
+	if options.RunID != "" {
+		query.Set("runId", options.RunID)
+	}
+	if options.NextPageToken != "" {
+		query.Set("nextPageToken", options.NextPageToken)
+	}
+	if options.Wait {
+		query.Set("wait", "true")
+	}
+	var resp History
+	if err := c.do(ctx, http.MethodGet, "/workflows/history", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Signal envía una señal a un workflow
+func (c *Client) Signal(ctx context.Context, req SignalRequest) error {
+	return c.do(ctx, http.MethodPost, "/workflows/signal", nil, req, nil)
+}
+
+// Query ejecuta una query y decodifica su resultado en result (puede ser nil)
+func (c *Client) Query(ctx context.Context, req QueryRequest, result interface{}) error {
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/workflows/query", nil, req, &resp); err != nil {
+		return err
+	}
+	if result == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("failed to decode query result: %w", err)
+	}
+	return nil
+}
+
+// Cancel pide la cancelación de un workflow; runID vacío es el run actual
+func (c *Client) Cancel(ctx context.Context, workflowID, runID string) error {
+	return c.do(ctx, http.MethodPost, "/workflows/cancel", nil, cancelRequest{WorkflowID: workflowID, RunID: runID}, nil)
+}
+
+// WaitForCompletion consulta el estado hasta que la ejecución termina o ctx
+// vence. Para una ejecución recién iniciada, llamarlo sobre
+// WithCluster(resp.Cluster). Entre consultas espera PollInterval con ±20% de jitter, así muchos
+// clientes esperando a la vez no consultan en el mismo instante.
+func (c *Client) WaitForCompletion(ctx context.Context, workflowID, runID string) (*WorkflowStatus, error) {
+	for {
+		status, err := c.Status(ctx, workflowID, runID)
+		if err != nil {
+			return nil, err
+		}
+		if status.Done() {
+			return status, nil
+		}
+
+		interval := c.options.PollInterval
+		wait := interval - interval/5 + jitter(2*interval/5)
+		if err := sleep(ctx, wait); err != nil {
+			return status, err
+		}
+	}
+}
+
+// do envía el request y decodifica la respuesta en out (puede ser nil),
+// reintentando ante 429 y 503
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	endpoint := *c.baseURL
+	endpoint.Path += path
+	endpoint.RawQuery = query.Encode()
+
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		c.setHeaders(req, body != nil)
+
+		resp, err := c.options.HTTPClient.Do(req)
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode >= 400 {
+			apiErr := &APIError{StatusCode: resp.StatusCode}
+			if json.Unmarshal(data, apiErr) != nil || apiErr.Code == "" {
+				apiErr.Code = strings.TrimSpace(string(data))
+			}
+			if !retryable(resp.StatusCode) || attempt >= c.options.Retry.MaxAttempts {
+				return apiErr
+			}
+			// Retry-After del API manda, acotado por MaxInterval
+			wait := retryAfter(resp.Header.Get("Retry-After"))
+			if wait == 0 {
+				wait = c.options.Retry.delay(attempt)
+			} else if wait > c.options.Retry.MaxInterval {
+				wait = c.options.Retry.MaxInterval
+			}
+			if err := sleep(ctx, wait); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if out == nil {
+			return nil
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil
+	}
+}
+
+func (c *Client) setHeaders(req *http.Request, hasBody bool) {
+	req.Header.Set("Accept", "application/json")
+	if hasBody {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.options.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.options.APIKey)
+	}
+	if c.options.Namespace != "" {
+		req.Header.Set(namespaceHeader, c.options.Namespace)
+	}
+	if c.options.Cluster != "" {
+		req.Header.Set(clusterHeader, c.options.Cluster)
+	}
+}
+
+func retryable(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable
+}
+
+// retryAfter interpreta el header Retry-After en segundos; 0 si no viene
+func retryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// jitter devuelve una duración al azar en [0, max)
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
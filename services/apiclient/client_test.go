@@ -0,0 +1,280 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// fastRetry evita que los tests esperen los intervalos reales
+var fastRetry = RetryPolicy{MaxAttempts: 3, InitialInterval: time.Millisecond, MaxInterval: 5 * time.Millisecond}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, options Options) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	options.BaseURL = server.URL
+	if options.Retry.MaxAttempts == 0 {
+		options.Retry = fastRetry
+	}
+	c, err := New(options)
+	require.NoError(t, err)
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
+
+func TestStartSendsRequestAndHeaders(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/workflows/start", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "staging", r.Header.Get(namespaceHeader))
+		assert.Equal(t, "infra-2", r.Header.Get(clusterHeader))
+
+		var req StartRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "order-1", req.WorkflowID)
+		assert.Equal(t, "5m", req.SLA)
+		assert.Equal(t, 500.0, req.Approval.Threshold)
+		writeJSON(w, http.StatusOK, StartResponse{WorkflowID: req.WorkflowID, RunID: "run-1", Cluster: "infra-2"})
+	}, Options{APIKey: "secret", Namespace: "staging", Cluster: "infra-2"})
+
+	resp, err := c.Start(context.Background(), StartRequest{
+		WorkflowID: "order-1",
+		Input:      map[string]interface{}{"amount": 10},
+		Approval:   &contracts.ApprovalPolicy{Threshold: 500},
+		SLA:        "5m",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", resp.RunID)
+	assert.Equal(t, "infra-2", resp.Cluster)
+}
+
+func TestRetriesOnUnavailableAndRateLimited(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "5")
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "Temporal unavailable"})
+		case 2:
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": "Too many requests"})
+		default:
+			writeJSON(w, http.StatusOK, WorkflowStatus{WorkflowID: "wf", Status: StatusCompleted})
+		}
+	}, Options{})
+
+	// Retry-After se acota a MaxInterval, así el test no espera 5s
+	status, err := c.Status(context.Background(), "wf", "")
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, status.Status)
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+func TestGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "Temporal unavailable", "message": "retry later"})
+	}, Options{})
+
+	err := c.Signal(context.Background(), SignalRequest{WorkflowID: "wf", SignalName: "approval"})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, StatusCode(err))
+	assert.Contains(t, err.Error(), "retry later")
+	assert.EqualValues(t, fastRetry.MaxAttempts, calls.Load())
+}
+
+func TestDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid cluster", "message": `unknown cluster "x"`})
+	}, Options{})
+
+	err := c.Cancel(context.Background(), "wf", "")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "Invalid cluster", apiErr.Code)
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestContextCancelStopsRetries(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "Temporal unavailable"})
+	}, Options{Retry: RetryPolicy{MaxAttempts: 100, InitialInterval: time.Hour, MaxInterval: time.Hour}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.Status(ctx, "wf", "")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestListEncodesFilters(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/workflows", r.URL.Path)
+		query := r.URL.Query()
+		assert.Equal(t, "WorkflowA", query.Get("workflowType"))
+		assert.Equal(t, "Running", query.Get("status"))
+		assert.Equal(t, "C-42", query.Get("customerId"))
+		assert.Equal(t, "10", query.Get("pageSize"))
+		assert.Equal(t, "abc", query.Get("nextPageToken"))
+		writeJSON(w, http.StatusOK, ListResponse{Workflows: []WorkflowSummary{{WorkflowID: "wf", Status: StatusRunning}}})
+	}, Options{})
+
+	resp, err := c.List(context.Background(), ListOptions{
+		WorkflowType:     contracts.WorkflowA,
+		Status:           StatusRunning,
+		SearchAttributes: map[string]string{"customerId": "C-42"},
+		PageSize:         10,
+		NextPageToken:    "abc",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Workflows, 1)
+	assert.Empty(t, resp.NextPageToken)
+}
+
+func TestQueryDecodesTypedResult(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req QueryRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, contracts.BatchProgressQueryName, req.QueryType)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"workflowId": req.WorkflowID,
+			"queryType":  req.QueryType,
+			"result":     contracts.BatchProgress{BatchID: "b", Total: 10, Processed: 4},
+		})
+	}, Options{})
+
+	var progress contracts.BatchProgress
+	require.NoError(t, c.Query(context.Background(), QueryRequest{WorkflowID: "b", QueryType: contracts.BatchProgressQueryName}, &progress))
+	assert.Equal(t, 4, progress.Processed)
+}
+
+func TestHistorySendsCursor(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/workflows/history", r.URL.Path)
+		assert.Equal(t, "order-1", r.URL.Query().Get("workflowId"))
+		assert.Equal(t, "12", r.URL.Query().Get("afterEventId"))
+		writeJSON(w, http.StatusOK, History{
+			WorkflowID: "order-1",
+			Events:     []HistoryEvent{{EventID: 13, EventType: "ActivityTaskScheduled", Details: contracts.Activity2}},
+			Closed:     false,
+		})
+	}, Options{})
+
+	history, err := c.History(context.Background(), "order-1", "", 12)
+	require.NoError(t, err)
+	require.Len(t, history.Events, 1)
+	assert.Equal(t, contracts.Activity2, history.Events[0].Details)
+	assert.False(t, history.Closed)
+}
+
+func TestHistoryFollowsPages(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("nextPageToken") {
+		case "":
+			writeJSON(w, http.StatusOK, History{WorkflowID: "order-1", Events: []HistoryEvent{{EventID: 1}, {EventID: 2}}, NextPageToken: "p2"})
+		case "p2":
+			writeJSON(w, http.StatusOK, History{WorkflowID: "order-1", Events: []HistoryEvent{{EventID: 3}}, Closed: true})
+		}
+	}, Options{})
+
+	history, err := c.History(context.Background(), "order-1", "", 0)
+	require.NoError(t, err)
+	require.Len(t, history.Events, 3)
+	assert.Equal(t, int64(3), history.Events[2].EventID)
+	assert.True(t, history.Closed)
+	assert.Empty(t, history.NextPageToken)
+}
+
+func TestHistoryPageSendsTokenAndWait(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "run-1", r.URL.Query().Get("runId"))
+		assert.Equal(t, "p2", r.URL.Query().Get("nextPageToken"))
+		assert.Equal(t, "true", r.URL.Query().Get("wait"))
+		writeJSON(w, http.StatusOK, History{WorkflowID: "order-1", Events: []HistoryEvent{}, NextPageToken: "p2"})
+	}, Options{})
+
+	history, err := c.HistoryPage(context.Background(), "order-1", HistoryOptions{RunID: "run-1", NextPageToken: "p2", Wait: true})
+	require.NoError(t, err)
+	assert.Empty(t, history.Events)
+	assert.Equal(t, "p2", history.NextPageToken)
+}
+
+func TestWaitForCompletionPollsUntilDone(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		status := StatusRunning
+		if calls.Add(1) >= 3 {
+			status = StatusCompleted
+		}
+		writeJSON(w, http.StatusOK, WorkflowStatus{WorkflowID: "wf", RunID: "run-1", Status: status, Result: "ok"})
+	}, Options{PollInterval: time.Millisecond})
+
+	status, err := c.WaitForCompletion(context.Background(), "wf", "run-1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, status.Status)
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+func TestWithClusterPinsFollowUpCalls(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/workflows/start" {
+			assert.Empty(t, r.Header.Get(clusterHeader))
+			writeJSON(w, http.StatusOK, StartResponse{WorkflowID: "wf", RunID: "run-1", Cluster: "infra-2"})
+			return
+		}
+		assert.Equal(t, "infra-2", r.Header.Get(clusterHeader))
+		writeJSON(w, http.StatusOK, WorkflowStatus{WorkflowID: "wf", RunID: "run-1", Status: StatusCompleted})
+	}, Options{PollInterval: time.Millisecond})
+
+	resp, err := c.Start(context.Background(), StartRequest{WorkflowID: "wf"})
+	require.NoError(t, err)
+	status, err := c.WithCluster(resp.Cluster).WaitForCompletion(context.Background(), "wf", resp.RunID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, status.Status)
+	assert.Same(t, c, c.WithCluster(""))
+}
+
+func TestWaitForCompletionHonorsContext(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, WorkflowStatus{WorkflowID: "wf", Status: StatusRunning})
+	}, Options{PollInterval: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	status, err := c.WaitForCompletion(ctx, "wf", "")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, StatusRunning, status.Status)
+}
+
+func TestRetryDelayStaysWithinBounds(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, InitialInterval: 100 * time.Millisecond, MaxInterval: time.Second}
+	for attempt := 1; attempt <= 10; attempt++ {
+		d := policy.delay(attempt)
+		assert.LessOrEqual(t, d, policy.MaxInterval)
+		assert.GreaterOrEqual(t, d, policy.InitialInterval/2)
+	}
+}
+
+func TestNewRejectsInvalidBaseURL(t *testing.T) {
+	_, err := New(Options{BaseURL: "localhost:8080"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/temporal-aws-poc/apiclient"
+	"github.com/temporal-aws-poc/contracts"
+)
+
+func TestParseMix(t *testing.T) {
+	mix, err := parseMix("WorkflowB=3, WorkflowA,pipeline:orders=2")
+	require.NoError(t, err)
+	assert.Equal(t, []mixEntry{{"WorkflowA", 1}, {"WorkflowB", 3}, {"pipeline:orders", 2}}, mix.entries)
+	assert.Equal(t, 6, mix.total)
+
+	for _, value := range []string{"", "WorkflowA=0", "WorkflowA=x", "BatchWorkflow", "pipeline:", "WorkflowA,WorkflowA"} {
+		_, err := parseMix(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestMixPicksByWeight(t *testing.T) {
+	mix, err := parseMix("WorkflowA=3,WorkflowB=1")
+	require.NoError(t, err)
+
+	r := rand.New(rand.NewSource(1))
+	picks := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		picks[mix.pick(r)]++
+	}
+	assert.InDelta(t, 3000, picks[contracts.WorkflowA], 150)
+	assert.InDelta(t, 1000, picks[contracts.WorkflowB], 150)
+}
+
+func TestStartRequestForPipeline(t *testing.T) {
+	req := startRequest("pipeline:orders", "id-1", nil)
+	assert.Equal(t, "orders", req.Pipeline)
+	assert.Empty(t, req.WorkflowType)
+
+	req = startRequest(contracts.WorkflowC, "id-2", nil)
+	assert.Equal(t, contracts.WorkflowC, req.WorkflowType)
+}
+
+func TestLatencyStats(t *testing.T) {
+	var samples []time.Duration
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+	stats := latencyStats(samples)
+	assert.Equal(t, 100, stats.Count)
+	assert.Equal(t, 1.0, stats.Min)
+	assert.Equal(t, 50.0, stats.P50)
+	assert.Equal(t, 95.0, stats.P95)
+	assert.Equal(t, 99.0, stats.P99)
+	assert.Equal(t, 100.0, stats.Max)
+	assert.Equal(t, 50.5, stats.Mean)
+
+	assert.Equal(t, LatencyStats{}, latencyStats(nil))
+}
+
+// fakeAPI rechaza uno de cada cinco inicios con 503 y hace fallar los
+// workflows de tipo WorkflowB
+func fakeAPI(t *testing.T) *httptest.Server {
+	var starts int64
+	var mu sync.Mutex
+	types := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/workflows/start":
+			var req apiclient.StartRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			if atomic.AddInt64(&starts, 1)%5 == 0 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				json.NewEncoder(w).Encode(apiclient.APIError{Code: "Not connected to Temporal cluster default"})
+				return
+			}
+			mu.Lock()
+			types[req.WorkflowID] = req.WorkflowType
+			mu.Unlock()
+			json.NewEncoder(w).Encode(apiclient.StartResponse{WorkflowID: req.WorkflowID, RunID: "run"})
+		case "/workflows/status":
+			id := r.URL.Query().Get("workflowId")
+			mu.Lock()
+			workflowType := types[id]
+			mu.Unlock()
+			status := apiclient.WorkflowStatus{WorkflowID: id, Status: apiclient.StatusCompleted}
+			if workflowType == contracts.WorkflowB {
+				status.Status, status.ErrorType = apiclient.StatusFailed, "ActivityError"
+			}
+			json.NewEncoder(w).Encode(status)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRunReportsOutcomes(t *testing.T) {
+	server := fakeAPI(t)
+	retry := apiclient.DefaultRetryPolicy
+	retry.MaxAttempts = 1
+	client, err := apiclient.New(apiclient.Options{BaseURL: server.URL, Retry: retry, PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	mix, err := parseMix("WorkflowA=1,WorkflowB=1")
+	require.NoError(t, err)
+
+	l := &loadRun{
+		client:   client,
+		mix:      mix,
+		idPrefix: "test",
+		count:    20,
+		recorder: &recorder{},
+		rand:     rand.New(rand.NewSource(1)),
+	}
+	startedAt := time.Now()
+	load := l.run(context.Background(), 0, 4, 0, time.Minute, time.Minute)
+	report := l.recorder.report(RunConfig{}, startedAt, load, time.Since(startedAt))
+
+	assert.Equal(t, 20, report.Attempted)
+	assert.Equal(t, 16, report.Started)
+	assert.Equal(t, 4, report.Errors["start: 503 Not connected to Temporal cluster default"])
+	assert.Equal(t, report.Started, report.Completed+report.Failed)
+	assert.Equal(t, report.Completed, report.EndToEndLatency.Count)
+	assert.Equal(t, report.Failed, report.Errors["completion: Failed ActivityError"])
+	assert.Equal(t, report.Completed, report.ByType[contracts.WorkflowA].Completed)
+	assert.Zero(t, report.ByType[contracts.WorkflowB].Completed)
+	assert.Greater(t, report.Throughput.StartsPerSecond, 0.0)
+	// La carga termina al llegar a count, mucho antes de duration
+	assert.Less(t, report.LoadSeconds, 30.0)
+}
+
+func TestRunWithRateStopsAtDuration(t *testing.T) {
+	server := fakeAPI(t)
+	client, err := apiclient.New(apiclient.Options{BaseURL: server.URL, PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	mix, err := parseMix("WorkflowA")
+	require.NoError(t, err)
+
+	l := &loadRun{client: client, mix: mix, idPrefix: "rate", recorder: &recorder{}, rand: rand.New(rand.NewSource(1))}
+	startedAt := time.Now()
+	load := l.run(context.Background(), 100, 0, 1000, 200*time.Millisecond, time.Minute)
+	report := l.recorder.report(RunConfig{}, startedAt, load, time.Since(startedAt))
+
+	// ~20 inicios a 100/s en 200ms; el margen cubre la granularidad del ticker
+	assert.InDelta(t, 20, report.Attempted, 8)
+	assert.Equal(t, report.Attempted, report.Started+sumErrors(report, "start:"))
+	assert.Zero(t, report.Unfinished)
+}
+
+func sumErrors(report Report, prefix string) int {
+	total := 0
+	for kind, n := range report.Errors {
+		if strings.HasPrefix(kind, prefix) {
+			total += n
+		}
+	}
+	return total
+}
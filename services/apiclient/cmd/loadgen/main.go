@@ -0,0 +1,171 @@
+// loadgen genera carga contra el API para medir un despliegue: inicia
+// workflows a una tasa (-rate) o con una concurrencia (-concurrency) fija
+// durante -duration, mezclando tipos por peso, espera a que terminen y
+// escribe un reporte JSON con percentiles de latencia de inicio y de punta a
+// punta, errores agrupados por causa y throughput.
+//
+// Uso:
+//
+//	ALB_DNS=$(terraform -chdir=infra output -raw alb_dns_name)
+//	go run ./cmd/loadgen -url "http://${ALB_DNS}:8080" -rate 5 -duration 5m \
+//	    -mix WorkflowA=3,WorkflowB=1,pipeline:orders=1 -out report.json
+//
+// La API key se toma de -api-key o de LOADGEN_API_KEY.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/temporal-aws-poc/apiclient"
+)
+
+func main() {
+	defaultURL := os.Getenv("API_URL")
+	if defaultURL == "" {
+		defaultURL = "http://localhost:8080"
+	}
+
+	baseURL := flag.String("url", defaultURL, "API base URL (defaults to API_URL)")
+	apiKey := flag.String("api-key", os.Getenv("LOADGEN_API_KEY"), "API key (defaults to LOADGEN_API_KEY)")
+	namespace := flag.String("namespace", "", "Temporal namespace (defaults to the API's)")
+	cluster := flag.String("cluster", "", "Temporal cluster (defaults to the API's)")
+	rate := flag.Float64("rate", 0, "workflow starts per second (open loop)")
+	concurrency := flag.Int("concurrency", 0, "workflows in flight at once (closed loop); defaults to 5 without -rate")
+	maxInFlight := flag.Int("max-in-flight", 1000, "with -rate, skip starts while this many workflows are in flight")
+	duration := flag.Duration("duration", time.Minute, "how long to keep starting workflows")
+	count := flag.Int("count", 0, "stop after starting this many workflows (0 is no limit)")
+	mixFlag := flag.String("mix", "WorkflowA", "weighted workflow types: WorkflowA=3,WorkflowB=1,pipeline:<name>=1")
+	inputPath := flag.String("input", "", "JSON file with the input of every workflow")
+	idPrefix := flag.String("id-prefix", "", "workflow ID prefix (defaults to loadgen-<unix time>)")
+	drainTimeout := flag.Duration("drain-timeout", 5*time.Minute, "how long to wait for workflows still in flight after -duration")
+	pollInterval := flag.Duration("poll-interval", time.Second, "status polling interval while waiting for completion")
+	retries := flag.Int("retries", 0, "retries on 429/503; 0 counts every rejection as an error")
+	out := flag.String("out", "", "report file (defaults to stdout)")
+	flag.Parse()
+
+	if *rate > 0 && *concurrency > 0 {
+		log.Fatalf("-rate and -concurrency are mutually exclusive")
+	}
+	if *rate < 0 || *concurrency < 0 || *maxInFlight <= 0 || *duration <= 0 || *retries < 0 {
+		log.Fatalf("-rate, -concurrency and -retries cannot be negative; -max-in-flight and -duration must be positive")
+	}
+	if *rate == 0 && *concurrency == 0 {
+		*concurrency = 5
+	}
+	mix, err := parseMix(*mixFlag)
+	if err != nil {
+		log.Fatalf("Invalid -mix: %v", err)
+	}
+	input := map[string]interface{}{"message": "loadgen"}
+	if *inputPath != "" {
+		data, err := os.ReadFile(*inputPath)
+		if err == nil {
+			err = json.Unmarshal(data, &input)
+		}
+		if err != nil {
+			log.Fatalf("Invalid -input: %v", err)
+		}
+	}
+	if *idPrefix == "" {
+		*idPrefix = fmt.Sprintf("loadgen-%d", time.Now().Unix())
+	}
+
+	// Sin reintentos por defecto: un 429 o 503 es parte de lo que se mide
+	retry := apiclient.DefaultRetryPolicy
+	retry.MaxAttempts = *retries + 1
+	// El transporte por defecto guarda 2 conexiones por host; con más
+	// workflows en curso se abrirían y cerrarían conexiones todo el tiempo
+	connections := *concurrency
+	if *rate > 0 {
+		connections = *maxInFlight
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = connections
+
+	client, err := apiclient.New(apiclient.Options{
+		BaseURL:      *baseURL,
+		APIKey:       *apiKey,
+		Namespace:    *namespace,
+		Cluster:      *cluster,
+		HTTPClient:   &http.Client{Timeout: 30 * time.Second, Transport: transport},
+		Retry:        retry,
+		PollInterval: *pollInterval,
+	})
+	if err != nil {
+		log.Fatalf("Invalid API configuration: %v", err)
+	}
+
+	config := RunConfig{
+		URL:          *baseURL,
+		Namespace:    *namespace,
+		Cluster:      *cluster,
+		Rate:         *rate,
+		Concurrency:  *concurrency,
+		Duration:     duration.String(),
+		Count:        *count,
+		Mix:          mix.entries,
+		IDPrefix:     *idPrefix,
+		DrainTimeout: drainTimeout.String(),
+	}
+	if *rate > 0 {
+		config.MaxInFlight = *maxInFlight
+	}
+
+	// Ctrl-C corta la corrida pero igual escribe el reporte de lo hecho
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	l := &loadRun{
+		client:   client,
+		mix:      mix,
+		input:    input,
+		idPrefix: *idPrefix,
+		count:    *count,
+		recorder: &recorder{},
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	log.Printf("Starting load against %s: %s", *baseURL, describeLoad(config))
+	startedAt := time.Now()
+	load := l.run(ctx, *rate, *concurrency, *maxInFlight, *duration, *drainTimeout)
+	report := l.recorder.report(config, startedAt, load, time.Since(startedAt))
+
+	if err := writeReport(*out, report); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+	log.Printf("Done: %d attempted, %d started, %d completed, %d failed, %d unfinished",
+		report.Attempted, report.Started, report.Completed, report.Failed, report.Unfinished)
+}
+
+func describeLoad(config RunConfig) string {
+	if config.Rate > 0 {
+		return fmt.Sprintf("%.2f starts/s for %s", config.Rate, config.Duration)
+	}
+	return fmt.Sprintf("%d concurrent workflows for %s", config.Concurrency, config.Duration)
+}
+
+// writeReport escribe el reporte indentado en path, o en stdout si está vacío
+func writeReport(path string, report Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	log.Printf("Report written to %s", path)
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/temporal-aws-poc/apiclient"
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// pipelinePrefix marca en la mezcla un pipeline de DSLWorkflow ("pipeline:orders")
+const pipelinePrefix = "pipeline:"
+
+// mixEntry es un tipo de workflow de la mezcla con su peso
+type mixEntry struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
+}
+
+// workflowMix elige el tipo de cada workflow al azar, en proporción a su peso
+type workflowMix struct {
+	entries []mixEntry
+	total   int
+}
+
+// parseMix interpreta "WorkflowA=3,WorkflowB=1,pipeline:orders=1". Un tipo
+// sin peso vale 1. BatchWorkflow no entra: necesita sus items.
+func parseMix(value string) (*workflowMix, error) {
+	mix := &workflowMix{}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, weightStr, hasWeight := strings.Cut(part, "=")
+		weight := 1
+		if hasWeight {
+			var err error
+			if weight, err = strconv.Atoi(weightStr); err != nil || weight <= 0 {
+				return nil, fmt.Errorf("invalid weight %q for %s: must be a positive integer", weightStr, name)
+			}
+		}
+		if !validMixName(name) {
+			return nil, fmt.Errorf("unknown workflow type %q: use WorkflowA-D or %s<name>", name, pipelinePrefix)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("workflow type %s appears twice", name)
+		}
+		seen[name] = true
+		mix.entries = append(mix.entries, mixEntry{Name: name, Weight: weight})
+		mix.total += weight
+	}
+	if len(mix.entries) == 0 {
+		return nil, fmt.Errorf("the mix needs at least one workflow type")
+	}
+	sort.Slice(mix.entries, func(i, j int) bool { return mix.entries[i].Name < mix.entries[j].Name })
+	return mix, nil
+}
+
+func validMixName(name string) bool {
+	switch name {
+	case contracts.WorkflowA, contracts.WorkflowB, contracts.WorkflowC, contracts.WorkflowD:
+		return true
+	}
+	return strings.HasPrefix(name, pipelinePrefix) && len(name) > len(pipelinePrefix)
+}
+
+// pick elige un tipo; r se pasa para que los tests sean deterministas
+func (m *workflowMix) pick(r *rand.Rand) string {
+	n := r.Intn(m.total)
+	for _, entry := range m.entries {
+		if n < entry.Weight {
+			return entry.Name
+		}
+		n -= entry.Weight
+	}
+	return m.entries[len(m.entries)-1].Name
+}
+
+// startRequest arma el request para un tipo de la mezcla
+func startRequest(name, workflowID string, input map[string]interface{}) apiclient.StartRequest {
+	req := apiclient.StartRequest{WorkflowID: workflowID, Input: input}
+	if pipeline := strings.TrimPrefix(name, pipelinePrefix); pipeline != name {
+		req.Pipeline = pipeline
+	} else {
+		req.WorkflowType = name
+	}
+	return req
+}
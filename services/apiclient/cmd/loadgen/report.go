@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/temporal-aws-poc/apiclient"
+)
+
+// Report es el resultado de una corrida, pensado para comparar corridas
+type Report struct {
+	Config     RunConfig `json:"config"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	// LoadSeconds es la fase de carga (iniciar workflows); ElapsedSeconds
+	// incluye la espera de los que quedaron en curso
+	LoadSeconds    float64 `json:"loadSeconds"`
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+
+	Totals
+	// Skipped son los inicios que no se hicieron en modo rate por tener
+	// maxInFlight workflows en curso: el sistema no da abasto a esa tasa
+	Skipped    int        `json:"skipped,omitempty"`
+	Throughput Throughput `json:"throughput"`
+	// Errors cuenta los errores por fase y causa ("start: 503 Not connected
+	// to Temporal cluster default", "completion: Failed")
+	Errors map[string]int    `json:"errors"`
+	ByType map[string]Totals `json:"byType"`
+}
+
+// Totals son los contadores y latencias de un conjunto de workflows
+type Totals struct {
+	Attempted int `json:"attempted"`
+	Started   int `json:"started"`
+	Completed int `json:"completed"`
+	// Failed son los workflows que cerraron sin completarse
+	Failed int `json:"failed"`
+	// Unfinished son aquellos cuyo fin no se llegó a saber: seguían en curso
+	// al vencer drainTimeout o falló la consulta de estado
+	Unfinished      int          `json:"unfinished"`
+	StartLatency    LatencyStats `json:"startLatencyMs"`
+	EndToEndLatency LatencyStats `json:"endToEndLatencyMs"`
+}
+
+// Throughput son las tasas por segundo de la corrida
+type Throughput struct {
+	StartsPerSecond      float64 `json:"startsPerSecond"`
+	CompletionsPerSecond float64 `json:"completionsPerSecond"`
+}
+
+// LatencyStats son los percentiles de una latencia, en milisegundos
+type LatencyStats struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Mean  float64 `json:"mean"`
+	P50   float64 `json:"p50"`
+	P90   float64 `json:"p90"`
+	P95   float64 `json:"p95"`
+	P99   float64 `json:"p99"`
+	Max   float64 `json:"max"`
+}
+
+// outcome es lo que pasó con un workflow de la corrida
+type outcome struct {
+	workflowType string
+	started      bool
+	startLatency time.Duration
+	// status es el estado final; vacío si no se llegó a saber
+	status    string
+	completed bool
+	endToEnd  time.Duration
+	err       string
+}
+
+// recorder junta los resultados de los workers
+type recorder struct {
+	mu       sync.Mutex
+	outcomes []outcome
+	skipped  int
+}
+
+func (r *recorder) record(o outcome) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outcomes = append(r.outcomes, o)
+}
+
+func (r *recorder) skip() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.skipped++
+}
+
+// report arma el reporte; load es la duración de la fase de carga y elapsed
+// la de toda la corrida
+func (r *recorder) report(config RunConfig, startedAt time.Time, load, elapsed time.Duration) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := Report{
+		Config:         config,
+		StartedAt:      startedAt,
+		FinishedAt:     startedAt.Add(elapsed),
+		LoadSeconds:    load.Seconds(),
+		ElapsedSeconds: elapsed.Seconds(),
+		Skipped:        r.skipped,
+		Errors:         map[string]int{},
+		ByType:         map[string]Totals{},
+	}
+
+	all := &accumulator{}
+	byType := map[string]*accumulator{}
+	for _, o := range r.outcomes {
+		if o.err != "" {
+			report.Errors[o.err]++
+		}
+		all.add(o)
+		if byType[o.workflowType] == nil {
+			byType[o.workflowType] = &accumulator{}
+		}
+		byType[o.workflowType].add(o)
+	}
+	report.Totals = all.totals()
+	for name, acc := range byType {
+		report.ByType[name] = acc.totals()
+	}
+
+	if load > 0 {
+		report.Throughput.StartsPerSecond = float64(report.Started) / load.Seconds()
+	}
+	if elapsed > 0 {
+		report.Throughput.CompletionsPerSecond = float64(report.Completed) / elapsed.Seconds()
+	}
+	return report
+}
+
+type accumulator struct {
+	counts    Totals
+	starts    []time.Duration
+	endToEnds []time.Duration
+}
+
+func (a *accumulator) add(o outcome) {
+	a.counts.Attempted++
+	if !o.started {
+		return
+	}
+	a.counts.Started++
+	a.starts = append(a.starts, o.startLatency)
+	switch {
+	case o.status == "":
+		a.counts.Unfinished++
+	case o.completed:
+		a.counts.Completed++
+		a.endToEnds = append(a.endToEnds, o.endToEnd)
+	default:
+		a.counts.Failed++
+	}
+}
+
+func (a *accumulator) totals() Totals {
+	t := a.counts
+	t.StartLatency = latencyStats(a.starts)
+	t.EndToEndLatency = latencyStats(a.endToEnds)
+	return t
+}
+
+// latencyStats calcula los percentiles por nearest-rank
+func latencyStats(samples []time.Duration) LatencyStats {
+	if len(samples) == 0 {
+		return LatencyStats{}
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		return millis(sorted[rank])
+	}
+	return LatencyStats{
+		Count: len(sorted),
+		Min:   millis(sorted[0]),
+		Mean:  millis(sum / time.Duration(len(sorted))),
+		P50:   percentile(0.50),
+		P90:   percentile(0.90),
+		P95:   percentile(0.95),
+		P99:   percentile(0.99),
+		Max:   millis(sorted[len(sorted)-1]),
+	}
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// errorKind resume un error para agruparlo: el código y error del API, o
+// la causa del fallo de red sin el request que lo produjo
+func errorKind(phase string, err error) string {
+	var apiErr *apiclient.APIError
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &apiErr):
+		return fmt.Sprintf("%s: %d %s", phase, apiErr.StatusCode, apiErr.Code)
+	case errors.Is(err, context.DeadlineExceeded):
+		return phase + ": timeout"
+	case errors.As(err, &urlErr):
+		return fmt.Sprintf("%s: %v", phase, urlErr.Err)
+	}
+	return fmt.Sprintf("%s: %v", phase, err)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/temporal-aws-poc/apiclient"
+)
+
+// RunConfig describe la corrida; va en el reporte para saber qué se comparó
+type RunConfig struct {
+	URL       string `json:"url"`
+	Namespace string `json:"namespace,omitempty"`
+	Cluster   string `json:"cluster,omitempty"`
+	// Rate son los inicios por segundo (lazo abierto); Concurrency los
+	// workflows en curso a la vez (lazo cerrado). Se usa uno de los dos.
+	Rate        float64 `json:"rate,omitempty"`
+	Concurrency int     `json:"concurrency,omitempty"`
+	// MaxInFlight limita los workflows en curso en modo rate
+	MaxInFlight int        `json:"maxInFlight,omitempty"`
+	Duration    string     `json:"duration"`
+	Count       int        `json:"count,omitempty"`
+	Mix         []mixEntry `json:"mix"`
+	IDPrefix    string     `json:"idPrefix"`
+	// DrainTimeout es cuánto se espera, al terminar la carga, a los
+	// workflows que siguen en curso
+	DrainTimeout string `json:"drainTimeout"`
+}
+
+// loadRun es una corrida en curso
+type loadRun struct {
+	client   *apiclient.Client
+	mix      *workflowMix
+	input    map[string]interface{}
+	idPrefix string
+	count    int
+	recorder *recorder
+
+	seq      int64
+	inFlight int64
+
+	randMu sync.Mutex
+	rand   *rand.Rand
+}
+
+// run inicia workflows hasta que vence duration (o se llega a count) y
+// espera a los que quedaron en curso hasta drainTimeout. Devuelve la
+// duración de la fase de carga.
+func (l *loadRun) run(ctx context.Context, rate float64, concurrency, maxInFlight int, duration, drainTimeout time.Duration) time.Duration {
+	start := time.Now()
+	loadCtx, cancelLoad := context.WithTimeout(ctx, duration)
+	defer cancelLoad()
+	// Las esperas de completion tienen su propio plazo, más allá de la carga
+	waitCtx, cancelWait := context.WithDeadline(ctx, start.Add(duration+drainTimeout))
+	defer cancelWait()
+
+	stopProgress := l.logProgress(10 * time.Second)
+	defer stopProgress()
+
+	var wg sync.WaitGroup
+	if rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+	loop:
+		for {
+			select {
+			case <-loadCtx.Done():
+				break loop
+			case <-ticker.C:
+			}
+			seq, ok := l.next()
+			if !ok {
+				break loop
+			}
+			// Lazo abierto: no se espera al sistema, pero se acota la memoria
+			if atomic.LoadInt64(&l.inFlight) >= int64(maxInFlight) {
+				l.recorder.skip()
+				continue
+			}
+			atomic.AddInt64(&l.inFlight, 1)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer atomic.AddInt64(&l.inFlight, -1)
+				l.runOne(waitCtx, seq)
+			}()
+		}
+	} else {
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for loadCtx.Err() == nil {
+					seq, ok := l.next()
+					if !ok {
+						return
+					}
+					atomic.AddInt64(&l.inFlight, 1)
+					l.runOne(waitCtx, seq)
+					atomic.AddInt64(&l.inFlight, -1)
+				}
+			}()
+		}
+	}
+
+	// En modo concurrency la carga termina cuando vence duration aunque los
+	// workers sigan esperando su último workflow
+	if rate <= 0 {
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-loadCtx.Done():
+		case <-done:
+		}
+	}
+	load := time.Since(start)
+	if load > duration {
+		load = duration
+	}
+
+	log.Printf("Load phase finished, waiting for %d workflows in flight", atomic.LoadInt64(&l.inFlight))
+	wg.Wait()
+	return load
+}
+
+// next reserva el número del próximo workflow; false si ya se llegó a count
+func (l *loadRun) next() (int64, bool) {
+	seq := atomic.AddInt64(&l.seq, 1)
+	return seq, l.count <= 0 || seq <= int64(l.count)
+}
+
+// runOne inicia un workflow y espera a que termine
+func (l *loadRun) runOne(waitCtx context.Context, seq int64) {
+	l.randMu.Lock()
+	name := l.mix.pick(l.rand)
+	l.randMu.Unlock()
+
+	o := outcome{workflowType: name}
+	defer func() { l.recorder.record(o) }()
+
+	workflowID := fmt.Sprintf("%s-%06d", l.idPrefix, seq)
+	begin := time.Now()
+	resp, err := l.client.Start(waitCtx, startRequest(name, workflowID, l.input))
+	if err != nil {
+		o.err = errorKind("start", err)
+		return
+	}
+	o.started = true
+	o.startLatency = time.Since(begin)
+
+	// El API retiene la consulta de estado mientras el workflow sigue en
+	// curso, así que el fin se detecta casi en el momento. La consulta va al
+	// cluster donde se inició, que con failover no es el por defecto.
+	status, err := l.client.WithCluster(resp.Cluster).WaitForCompletion(waitCtx, workflowID, resp.RunID)
+	if err != nil {
+		o.err = errorKind("completion", err)
+		return
+	}
+	o.status = status.Status
+	o.endToEnd = time.Since(begin)
+	o.completed = status.Completed()
+	if !o.completed {
+		o.err = "completion: " + status.Status
+		if status.ErrorType != "" {
+			o.err += " " + status.ErrorType
+		}
+	}
+}
+
+// logProgress informa el avance cada interval hasta que se llama a la
+// función devuelta
+func (l *loadRun) logProgress(interval time.Duration) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				l.recorder.mu.Lock()
+				finished, failed := len(l.recorder.outcomes), 0
+				for _, o := range l.recorder.outcomes {
+					if o.err != "" {
+						failed++
+					}
+				}
+				l.recorder.mu.Unlock()
+				log.Printf("Progress: %d finished (%d with errors), %d in flight", finished, failed, atomic.LoadInt64(&l.inFlight))
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/temporal-aws-poc/apiclient"
+	"github.com/temporal-aws-poc/contracts"
+)
+
+var startCommand = command{
+	usage:   "[flags]",
+	summary: "Start a workflow of any type, with its input read from a file or stdin.",
+	setup: func(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+		workflowType := fs.String("type", contracts.WorkflowA, "workflow type: WorkflowA-D, DSLWorkflow (needs -pipeline) or BatchWorkflow")
+		workflowID := fs.String("id", "", "workflow ID (defaults to <type>-<timestamp>)")
+		inputPath := fs.String("input", "", "JSON input file, or - for stdin; for BatchWorkflow it is the batch request")
+		pipeline := fs.String("pipeline", "", "pipeline name for DSLWorkflow")
+		sla := fs.String("sla", "", "execution SLA, such as 30m")
+		activityProfile := fs.String("activity-profile", "", "ActivityOptions profile for every activity")
+		attributes := keyValues{}
+		fs.Var(attributes, "attr", "search attribute key=value (repeatable)")
+
+		return func(ctx context.Context, e *env, args []string) error {
+			if err := requireArgs(args, 0, 0); err != nil {
+				return err
+			}
+			req := apiclient.StartRequest{
+				WorkflowID:      *workflowID,
+				SLA:             *sla,
+				ActivityProfile: *activityProfile,
+			}
+			if len(attributes) > 0 {
+				req.SearchAttributes = attributes
+			}
+			if req.WorkflowID == "" {
+				req.WorkflowID = fmt.Sprintf("%s-%d", strings.ToLower(*workflowType), time.Now().Unix())
+			}
+
+			switch *workflowType {
+			case contracts.DSLWorkflow:
+				if *pipeline == "" {
+					return usageError("-pipeline is required for " + contracts.DSLWorkflow)
+				}
+				req.Pipeline = *pipeline
+				if _, err := readInput(*inputPath, os.Stdin, &req.Input); err != nil {
+					return err
+				}
+			case contracts.BatchWorkflow:
+				var batch apiclient.BatchRequest
+				ok, err := readInput(*inputPath, os.Stdin, &batch)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return usageError("-input with the batch request is required for " + contracts.BatchWorkflow)
+				}
+				req.Batch = &batch
+			default:
+				if *pipeline != "" {
+					return usageError("-pipeline only applies to " + contracts.DSLWorkflow)
+				}
+				req.WorkflowType = *workflowType
+				if _, err := readInput(*inputPath, os.Stdin, &req.Input); err != nil {
+					return err
+				}
+			}
+
+			resp, err := e.client.Start(ctx, req)
+			if err != nil {
+				return err
+			}
+			if e.output == "json" {
+				return printJSON(resp)
+			}
+			t := newTable("WORKFLOW ID", "RUN ID", "CLUSTER")
+			t.row(resp.WorkflowID, resp.RunID, resp.Cluster)
+			return t.flush()
+		}
+	},
+}
+
+var statusCommand = command{
+	usage:   "<workflowId> [flags]",
+	summary: "Show the status of an execution, its result or error and its related workflows.",
+	setup: func(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+		runID := fs.String("run-id", "", "run ID (defaults to the latest run)")
+
+		return func(ctx context.Context, e *env, args []string) error {
+			if err := requireArgs(args, 1, 1); err != nil {
+				return err
+			}
+			status, err := e.client.Status(ctx, args[0], *runID)
+			if err != nil {
+				return err
+			}
+			if e.output == "json" {
+				return printJSON(status)
+			}
+			printStatus(status)
+			return nil
+		}
+	},
+}
+
+var watchCommand = command{
+	usage:   "<workflowId> [flags]",
+	summary: "Follow the steps of an execution as they happen, until it closes.",
+	setup: func(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+		runID := fs.String("run-id", "", "run ID (defaults to the latest run)")
+		interval := fs.Duration("interval", 2*time.Second, "polling interval")
+		all := fs.Bool("all", false, "show every history event, not only the steps")
+
+		return func(ctx context.Context, e *env, args []string) error {
+			if err := requireArgs(args, 1, 1); err != nil {
+				return err
+			}
+			// Columnas de ancho fijo: las filas se imprimen a medida que llegan
+			// y no se pueden alinear con las que faltan
+			const rowFormat = "%-6v %-19s %-42s %s\n"
+			if e.output == "table" {
+				fmt.Printf(rowFormat, "EVENT", "TIME", "TYPE", "DETAILS")
+			}
+
+			// Con Wait el token sigue desde el último evento recibido y el API
+			// espera los nuevos, sin volver a leer el historial en cada vuelta
+			options := apiclient.HistoryOptions{RunID: *runID, Wait: true}
+			for {
+				history, err := e.client.HistoryPage(ctx, args[0], options)
+				if err != nil {
+					return err
+				}
+				for _, event := range history.Events {
+					if !*all && !isStepEvent(event.EventType) {
+						continue
+					}
+					if e.output == "json" {
+						// Una línea JSON por evento, para procesar con jq a medida que llegan
+						if err := printCompactJSON(event); err != nil {
+							return err
+						}
+						continue
+					}
+					fmt.Printf(rowFormat, event.EventID, formatTime(event.EventTime), event.EventType, event.Details)
+				}
+				if history.Closed || history.NextPageToken == "" {
+					return nil
+				}
+				options.NextPageToken = history.NextPageToken
+				if len(history.Events) > 0 {
+					// Quedan páginas o eventos nuevos: se piden sin esperar
+					continue
+				}
+
+				timer := time.NewTimer(*interval)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+	},
+}
+
+var listCommand = command{
+	usage:   "[flags]",
+	summary: "List executions, filtered by type, status and search attributes.",
+	setup: func(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+		workflowType := fs.String("type", "", "workflow type")
+		status := fs.String("status", "", "execution status (Running, Completed, Failed, ...)")
+		pageSize := fs.Int("limit", 20, "page size")
+		pageToken := fs.String("page-token", "", "token of the page to show, from a previous list")
+		attributes := keyValues{}
+		fs.Var(attributes, "attr", "search attribute filter key=value (repeatable)")
+
+		return func(ctx context.Context, e *env, args []string) error {
+			if err := requireArgs(args, 0, 0); err != nil {
+				return err
+			}
+			resp, err := e.client.List(ctx, apiclient.ListOptions{
+				WorkflowType:     *workflowType,
+				Status:           *status,
+				SearchAttributes: attributes,
+				PageSize:         *pageSize,
+				NextPageToken:    *pageToken,
+			})
+			if err != nil {
+				return err
+			}
+			if e.output == "json" {
+				return printJSON(resp)
+			}
+
+			t := newTable("WORKFLOW ID", "TYPE", "STATUS", "STARTED", "CLOSED", "SEARCH ATTRIBUTES")
+			for _, wf := range resp.Workflows {
+				closed := ""
+				if wf.CloseTime != nil {
+					closed = formatTime(*wf.CloseTime)
+				}
+				t.row(wf.WorkflowID, wf.WorkflowType, wf.Status, formatTime(wf.StartTime), closed, formatAttributes(wf.SearchAttributes))
+			}
+			if err := t.flush(); err != nil {
+				return err
+			}
+			if resp.NextPageToken != "" {
+				fmt.Fprintf(os.Stderr, "\nMore results: -page-token %s\n", resp.NextPageToken)
+			}
+			return nil
+		}
+	},
+}
+
+var signalCommand = command{
+	usage:   "<workflowId> <signalName> [flags]",
+	summary: "Send a signal to an execution, with an optional JSON payload.",
+	setup: func(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+		runID := fs.String("run-id", "", "run ID (defaults to the latest run)")
+		inputPath := fs.String("input", "", "JSON payload file, or - for stdin")
+
+		return func(ctx context.Context, e *env, args []string) error {
+			if err := requireArgs(args, 2, 2); err != nil {
+				return err
+			}
+			req := apiclient.SignalRequest{WorkflowID: args[0], RunID: *runID, SignalName: args[1]}
+			if _, err := readInput(*inputPath, os.Stdin, &req.Input); err != nil {
+				return err
+			}
+			if err := e.client.Signal(ctx, req); err != nil {
+				return err
+			}
+			return printDone(e, args[0], "Signal "+args[1]+" sent")
+		}
+	},
+}
+
+var cancelCommand = command{
+	usage:   "<workflowId> [flags]",
+	summary: "Request the cancellation of an execution.",
+	setup: func(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+		runID := fs.String("run-id", "", "run ID (defaults to the latest run)")
+
+		return func(ctx context.Context, e *env, args []string) error {
+			if err := requireArgs(args, 1, 1); err != nil {
+				return err
+			}
+			if err := e.client.Cancel(ctx, args[0], *runID); err != nil {
+				return err
+			}
+			return printDone(e, args[0], "Cancellation requested")
+		}
+	},
+}
+
+var historyCommand = command{
+	usage:   "<workflowId> [flags]",
+	summary: "Show the history events of an execution.",
+	setup: func(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+		runID := fs.String("run-id", "", "run ID (defaults to the latest run)")
+		after := fs.Int64("after", 0, "only show events after this event ID")
+
+		return func(ctx context.Context, e *env, args []string) error {
+			if err := requireArgs(args, 1, 1); err != nil {
+				return err
+			}
+			history, err := e.client.History(ctx, args[0], *runID, *after)
+			if err != nil {
+				return err
+			}
+			if e.output == "json" {
+				return printJSON(history)
+			}
+			t := newTable("EVENT", "TIME", "TYPE", "DETAILS")
+			for _, event := range history.Events {
+				t.row(fmt.Sprint(event.EventID), formatTime(event.EventTime), event.EventType, event.Details)
+			}
+			return t.flush()
+		}
+	},
+}
+
+var resultCommand = command{
+	usage:   "<workflowId> [flags]",
+	summary: "Wait for an execution to close and print its result; fails if it did not complete.",
+	setup: func(fs *flag.FlagSet) func(context.Context, *env, []string) error {
+		runID := fs.String("run-id", "", "run ID (defaults to the latest run)")
+		timeout := fs.Duration("timeout", 0, "maximum time to wait (0 waits until the execution closes)")
+
+		return func(ctx context.Context, e *env, args []string) error {
+			if err := requireArgs(args, 1, 1); err != nil {
+				return err
+			}
+			if *timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, *timeout)
+				defer cancel()
+			}
+			status, err := e.client.WaitForCompletion(ctx, args[0], *runID)
+			if err != nil {
+				return err
+			}
+
+			if e.output == "json" {
+				if err := printJSON(status); err != nil {
+					return err
+				}
+			} else if status.Completed() {
+				fmt.Println(status.Result)
+			}
+			if !status.Completed() {
+				if status.Error != "" {
+					return fmt.Errorf("workflow %s %s: %s", args[0], strings.ToLower(status.Status), status.Error)
+				}
+				return fmt.Errorf("workflow %s %s", args[0], strings.ToLower(status.Status))
+			}
+			return nil
+		}
+	},
+}
+
+// isStepEvent indica si el evento marca el avance de la ejecución: las
+// activities, los child workflows, las señales, los timers y el cierre.
+// Las workflow tasks y los markers son ruido para seguirla.
+func isStepEvent(eventType string) bool {
+	switch {
+	case strings.HasPrefix(eventType, "WorkflowTask"),
+		eventType == "ActivityTaskStarted",
+		eventType == "MarkerRecorded",
+		eventType == "UpsertWorkflowSearchAttributes",
+		eventType == "WorkflowPropertiesModified":
+		return false
+	}
+	return true
+}
+
+// printStatus muestra el estado como pares campo/valor
+func printStatus(status *apiclient.WorkflowStatus) {
+	t := newTable()
+	t.row("Workflow ID:", status.WorkflowID)
+	t.row("Run ID:", status.RunID)
+	t.row("Status:", status.Status)
+	if status.Result != "" {
+		t.row("Result:", status.Result)
+	}
+	if status.Error != "" {
+		t.row("Error:", status.Error)
+	}
+	if status.ErrorType != "" {
+		t.row("Error type:", status.ErrorType)
+	}
+	if status.ErrorDetails != nil {
+		t.row("Error details:", formatValue(status.ErrorDetails))
+	}
+	if len(status.SearchAttributes) > 0 {
+		t.row("Search attributes:", formatAttributes(status.SearchAttributes))
+	}
+	if status.Parent != nil {
+		t.row("Parent:", status.Parent.WorkflowID+" ("+status.Parent.RunID+")")
+	}
+	for _, child := range status.Children {
+		t.row("Child:", fmt.Sprintf("%s %s %s", child.WorkflowID, child.WorkflowType, child.Status))
+	}
+	t.flush()
+}
+
+// printDone confirma una operación sin respuesta propia
+func printDone(e *env, workflowID, message string) error {
+	if e.output == "json" {
+		return printJSON(map[string]string{"workflowId": workflowID, "message": message})
+	}
+	fmt.Printf("%s: %s\n", workflowID, message)
+	return nil
+}
@@ -0,0 +1,157 @@
+// wfctl opera workflows a través del API: iniciarlos, consultar su estado,
+// seguir su avance, listarlos, señalarlos, cancelarlos y leer su historial o
+// su resultado. Los endpoints y credenciales se eligen por perfil (ver
+// ProfilesConfig).
+//
+// Uso:
+//
+//	go run ./cmd/wfctl start -type WorkflowB -id order-1 -input order.json
+//	go run ./cmd/wfctl watch order-1
+//	go run ./cmd/wfctl list -status Running -o json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/temporal-aws-poc/apiclient"
+)
+
+// command es un subcomando: sus flags se registran en fs y run recibe los
+// argumentos posicionales
+type command struct {
+	usage   string
+	summary string
+	setup   func(fs *flag.FlagSet) func(ctx context.Context, env *env, args []string) error
+}
+
+var commands = map[string]command{
+	"start":   startCommand,
+	"status":  statusCommand,
+	"watch":   watchCommand,
+	"list":    listCommand,
+	"signal":  signalCommand,
+	"cancel":  cancelCommand,
+	"history": historyCommand,
+	"result":  resultCommand,
+}
+
+// commandOrder es el orden de los comandos en la ayuda
+var commandOrder = []string{"start", "status", "watch", "list", "signal", "cancel", "history", "result"}
+
+// env es lo que comparten los comandos: el cliente y el formato de salida
+type env struct {
+	client *apiclient.Client
+	output string
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "help" {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("wfctl "+os.Args[1], flag.ExitOnError)
+	var conn connectionFlags
+	fs.StringVar(&conn.profile, "profile", "", "profile from the config file (defaults to WFCTL_PROFILE or current)")
+	fs.StringVar(&conn.url, "url", "", "API base URL (overrides the profile)")
+	fs.StringVar(&conn.apiKey, "api-key", "", "API key (overrides the profile)")
+	fs.StringVar(&conn.namespace, "namespace", "", "Temporal namespace (overrides the profile)")
+	fs.StringVar(&conn.cluster, "cluster", "", "Temporal cluster (overrides the profile)")
+	output := fs.String("o", "table", "output format: table or json")
+	run := cmd.setup(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: wfctl %s %s\n\n%s\n\nFlags:\n", os.Args[1], cmd.usage, cmd.summary)
+		fs.PrintDefaults()
+	}
+	args := parseInterspersed(fs, os.Args[2:])
+
+	if *output != "table" && *output != "json" {
+		fatalf("Invalid output format %q: use table or json", *output)
+	}
+	options, err := resolveOptions(conn, os.Getenv)
+	if err != nil {
+		fatalf("Invalid profile: %v", err)
+	}
+	client, err := apiclient.New(options)
+	if err != nil {
+		fatalf("Invalid API configuration: %v", err)
+	}
+
+	// Ctrl-C corta watch y result sin dejar el comando colgado
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, &env{client: client, output: *output}, args); err != nil {
+		var usageErr usageError
+		if errors.As(err, &usageErr) {
+			fmt.Fprintf(os.Stderr, "%v\n\n", err)
+			fs.Usage()
+			os.Exit(2)
+		}
+		stop()
+		fatalf("%v", err)
+	}
+}
+
+// parseInterspersed permite flags antes y después de los argumentos
+// posicionales ("wfctl status order-1 -o json")
+func parseInterspersed(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// usageError es un error en los argumentos; se muestra junto con la ayuda
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+// requireArgs valida la cantidad de argumentos posicionales
+func requireArgs(args []string, min, max int) error {
+	if len(args) < min || len(args) > max {
+		return usageError(fmt.Sprintf("expected %s, got %d", argCount(min, max), len(args)))
+	}
+	return nil
+}
+
+func argCount(min, max int) string {
+	if min == max {
+		return fmt.Sprintf("%d argument(s)", min)
+	}
+	return fmt.Sprintf("%d to %d arguments", min, max)
+}
+
+func usage() {
+	var b strings.Builder
+	b.WriteString("Usage: wfctl <command> [flags] [arguments]\n\nCommands:\n")
+	for _, name := range commandOrder {
+		fmt.Fprintf(&b, "  %-8s %s\n", name, commands[name].summary)
+	}
+	b.WriteString("\nRun \"wfctl <command> -h\" for the flags of each command.\n")
+	b.WriteString("Profiles are read from WFCTL_CONFIG or ~/.config/wfctl/config.yaml.\n")
+	fmt.Fprint(os.Stderr, b.String())
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
+	os.Exit(1)
+}
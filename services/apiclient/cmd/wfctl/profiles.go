@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/temporal-aws-poc/apiclient"
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// defaultURL es el API local de docker-compose
+const defaultURL = "http://localhost:8080"
+
+// ProfilesConfig es el archivo de perfiles (YAML o JSON), por defecto
+// ~/.config/wfctl/config.yaml o el de WFCTL_CONFIG:
+//
+//	current: local
+//	profiles:
+//	  local:
+//	    url: http://localhost:8080
+//	  staging:
+//	    url: https://workflows.staging.internal
+//	    apiKeyEnv: WFCTL_STAGING_API_KEY
+//	    namespace: staging
+//	    cluster: infra-2
+type ProfilesConfig struct {
+	// Current es el perfil cuando no se indica --profile ni WFCTL_PROFILE
+	Current  string             `json:"current,omitempty"`
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// Profile es un endpoint del API con sus credenciales
+type Profile struct {
+	URL string `json:"url"`
+	// La API key va en el archivo (apiKey) o, mejor, en una variable de
+	// entorno (apiKeyEnv); no ambas
+	APIKey    string `json:"apiKey,omitempty"`
+	APIKeyEnv string `json:"apiKeyEnv,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Cluster   string `json:"cluster,omitempty"`
+}
+
+// configPath es el archivo de perfiles: WFCTL_CONFIG o el directorio de
+// configuración del usuario
+func configPath(getenv func(string) string) string {
+	if path := getenv("WFCTL_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "wfctl", "config.yaml")
+}
+
+// loadProfiles lee y valida el archivo de perfiles
+func loadProfiles(path string) (ProfilesConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ProfilesConfig{}, err
+	}
+
+	var config ProfilesConfig
+	if err := contracts.DecodeYAMLStrict(data, &config); err != nil {
+		return ProfilesConfig{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return config, config.validate()
+}
+
+func (c ProfilesConfig) validate() error {
+	for name, profile := range c.Profiles {
+		if profile.URL == "" {
+			return fmt.Errorf("profile %s: url is required", name)
+		}
+		if profile.APIKey != "" && profile.APIKeyEnv != "" {
+			return fmt.Errorf("profile %s: apiKey and apiKeyEnv are mutually exclusive", name)
+		}
+	}
+	if c.Current != "" {
+		if _, ok := c.Profiles[c.Current]; !ok {
+			return fmt.Errorf("current profile %s is not declared", c.Current)
+		}
+	}
+	return nil
+}
+
+// names devuelve los perfiles declarados, ordenados
+func (c ProfilesConfig) names() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// connectionFlags son los flags comunes a todos los comandos; los no vacíos
+// reemplazan los valores del perfil
+type connectionFlags struct {
+	profile   string
+	url       string
+	apiKey    string
+	namespace string
+	cluster   string
+}
+
+// resolveOptions arma las opciones del cliente: el perfil de --profile,
+// WFCTL_PROFILE o el current del archivo, y encima los flags. Sin archivo
+// de perfiles se usa el API local.
+func resolveOptions(flags connectionFlags, getenv func(string) string) (apiclient.Options, error) {
+	var profile Profile
+	name := flags.profile
+	if name == "" {
+		name = getenv("WFCTL_PROFILE")
+	}
+
+	// Sin directorio de configuración se comporta como si no hubiera archivo
+	path := configPath(getenv)
+	var config ProfilesConfig
+	err := os.ErrNotExist
+	if path != "" {
+		config, err = loadProfiles(path)
+	}
+	switch {
+	case err == nil:
+		if name == "" {
+			name = config.Current
+		}
+		if name != "" {
+			var ok bool
+			if profile, ok = config.Profiles[name]; !ok {
+				return apiclient.Options{}, fmt.Errorf("unknown profile %q (declared: %s)", name, strings.Join(config.names(), ", "))
+			}
+		}
+	case errors.Is(err, os.ErrNotExist):
+		if name != "" {
+			return apiclient.Options{}, fmt.Errorf("profile %q requested but %s does not exist", name, path)
+		}
+	default:
+		return apiclient.Options{}, err
+	}
+
+	options := apiclient.Options{
+		BaseURL:   profile.URL,
+		APIKey:    profile.APIKey,
+		Namespace: profile.Namespace,
+		Cluster:   profile.Cluster,
+	}
+	if profile.APIKeyEnv != "" {
+		if options.APIKey = getenv(profile.APIKeyEnv); options.APIKey == "" {
+			return apiclient.Options{}, fmt.Errorf("profile %s: environment variable %s is empty", name, profile.APIKeyEnv)
+		}
+	}
+	if options.BaseURL == "" {
+		options.BaseURL = defaultURL
+	}
+
+	if flags.url != "" {
+		options.BaseURL = flags.url
+	}
+	if flags.apiKey != "" {
+		options.APIKey = flags.apiKey
+	}
+	if flags.namespace != "" {
+		options.Namespace = flags.namespace
+	}
+	if flags.cluster != "" {
+		options.Cluster = flags.cluster
+	}
+	return options, nil
+}
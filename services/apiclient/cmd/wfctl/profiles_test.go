@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testProfiles = `
+current: local
+profiles:
+  local:
+    url: http://localhost:8080
+  staging:
+    url: https://workflows.staging.internal
+    apiKeyEnv: STAGING_KEY
+    namespace: staging
+    cluster: infra-2
+`
+
+// testEnv devuelve un getenv con el archivo de perfiles y las variables dadas
+func testEnv(t *testing.T, config string, vars map[string]string) func(string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+	return func(key string) string {
+		if key == "WFCTL_CONFIG" {
+			return path
+		}
+		return vars[key]
+	}
+}
+
+func TestResolveOptionsUsesCurrentProfile(t *testing.T) {
+	options, err := resolveOptions(connectionFlags{}, testEnv(t, testProfiles, nil))
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", options.BaseURL)
+	assert.Empty(t, options.APIKey)
+}
+
+func TestResolveOptionsSelectsProfileAndReadsKeyFromEnv(t *testing.T) {
+	getenv := testEnv(t, testProfiles, map[string]string{"WFCTL_PROFILE": "staging", "STAGING_KEY": "secret"})
+
+	options, err := resolveOptions(connectionFlags{}, getenv)
+	require.NoError(t, err)
+	assert.Equal(t, "https://workflows.staging.internal", options.BaseURL)
+	assert.Equal(t, "secret", options.APIKey)
+	assert.Equal(t, "staging", options.Namespace)
+	assert.Equal(t, "infra-2", options.Cluster)
+
+	// Los flags reemplazan los valores del perfil, y --profile a WFCTL_PROFILE
+	options, err = resolveOptions(connectionFlags{profile: "local", namespace: "orders"}, getenv)
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", options.BaseURL)
+	assert.Equal(t, "orders", options.Namespace)
+}
+
+func TestResolveOptionsErrors(t *testing.T) {
+	_, err := resolveOptions(connectionFlags{profile: "prod"}, testEnv(t, testProfiles, nil))
+	assert.ErrorContains(t, err, `unknown profile "prod"`)
+
+	_, err = resolveOptions(connectionFlags{profile: "staging"}, testEnv(t, testProfiles, nil))
+	assert.ErrorContains(t, err, "STAGING_KEY is empty")
+
+	_, err = resolveOptions(connectionFlags{}, testEnv(t, "profiles:\n  local:\n    uri: http://x\n", nil))
+	assert.ErrorContains(t, err, "unknown field")
+}
+
+func TestResolveOptionsWithoutConfigFile(t *testing.T) {
+	getenv := func(key string) string {
+		if key == "WFCTL_CONFIG" {
+			return filepath.Join(t.TempDir(), "missing.yaml")
+		}
+		return ""
+	}
+
+	options, err := resolveOptions(connectionFlags{}, getenv)
+	require.NoError(t, err)
+	assert.Equal(t, defaultURL, options.BaseURL)
+
+	_, err = resolveOptions(connectionFlags{profile: "staging"}, getenv)
+	assert.ErrorContains(t, err, "does not exist")
+}
@@ -0,0 +1,30 @@
+module github.com/temporal-aws-poc/apiclient
+
+go 1.21
+
+require (
+	github.com/stretchr/testify v1.8.4
+	github.com/temporal-aws-poc/contracts v0.0.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/grpc-ecosystem/grpc-gateway v1.16.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.temporal.io/api v1.26.0 // indirect
+	go.temporal.io/sdk v1.25.1 // indirect
+	golang.org/x/net v0.18.0 // indirect
+	golang.org/x/sys v0.14.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto v0.0.0-20231106174013-bbf56f31fb17 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20231106174013-bbf56f31fb17 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20231106174013-bbf56f31fb17 // indirect
+	google.golang.org/grpc v1.59.0 // indirect
+	google.golang.org/protobuf v1.31.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// contracts se comparte con el API y el worker desde el mismo repositorio
+replace github.com/temporal-aws-poc/contracts => ../contracts
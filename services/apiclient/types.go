@@ -0,0 +1,179 @@
+package apiclient
+
+import (
+	"time"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// Estados de una ejecución, tal como los reporta el API
+const (
+	StatusRunning        = "Running"
+	StatusCompleted      = "Completed"
+	StatusFailed         = "Failed"
+	StatusCanceled       = "Canceled"
+	StatusTerminated     = "Terminated"
+	StatusContinuedAsNew = "ContinuedAsNew"
+	StatusTimedOut       = "TimedOut"
+)
+
+// StartRequest es el payload de POST /workflows/start. Sin Pipeline ni Batch
+// se inicia WorkflowType (WorkflowA si está vacío) con Input.
+type StartRequest struct {
+	WorkflowID string `json:"workflowId"`
+	// WorkflowType es uno de WorkflowA a WorkflowD
+	WorkflowType string                 `json:"workflowType,omitempty"`
+	Input        map[string]interface{} `json:"input,omitempty"`
+	// ChildWorkflowIDTemplate es la plantilla de IDs de los child workflows
+	// (campos: WorkflowID, RunID, WorkflowType, Step)
+	ChildWorkflowIDTemplate string                    `json:"childWorkflowIdTemplate,omitempty"`
+	Approval                *contracts.ApprovalPolicy `json:"approval,omitempty"`
+	// Pipeline inicia DSLWorkflow con la definición de ese nombre
+	Pipeline string `json:"pipeline,omitempty"`
+	// ActivityProfile es el perfil de ActivityOptions de la ejecución
+	ActivityProfile string `json:"activityProfile,omitempty"`
+	// Batch inicia BatchWorkflow con sus items
+	Batch *BatchRequest `json:"batch,omitempty"`
+	// SLA es el plazo de la ejecución ("30m")
+	SLA string `json:"sla,omitempty"`
+	// SearchAttributes son las claves de negocio ("customerId", "orderId", "tenant")
+	SearchAttributes map[string]string `json:"searchAttributes,omitempty"`
+}
+
+// BatchRequest son los items de un BatchWorkflow: inline o una referencia
+// a una lista del worker, no ambos
+type BatchRequest struct {
+	Items          []interface{} `json:"items,omitempty"`
+	ItemsRef       string        `json:"itemsRef,omitempty"`
+	Activity       string        `json:"activity,omitempty"`
+	MaxParallelism int           `json:"maxParallelism,omitempty"`
+	ItemsPerRun    int           `json:"itemsPerRun,omitempty"`
+}
+
+// StartResponse identifica la ejecución iniciada
+type StartResponse struct {
+	WorkflowID string `json:"workflowId"`
+	RunID      string `json:"runId"`
+	// Cluster es el cluster de Temporal donde se inició
+	Cluster string `json:"cluster,omitempty"`
+	Message string `json:"message"`
+}
+
+// WorkflowStatus es el estado de una ejecución
+type WorkflowStatus struct {
+	WorkflowID string `json:"workflowId"`
+	RunID      string `json:"runId"`
+	Status     string `json:"status"`
+	Result     string `json:"result,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// Tipo y detalle de la causa raíz cuando el workflow falló
+	ErrorType        string                 `json:"errorType,omitempty"`
+	ErrorDetails     interface{}            `json:"errorDetails,omitempty"`
+	Parent           *WorkflowRef           `json:"parent,omitempty"`
+	Children         []WorkflowRef          `json:"children,omitempty"`
+	SearchAttributes map[string]interface{} `json:"searchAttributes,omitempty"`
+}
+
+// Done indica si la ejecución terminó. El API reporta "running" en
+// minúsculas cuando no pudo describir la ejecución.
+func (s *WorkflowStatus) Done() bool {
+	switch s.Status {
+	case StatusRunning, "running", "":
+		return false
+	}
+	return true
+}
+
+// Completed indica si la ejecución terminó con éxito
+func (s *WorkflowStatus) Completed() bool {
+	return s.Status == StatusCompleted || s.Status == "completed"
+}
+
+// WorkflowRef es un workflow relacionado (padre o hijo)
+type WorkflowRef struct {
+	WorkflowID   string `json:"workflowId"`
+	RunID        string `json:"runId"`
+	WorkflowType string `json:"workflowType,omitempty"`
+	Status       string `json:"status,omitempty"`
+}
+
+// ListOptions son los filtros de GET /workflows; los vacíos no filtran
+type ListOptions struct {
+	WorkflowType string
+	Status       string
+	// SearchAttributes filtra por los search attributes declarados
+	// ("customerId", "currentStep", ...)
+	SearchAttributes map[string]string
+	PageSize         int
+	NextPageToken    string
+}
+
+// ListResponse es una página del listado; NextPageToken vacío indica que no
+// hay más resultados
+type ListResponse struct {
+	Workflows     []WorkflowSummary `json:"workflows"`
+	NextPageToken string            `json:"nextPageToken,omitempty"`
+}
+
+// WorkflowSummary es una ejecución del listado
+type WorkflowSummary struct {
+	WorkflowID       string                 `json:"workflowId"`
+	RunID            string                 `json:"runId"`
+	WorkflowType     string                 `json:"workflowType"`
+	Status           string                 `json:"status"`
+	StartTime        time.Time              `json:"startTime"`
+	CloseTime        *time.Time             `json:"closeTime,omitempty"`
+	SearchAttributes map[string]interface{} `json:"searchAttributes,omitempty"`
+}
+
+// HistoryEvent es un evento del historial, resumido por el API
+type HistoryEvent struct {
+	EventID   int64     `json:"eventId"`
+	EventTime time.Time `json:"eventTime"`
+	// EventType es el nombre del evento ("ActivityTaskScheduled")
+	EventType string `json:"eventType"`
+	// Details es la activity, child workflow o señal del evento, o su error
+	Details string `json:"details,omitempty"`
+}
+
+// History son los eventos de una ejecución; Closed indica que el último
+// evento cierra la ejecución. En HistoryPage, NextPageToken vacío indica que
+// no hay más eventos.
+type History struct {
+	WorkflowID    string         `json:"workflowId"`
+	RunID         string         `json:"runId,omitempty"`
+	Events        []HistoryEvent `json:"events"`
+	Closed        bool           `json:"closed"`
+	NextPageToken string         `json:"nextPageToken,omitempty"`
+}
+
+// HistoryOptions elige la página de GET /workflows/history
+type HistoryOptions struct {
+	// RunID vacío es el run actual
+	RunID         string
+	NextPageToken string
+	// Wait devuelve token mientras la ejecución sigue en curso; el request
+	// con ese token espera eventos nuevos en lugar de releer el historial
+	Wait bool
+}
+
+// SignalRequest es una señal para un workflow; RunID vacío apunta al run actual
+type SignalRequest struct {
+	WorkflowID string      `json:"workflowId"`
+	RunID      string      `json:"runId,omitempty"`
+	SignalName string      `json:"signalName"`
+	Input      interface{} `json:"input,omitempty"`
+}
+
+// QueryRequest es una query sobre un workflow; RunID vacío apunta al run actual
+type QueryRequest struct {
+	WorkflowID string        `json:"workflowId"`
+	RunID      string        `json:"runId,omitempty"`
+	QueryType  string        `json:"queryType"`
+	Args       []interface{} `json:"args,omitempty"`
+}
+
+type cancelRequest struct {
+	WorkflowID string `json:"workflowId"`
+	RunID      string `json:"runId,omitempty"`
+}
@@ -0,0 +1,43 @@
+package contracts
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// DefaultChildWorkflowIDTemplate deriva el ID del hijo del workflow padre,
+// su run y el paso que lo lanza, así dos runs del padre nunca colisionan
+const DefaultChildWorkflowIDTemplate = "{{.WorkflowID}}-{{.Step}}-{{.RunID}}"
+
+// ChildWorkflowIDData son los campos disponibles dentro de la plantilla de
+// ChildWorkflowIDTemplateMemoKey
+type ChildWorkflowIDData struct {
+	WorkflowID   string
+	RunID        string
+	WorkflowType string
+	Step         string
+}
+
+// RenderChildWorkflowID aplica la plantilla a los datos del padre. Si la
+// plantilla está vacía se usa DefaultChildWorkflowIDTemplate. El API la
+// prueba con datos de ejemplo al iniciar y el worker la aplica en cada hijo.
+func RenderChildWorkflowID(tmpl string, data ChildWorkflowIDData) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultChildWorkflowIDTemplate
+	}
+
+	t, err := template.New("childWorkflowId").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid child workflow ID template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render child workflow ID: %w", err)
+	}
+	if buf.Len() == 0 {
+		return "", fmt.Errorf("child workflow ID template rendered an empty ID")
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,91 @@
+// Package contracts reúne lo que el API y el worker deben acordar: nombres de
+// workflows, activities, task queues, señales, queries y claves del memo, los
+// inputs y resultados tipados, los search attributes y el data converter.
+// Ambos servicios lo importan, así un renombre en uno no compila en el otro.
+package contracts
+
+import "slices"
+
+// DefaultTaskQueue es la task queue de los workflows que inicia el API
+const DefaultTaskQueue = "hello-world-queue"
+
+// Nombres con los que se registran e inician los workflows
+const (
+	WorkflowA     = "WorkflowA"
+	WorkflowB     = "WorkflowB"
+	WorkflowC     = "WorkflowC"
+	WorkflowD     = "WorkflowD"
+	DSLWorkflow   = "DSLWorkflow"
+	BatchWorkflow = "BatchWorkflow"
+)
+
+// Nombres con los que se registran y ejecutan las activities
+const (
+	Activity1           = "Activity1"
+	Activity2           = "Activity2"
+	Activity3           = "Activity3"
+	Activity4           = "Activity4"
+	CompensateActivity1 = "CompensateActivity1"
+	CompensateActivity2 = "CompensateActivity2"
+	CompensateActivity4 = "CompensateActivity4"
+	Escalate            = "Escalate"
+	ProcessRecords      = "ProcessRecords"
+	LoadPipeline        = "LoadPipeline"
+	LoadBatchItems      = "LoadBatchItems"
+	StoreBatchItems     = "StoreBatchItems"
+)
+
+// BatchItemActivities son las activities que BatchWorkflow acepta como
+// activity por item; el resto (compensaciones, escalamiento, carga de
+// pipelines) no se puede invocar desde un batch
+var BatchItemActivities = []string{Activity1, Activity2, Activity3, Activity4, ProcessRecords}
+
+// IsBatchItemActivity indica si name está en BatchItemActivities
+func IsBatchItemActivity(name string) bool {
+	for _, activity := range BatchItemActivities {
+		if activity == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PipelineActivities son las activities que un pipeline de DSLWorkflow puede
+// ejecutar como paso; como en los batches, las compensaciones, el
+// escalamiento y las activities internas quedan fuera
+var PipelineActivities = []string{Activity1, Activity2, Activity3, Activity4}
+
+// IsPipelineActivity indica si name está en PipelineActivities
+func IsPipelineActivity(name string) bool {
+	return slices.Contains(PipelineActivities, name)
+}
+
+// Señales y queries de los workflows
+const (
+	// ApprovalSignalName es la señal con la que un humano aprueba o rechaza
+	// (payload ApprovalDecision)
+	ApprovalSignalName = "approval"
+	// ApprovalQueryName devuelve el ApprovalState actual de WorkflowA
+	ApprovalQueryName = "approval-status"
+	// BatchProgressQueryName devuelve el BatchProgress de BatchWorkflow
+	BatchProgressQueryName = "batch-progress"
+)
+
+// Claves del memo que el caller completa al iniciar y el worker lee
+const (
+	// ApprovalPolicyMemoKey lleva una ApprovalPolicy
+	ApprovalPolicyMemoKey = "approvalPolicy"
+	// ActivityProfileMemoKey lleva el nombre del perfil de ActivityOptions
+	ActivityProfileMemoKey = "activityProfile"
+	// ChildWorkflowIDTemplateMemoKey lleva la plantilla de IDs de los hijos
+	ChildWorkflowIDTemplateMemoKey = "childWorkflowIdTemplate"
+	// SLAMemoKey lleva una SLAPolicy
+	SLAMemoKey = "sla"
+)
+
+// Claves del memo que actualiza el worker
+const (
+	// ApprovalStateMemoKey lleva el ApprovalState de WorkflowA desde que pide
+	// aprobación; el API lo lee al listar sin consultar cada ejecución
+	ApprovalStateMemoKey = "approvalState"
+)
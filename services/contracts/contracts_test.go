@@ -0,0 +1,70 @@
+package contracts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Los nombres JSON son parte del contrato: las ejecuciones en curso y los
+// historiales ya guardados tienen los payloads con estos campos
+func TestWireFormat(t *testing.T) {
+	cases := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"PipelineInput", PipelineInput{Pipeline: "p", Data: "d"}, `{"pipeline":"p","data":"d"}`},
+		{"ApprovalPolicy omits empty fields", ApprovalPolicy{Threshold: 5}, `{"threshold":5}`},
+		{"ApprovalDecision", ApprovalDecision{Approved: true, Approver: "ana"}, `{"approved":true,"approver":"ana"}`},
+		{"SLAPolicy", SLAPolicy{Duration: "30m"}, `{"duration":"30m"}`},
+		{"BatchInput", BatchInput{BatchID: "b", ItemsRef: "items.json"},
+			`{"batchId":"b","itemsRef":"items.json","summary":{"succeeded":0,"failed":0,"runs":0}}`},
+		{"BatchProgress", BatchProgress{BatchID: "b", Total: 3, Processed: 1},
+			`{"batchId":"b","total":3,"processed":1,"succeeded":0,"failed":0,"inFlight":0,"run":0}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.value)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.want, string(data))
+		})
+	}
+}
+
+func TestDataConverterRoundTrip(t *testing.T) {
+	in := BatchInput{BatchID: "b", Items: []string{"x", "y"}, MaxParallelism: 2, Summary: BatchSummary{Failed: 1, Failures: []BatchItemFailure{{Index: 1, Error: "boom"}}}}
+	payload, err := DataConverter().ToPayload(in)
+	require.NoError(t, err)
+
+	var out BatchInput
+	require.NoError(t, DataConverter().FromPayload(payload, &out))
+	assert.Equal(t, in, out)
+}
+
+func TestSearchAttributeTypesCoverDeclaredKeys(t *testing.T) {
+	for _, name := range []string{CustomerIDSearchAttribute, OrderIDSearchAttribute, TenantSearchAttribute, CurrentStepSearchAttribute} {
+		assert.Contains(t, SearchAttributeTypes, name)
+	}
+}
+
+func TestDecodeYAMLStrict(t *testing.T) {
+	type config struct {
+		Name    string `json:"name"`
+		Timeout string `json:"timeout,omitempty"`
+	}
+
+	var fromYAML config
+	require.NoError(t, DecodeYAMLStrict([]byte("name: a\ntimeout: 5s\n"), &fromYAML))
+	assert.Equal(t, config{Name: "a", Timeout: "5s"}, fromYAML)
+
+	var fromJSON config
+	require.NoError(t, DecodeYAMLStrict([]byte(`{"name": "b"}`), &fromJSON))
+	assert.Equal(t, config{Name: "b"}, fromJSON)
+
+	var unknown config
+	assert.ErrorContains(t, DecodeYAMLStrict([]byte("name: a\ntimout: 5s\n"), &unknown), "timout")
+	assert.Error(t, DecodeYAMLStrict([]byte("name: [a"), &unknown))
+}
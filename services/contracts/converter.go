@@ -0,0 +1,10 @@
+package contracts
+
+import "go.temporal.io/sdk/converter"
+
+// DataConverter es el converter de los payloads (inputs, resultados, memo,
+// señales y queries). El API y el worker lo configuran en su cliente; si
+// cambia, cambia en los dos a la vez.
+func DataConverter() converter.DataConverter {
+	return converter.GetDefaultDataConverter()
+}
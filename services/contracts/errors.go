@@ -0,0 +1,18 @@
+package contracts
+
+// Tipos de error de las activities. Viajan como ApplicationError, así el
+// workflow, las RetryPolicy y la API pueden distinguirlos por su tipo.
+const (
+	// ValidationErrorType: el input es inválido; reintentar no lo arregla
+	ValidationErrorType = "ValidationError"
+	// PermanentErrorType: fallo que no se resolverá con reintentos
+	PermanentErrorType = "PermanentError"
+	// TransientErrorType: fallo temporal (timeout, dependencia caída)
+	TransientErrorType = "TransientError"
+	// RateLimitedErrorType: una dependencia pidió bajar el ritmo
+	RateLimitedErrorType = "RateLimitedError"
+)
+
+// NonRetryableErrorTypes son los tipos que las RetryPolicy no deben
+// reintentar; los usan todos los perfiles de ActivityOptions del worker
+var NonRetryableErrorTypes = []string{ValidationErrorType, PermanentErrorType}
@@ -0,0 +1,27 @@
+package contracts
+
+import enumspb "go.temporal.io/api/enums/v1"
+
+// Search attributes custom de las ejecuciones
+const (
+	// CustomerIDSearchAttribute, OrderIDSearchAttribute y
+	// TenantSearchAttribute los envía el caller al iniciar
+	CustomerIDSearchAttribute = "CustomerId"
+	OrderIDSearchAttribute    = "OrderId"
+	TenantSearchAttribute     = "Tenant"
+	// CurrentStepSearchAttribute lo actualiza el worker con la activity o
+	// child workflow en curso ("CurrentStep = 'Activity3'")
+	CurrentStepSearchAttribute = "CurrentStep"
+	// ApprovalStatusSearchAttribute lo actualiza WorkflowA con el estado de
+	// la aprobación manual ("ApprovalStatus = 'PENDING'")
+	ApprovalStatusSearchAttribute = "ApprovalStatus"
+)
+
+// SearchAttributeTypes es el tipo con el que se registra cada search attribute
+var SearchAttributeTypes = map[string]enumspb.IndexedValueType{
+	CustomerIDSearchAttribute:     enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+	OrderIDSearchAttribute:        enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+	TenantSearchAttribute:         enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+	CurrentStepSearchAttribute:    enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+	ApprovalStatusSearchAttribute: enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+}
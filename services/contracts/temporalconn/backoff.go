@@ -0,0 +1,59 @@
+package temporalconn
+
+import (
+	"fmt"
+	"time"
+)
+
+// Backoff define los reintentos mientras el frontend no responde
+type Backoff struct {
+	// Initial es la espera tras el primer intento fallido; se duplica en cada
+	// intento hasta Max
+	Initial time.Duration
+	Max     time.Duration
+	// MaxWait es el tiempo total de reintentos; cero reintenta sin límite
+	MaxWait time.Duration
+}
+
+// DefaultBackoff reintenta durante 5 minutos, con esperas de 1s a 30s
+var DefaultBackoff = Backoff{Initial: time.Second, Max: 30 * time.Second, MaxWait: 5 * time.Minute}
+
+// BackoffFromEnv ajusta base con TEMPORAL_DIAL_INITIAL_BACKOFF,
+// TEMPORAL_DIAL_MAX_BACKOFF y TEMPORAL_DIAL_MAX_WAIT
+func BackoffFromEnv(base Backoff, getenv func(string) string) (Backoff, error) {
+	fields := []struct {
+		name  string
+		value *time.Duration
+	}{
+		{"TEMPORAL_DIAL_INITIAL_BACKOFF", &base.Initial},
+		{"TEMPORAL_DIAL_MAX_BACKOFF", &base.Max},
+		{"TEMPORAL_DIAL_MAX_WAIT", &base.MaxWait},
+	}
+	for _, field := range fields {
+		v := getenv(field.name)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return Backoff{}, fmt.Errorf("%s: invalid duration %q", field.name, v)
+		}
+		*field.value = d
+	}
+	if base.Initial <= 0 {
+		return Backoff{}, fmt.Errorf("TEMPORAL_DIAL_INITIAL_BACKOFF must be positive")
+	}
+	return base, nil
+}
+
+// Delay es la espera tras el intento fallido número attempt (desde 1)
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Initial
+	for i := 1; i < attempt && (b.Max == 0 || delay < b.Max); i++ {
+		delay *= 2
+	}
+	if b.Max > 0 && delay > b.Max {
+		delay = b.Max
+	}
+	return delay
+}
@@ -0,0 +1,33 @@
+package temporalconn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffDelayDoublesUpToMax(t *testing.T) {
+	backoff := Backoff{Initial: time.Second, Max: 5 * time.Second}
+	delays := []time.Duration{}
+	for attempt := 1; attempt <= 5; attempt++ {
+		delays = append(delays, backoff.Delay(attempt))
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, delays)
+}
+
+func TestBackoffFromEnv(t *testing.T) {
+	env := map[string]string{"TEMPORAL_DIAL_MAX_WAIT": "0s", "TEMPORAL_DIAL_MAX_BACKOFF": "10s"}
+	backoff, err := BackoffFromEnv(DefaultBackoff, func(name string) string { return env[name] })
+	require.NoError(t, err)
+	assert.Equal(t, Backoff{Initial: time.Second, Max: 10 * time.Second}, backoff)
+
+	_, err = BackoffFromEnv(DefaultBackoff, func(name string) string {
+		if name == "TEMPORAL_DIAL_MAX_WAIT" {
+			return "forever"
+		}
+		return ""
+	})
+	assert.Error(t, err)
+}
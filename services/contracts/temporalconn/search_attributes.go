@@ -0,0 +1,60 @@
+package temporalconn
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/operatorservice/v1"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// RegisterSearchAttributes registra en el namespace los search attributes
+// names que falten, con su tipo de contracts.SearchAttributeTypes, y
+// devuelve los registrados. Falla si alguno existe con otro tipo; sin ellos
+// el servidor rechaza los inicios y upserts que los usan.
+func RegisterSearchAttributes(ctx context.Context, operator operatorservice.OperatorServiceClient, namespace string, names []string) ([]string, error) {
+	existing, err := operator.ListSearchAttributes(ctx, &operatorservice.ListSearchAttributesRequest{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list search attributes: %w", err)
+	}
+
+	missing := map[string]enumspb.IndexedValueType{}
+	var added, conflicts []string
+	for _, name := range names {
+		expected, ok := contracts.SearchAttributeTypes[name]
+		if !ok {
+			return nil, fmt.Errorf("search attribute %s is not declared in contracts", name)
+		}
+		current, ok := existing.GetCustomAttributes()[name]
+		if !ok {
+			missing[name] = expected
+			added = append(added, name)
+			continue
+		}
+		if current != expected {
+			conflicts = append(conflicts, fmt.Sprintf("%s is %s, expected %s", name, current, expected))
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("search attribute type mismatch: %s", strings.Join(conflicts, "; "))
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	_, err = operator.AddSearchAttributes(ctx, &operatorservice.AddSearchAttributesRequest{
+		Namespace:        namespace,
+		SearchAttributes: missing,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register search attributes: %w", err)
+	}
+	sort.Strings(added)
+	return added, nil
+}
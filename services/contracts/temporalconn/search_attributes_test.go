@@ -0,0 +1,69 @@
+package temporalconn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/operatorservice/v1"
+	"google.golang.org/grpc"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// fakeOperator guarda los search attributes del namespace en memoria
+type fakeOperator struct {
+	operatorservice.OperatorServiceClient
+	attributes map[string]enumspb.IndexedValueType
+	added      []*operatorservice.AddSearchAttributesRequest
+}
+
+func (f *fakeOperator) ListSearchAttributes(ctx context.Context, in *operatorservice.ListSearchAttributesRequest, opts ...grpc.CallOption) (*operatorservice.ListSearchAttributesResponse, error) {
+	return &operatorservice.ListSearchAttributesResponse{CustomAttributes: f.attributes}, nil
+}
+
+func (f *fakeOperator) AddSearchAttributes(ctx context.Context, in *operatorservice.AddSearchAttributesRequest, opts ...grpc.CallOption) (*operatorservice.AddSearchAttributesResponse, error) {
+	f.added = append(f.added, in)
+	for name, valueType := range in.SearchAttributes {
+		f.attributes[name] = valueType
+	}
+	return &operatorservice.AddSearchAttributesResponse{}, nil
+}
+
+func TestRegisterSearchAttributesAddsMissing(t *testing.T) {
+	operator := &fakeOperator{attributes: map[string]enumspb.IndexedValueType{
+		contracts.CurrentStepSearchAttribute: enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+	}}
+	names := []string{contracts.CurrentStepSearchAttribute, contracts.ApprovalStatusSearchAttribute, contracts.CustomerIDSearchAttribute}
+
+	added, err := RegisterSearchAttributes(context.Background(), operator, "staging", names)
+	require.NoError(t, err)
+	assert.Equal(t, []string{contracts.ApprovalStatusSearchAttribute, contracts.CustomerIDSearchAttribute}, added)
+	require.Len(t, operator.added, 1)
+	assert.Equal(t, "staging", operator.added[0].Namespace)
+
+	// Con todos registrados no vuelve a llamar al servidor
+	added, err = RegisterSearchAttributes(context.Background(), operator, "staging", names)
+	require.NoError(t, err)
+	assert.Empty(t, added)
+	assert.Len(t, operator.added, 1)
+}
+
+func TestRegisterSearchAttributesRejectsConflicts(t *testing.T) {
+	operator := &fakeOperator{attributes: map[string]enumspb.IndexedValueType{
+		contracts.CustomerIDSearchAttribute: enumspb.INDEXED_VALUE_TYPE_TEXT,
+		contracts.TenantSearchAttribute:     enumspb.INDEXED_VALUE_TYPE_INT,
+	}}
+
+	_, err := RegisterSearchAttributes(context.Background(), operator, "default",
+		[]string{contracts.CustomerIDSearchAttribute, contracts.TenantSearchAttribute, contracts.OrderIDSearchAttribute})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CustomerId is Text, expected Keyword")
+	assert.Contains(t, err.Error(), "Tenant is Int, expected Keyword")
+	assert.Empty(t, operator.added)
+
+	_, err = RegisterSearchAttributes(context.Background(), operator, "default", []string{"Unknown"})
+	assert.ErrorContains(t, err, "not declared")
+}
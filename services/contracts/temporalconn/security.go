@@ -0,0 +1,237 @@
+// Package temporalconn reúne lo que el API y el worker comparten para
+// conectarse al frontend de Temporal: TLS, mTLS con recarga del certificado,
+// API key y el backoff de los reintentos. Ambos leen las mismas variables
+// TEMPORAL_TLS_*, TEMPORAL_API_KEY* y TEMPORAL_DIAL_*.
+package temporalconn
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.temporal.io/sdk/client"
+)
+
+// Security es la configuración de TLS y credenciales hacia el frontend:
+// TLS con la CA del servidor (o las del sistema), mTLS con certificado de
+// cliente y API key (Temporal Cloud o un proxy de autenticación).
+type Security struct {
+	// TLS activa TLS aunque no haya CA ni certificado (CAs del sistema)
+	TLS bool
+	// CAFile es la CA que firma el certificado del servidor
+	CAFile string
+	// CertFile y KeyFile son el certificado de cliente para mTLS; se
+	// recargan cuando cambian en disco y se usan desde el siguiente handshake
+	CertFile string
+	KeyFile  string
+	// ServerName reemplaza el nombre esperado en el certificado del servidor
+	// (p.ej. al conectar por IP o por un alias de service discovery)
+	ServerName string
+	// APIKey se envía como "authorization: Bearer <key>" en cada llamada
+	APIKey string
+}
+
+// SecurityFromEnv lee TEMPORAL_TLS_ENABLED, TEMPORAL_TLS_CA_FILE,
+// TEMPORAL_TLS_CERT_FILE, TEMPORAL_TLS_KEY_FILE, TEMPORAL_TLS_SERVER_NAME,
+// TEMPORAL_API_KEY y TEMPORAL_API_KEY_FILE
+func SecurityFromEnv(getenv func(string) string) (Security, error) {
+	security := Security{
+		CAFile:     getenv("TEMPORAL_TLS_CA_FILE"),
+		CertFile:   getenv("TEMPORAL_TLS_CERT_FILE"),
+		KeyFile:    getenv("TEMPORAL_TLS_KEY_FILE"),
+		ServerName: getenv("TEMPORAL_TLS_SERVER_NAME"),
+		APIKey:     getenv("TEMPORAL_API_KEY"),
+	}
+	if v := getenv("TEMPORAL_TLS_ENABLED"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return Security{}, fmt.Errorf("TEMPORAL_TLS_ENABLED: %w", err)
+		}
+		security.TLS = enabled
+	}
+	if path := getenv("TEMPORAL_API_KEY_FILE"); path != "" {
+		if security.APIKey != "" {
+			return Security{}, fmt.Errorf("TEMPORAL_API_KEY and TEMPORAL_API_KEY_FILE are mutually exclusive")
+		}
+		key, err := ReadAPIKeyFile(path)
+		if err != nil {
+			return Security{}, fmt.Errorf("TEMPORAL_API_KEY_FILE: %w", err)
+		}
+		security.APIKey = key
+	}
+	return security, security.Validate()
+}
+
+// ReadAPIKeyFile lee una API key de un archivo, sin el salto de línea final
+func ReadAPIKeyFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Validate revisa que la configuración sea coherente
+func (s Security) Validate() error {
+	if (s.CertFile == "") != (s.KeyFile == "") {
+		return fmt.Errorf("client certificate and key must be set together")
+	}
+	return nil
+}
+
+// Enabled indica si la conexión usa TLS. Cualquier ajuste de TLS o una API
+// key lo activan: la API key nunca viaja en claro.
+func (s Security) Enabled() bool {
+	return s.TLS || s.CAFile != "" || s.CertFile != "" || s.ServerName != "" || s.APIKey != ""
+}
+
+// Apply completa las ConnectionOptions y el HeadersProvider de options
+func (s Security) Apply(options *client.Options) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if !s.Enabled() {
+		return nil
+	}
+
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: s.ServerName,
+	}
+	if s.CAFile != "" {
+		pem, err := os.ReadFile(s.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read server CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", s.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	if s.CertFile != "" {
+		reloader, err := NewCertReloader(s.CertFile, s.KeyFile)
+		if err != nil {
+			return err
+		}
+		config.GetClientCertificate = reloader.GetClientCertificate
+	}
+	options.ConnectionOptions.TLS = config
+
+	if s.APIKey != "" {
+		options.HeadersProvider = apiKeyHeaders(s.APIKey)
+	}
+	return nil
+}
+
+// String describe la configuración sin exponer la API key
+func (s Security) String() string {
+	if !s.Enabled() {
+		return "plaintext"
+	}
+	description := "TLS"
+	if s.CertFile != "" {
+		description = "mTLS"
+	}
+	if s.CAFile != "" {
+		description += ", CA " + s.CAFile
+	}
+	if s.ServerName != "" {
+		description += ", server name " + s.ServerName
+	}
+	if s.APIKey != "" {
+		description += ", API key"
+	}
+	return description
+}
+
+type apiKeyHeaders string
+
+func (k apiKeyHeaders) GetHeaders(context.Context) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(k)}, nil
+}
+
+// certReloadInterval limita cada cuánto se revisan los archivos en disco
+const certReloadInterval = 10 * time.Second
+
+// CertReloader entrega el certificado de cliente en cada handshake y lo
+// vuelve a leer cuando cambia la fecha de modificación de los archivos, así
+// la rotación de certificados no requiere reiniciar
+type CertReloader struct {
+	certFile, keyFile string
+	interval          time.Duration
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// NewCertReloader carga el certificado y su clave; falla si no son válidos
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile, interval: certReloadInterval}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetClientCertificate es el callback de tls.Config
+func (r *CertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) >= r.interval {
+		r.checkedAt = time.Now()
+		if modTime, err := r.latestModTime(); err == nil && modTime.After(r.modTime) {
+			// Si el par nuevo no es válido (p.ej. escritura a medias) se
+			// sigue usando el anterior y se reintenta en la próxima revisión
+			if err := r.load(modTime); err != nil {
+				log.Printf("Warning: keeping the current client certificate: %v", err)
+			} else {
+				log.Printf("Client certificate reloaded from %s", r.certFile)
+			}
+		}
+	}
+	return r.cert, nil
+}
+
+func (r *CertReloader) reload() error {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return err
+	}
+	r.checkedAt = time.Now()
+	return r.load(modTime)
+}
+
+func (r *CertReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	return nil
+}
+
+func (r *CertReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, path := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to read client certificate: %w", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
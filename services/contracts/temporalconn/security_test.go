@@ -0,0 +1,267 @@
+package temporalconn
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// testPKI es una CA generada para el test con la que se firman los
+// certificados del servidor y de los clientes
+type testPKI struct {
+	t    *testing.T
+	dir  string
+	ca   *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pki := &testPKI{t: t, dir: t.TempDir(), ca: ca, key: key, pool: x509.NewCertPool()}
+	pki.pool.AddCert(ca)
+	writePEM(t, filepath.Join(pki.dir, "ca.pem"), "CERTIFICATE", der)
+	return pki
+}
+
+// issue firma un certificado para name y lo escribe en <file>.pem y <file>-key.pem
+func (p *testPKI) issue(file, name string, usage x509.ExtKeyUsage) (certFile, keyFile string) {
+	p.t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(p.t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(p.t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, p.ca, &key.PublicKey, p.key)
+	require.NoError(p.t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(p.t, err)
+
+	certFile = filepath.Join(p.dir, file+".pem")
+	keyFile = filepath.Join(p.dir, file+"-key.pem")
+	writePEM(p.t, certFile, "CERTIFICATE", der)
+	writePEM(p.t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+}
+
+// startTLSFrontend levanta un frontend de prueba con TLS que exige
+// certificado de cliente firmado por la CA. Si apiKey no es vacío también
+// exige el header authorization. Devuelve la dirección y el nombre común del
+// último certificado de cliente recibido.
+func startTLSFrontend(t *testing.T, pki *testPKI, apiKey string) (string, func() string) {
+	t.Helper()
+	certFile, keyFile := pki.issue("server", "temporal.test", x509.ExtKeyUsageServerAuth)
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+
+	peers := make(chan string, 16)
+	creds := credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pki.pool,
+		VerifyPeerCertificate: func(_ [][]byte, chains [][]*x509.Certificate) error {
+			select {
+			case peers <- chains[0][0].Subject.CommonName:
+			default:
+			}
+			return nil
+		},
+	})
+	authorize := func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if apiKey != "" {
+			md, _ := metadata.FromIncomingContext(ctx)
+			if values := md.Get("authorization"); len(values) == 0 || values[0] != "Bearer "+apiKey {
+				return nil, status.Error(codes.Unauthenticated, "invalid API key")
+			}
+		}
+		return handler(ctx, req)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("temporal.api.workflowservice.v1.WorkflowService", healthpb.HealthCheckResponse_SERVING)
+	server := grpc.NewServer(grpc.Creds(creds), grpc.UnaryInterceptor(authorize))
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	lastPeer := ""
+	return listener.Addr().String(), func() string {
+		for {
+			select {
+			case name := <-peers:
+				lastPeer = name
+			default:
+				return lastPeer
+			}
+		}
+	}
+}
+
+func checkHealth(t *testing.T, security Security, address string) error {
+	t.Helper()
+	options := client.Options{HostPort: address}
+	require.NoError(t, security.Apply(&options))
+	c, err := client.NewLazyClient(options)
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err = c.CheckHealth(ctx, &client.CheckHealthRequest{})
+	return err
+}
+
+func TestMutualTLSHandshake(t *testing.T) {
+	pki := newTestPKI(t)
+	address, lastPeer := startTLSFrontend(t, pki, "")
+	certFile, keyFile := pki.issue("worker", "worker", x509.ExtKeyUsageClientAuth)
+
+	// El certificado del servidor es para temporal.test, no para 127.0.0.1
+	security := Security{
+		CAFile:     filepath.Join(pki.dir, "ca.pem"),
+		CertFile:   certFile,
+		KeyFile:    keyFile,
+		ServerName: "temporal.test",
+	}
+	require.NoError(t, checkHealth(t, security, address))
+	assert.Equal(t, "worker", lastPeer())
+
+	t.Run("without client certificate", func(t *testing.T) {
+		assert.Error(t, checkHealth(t, Security{CAFile: security.CAFile, ServerName: "temporal.test"}, address))
+	})
+	t.Run("without server name override", func(t *testing.T) {
+		withoutOverride := security
+		withoutOverride.ServerName = ""
+		assert.Error(t, checkHealth(t, withoutOverride, address))
+	})
+	t.Run("with unknown CA", func(t *testing.T) {
+		other := newTestPKI(t)
+		untrusted := security
+		untrusted.CAFile = filepath.Join(other.dir, "ca.pem")
+		assert.Error(t, checkHealth(t, untrusted, address))
+	})
+}
+
+func TestAPIKeyIsSentAsBearerToken(t *testing.T) {
+	pki := newTestPKI(t)
+	address, _ := startTLSFrontend(t, pki, "secret-key")
+	certFile, keyFile := pki.issue("worker", "worker", x509.ExtKeyUsageClientAuth)
+	security := Security{
+		CAFile:     filepath.Join(pki.dir, "ca.pem"),
+		CertFile:   certFile,
+		KeyFile:    keyFile,
+		ServerName: "temporal.test",
+		APIKey:     "secret-key",
+	}
+	require.NoError(t, checkHealth(t, security, address))
+
+	security.APIKey = "wrong-key"
+	assert.Error(t, checkHealth(t, security, address))
+}
+
+func TestCertReloaderPicksUpRotatedCertificate(t *testing.T) {
+	pki := newTestPKI(t)
+	certFile, keyFile := pki.issue("worker", "worker-v1", x509.ExtKeyUsageClientAuth)
+	reloader, err := NewCertReloader(certFile, keyFile)
+	require.NoError(t, err)
+	reloader.interval = 0
+
+	commonName := func() string {
+		cert, err := reloader.GetClientCertificate(nil)
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		return leaf.Subject.CommonName
+	}
+	assert.Equal(t, "worker-v1", commonName())
+
+	// La rotación reescribe los mismos archivos con una fecha posterior
+	pki.issue("worker", "worker-v2", x509.ExtKeyUsageClientAuth)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, later, later))
+	require.NoError(t, os.Chtimes(keyFile, later, later))
+	assert.Equal(t, "worker-v2", commonName())
+
+	// Un par inválido no reemplaza al vigente
+	require.NoError(t, os.WriteFile(keyFile, []byte("not a key"), 0o600))
+	evenLater := later.Add(time.Minute)
+	require.NoError(t, os.Chtimes(keyFile, evenLater, evenLater))
+	assert.Equal(t, "worker-v2", commonName())
+}
+
+func TestSecurityFromEnv(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "api-key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("file-key\n"), 0o600))
+	env := map[string]string{"TEMPORAL_API_KEY_FILE": keyFile, "TEMPORAL_TLS_SERVER_NAME": "temporal.test"}
+
+	security, err := SecurityFromEnv(func(name string) string { return env[name] })
+	require.NoError(t, err)
+	assert.Equal(t, "file-key", security.APIKey)
+	assert.True(t, security.Enabled())
+	assert.NotContains(t, security.String(), "file-key")
+
+	plain, err := SecurityFromEnv(func(string) string { return "" })
+	require.NoError(t, err)
+	assert.False(t, plain.Enabled())
+
+	_, err = SecurityFromEnv(func(name string) string {
+		if name == "TEMPORAL_TLS_CERT_FILE" {
+			return "client.pem"
+		}
+		return ""
+	})
+	assert.Error(t, err)
+}
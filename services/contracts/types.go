@@ -0,0 +1,98 @@
+package contracts
+
+import "time"
+
+// ApprovalPolicy activa la aprobación manual de WorkflowA y decide cuándo
+// la pide y cuánto espera; sin política en el memo no hay aprobación.
+// La regla compara el campo numérico Field de result2 contra Threshold; los
+// campos vacíos toman el valor por defecto del worker.
+type ApprovalPolicy struct {
+	Field         string  `json:"field,omitempty"`
+	Threshold     float64 `json:"threshold,omitempty"`
+	EscalateAfter string  `json:"escalateAfter,omitempty"`
+	Deadline      string  `json:"deadline,omitempty"`
+}
+
+// Estados posibles de la aprobación manual
+const (
+	ApprovalNotRequired  = "NOT_REQUIRED"
+	ApprovalPending      = "PENDING"
+	ApprovalEscalated    = "ESCALATED"
+	ApprovalApproved     = "APPROVED"
+	ApprovalRejected     = "REJECTED"
+	ApprovalAutoRejected = "AUTO_REJECTED"
+)
+
+// ApprovalDecision es el payload de la señal de aprobación
+type ApprovalDecision struct {
+	Approved bool   `json:"approved"`
+	Approver string `json:"approver"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// ApprovalState es lo que expone la query de aprobación
+type ApprovalState struct {
+	Status      string    `json:"status"`
+	Field       string    `json:"field,omitempty"`
+	Value       float64   `json:"value,omitempty"`
+	RequestedAt time.Time `json:"requestedAt,omitempty"`
+	EscalateAt  time.Time `json:"escalateAt,omitempty"`
+	DeadlineAt  time.Time `json:"deadlineAt,omitempty"`
+	Approver    string    `json:"approver,omitempty"`
+	Comment     string    `json:"comment,omitempty"`
+}
+
+// PipelineInput es el input de DSLWorkflow: el pipeline por nombre y los
+// datos con los que arranca
+type PipelineInput struct {
+	Pipeline string `json:"pipeline"`
+	Data     string `json:"data"`
+}
+
+// BatchInput es el input de BatchWorkflow. Los items llegan en Items o se
+// leen por páginas de la lista ItemsRef con la activity LoadBatchItems.
+type BatchInput struct {
+	BatchID        string   `json:"batchId"`
+	Items          []string `json:"items,omitempty"`
+	ItemsRef       string   `json:"itemsRef,omitempty"`
+	Activity       string   `json:"activity,omitempty"`
+	MaxParallelism int      `json:"maxParallelism,omitempty"`
+	ItemsPerRun    int      `json:"itemsPerRun,omitempty"`
+
+	// Estado que se traspasa entre runs con continue-as-new; el caller no
+	// lo completa
+	Offset  int          `json:"offset,omitempty"`
+	Summary BatchSummary `json:"summary"`
+}
+
+// BatchSummary acumula el resultado de todos los runs del batch; es el
+// resultado de BatchWorkflow
+type BatchSummary struct {
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Failures  []BatchItemFailure `json:"failures,omitempty"`
+	Runs      int                `json:"runs"`
+}
+
+// BatchItemFailure es un item cuya activity falló tras agotar los reintentos
+type BatchItemFailure struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// BatchProgress es la respuesta de la query de avance
+type BatchProgress struct {
+	BatchID   string `json:"batchId"`
+	Total     int    `json:"total"`
+	Processed int    `json:"processed"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	InFlight  int    `json:"inFlight"`
+	Run       int    `json:"run"`
+}
+
+// SLAPolicy es el plazo contractual de una ejecución, medido desde su inicio
+// ("30m")
+type SLAPolicy struct {
+	Duration string `json:"duration"`
+}
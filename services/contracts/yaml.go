@@ -0,0 +1,25 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DecodeYAMLStrict decodifica data (YAML o JSON, que también es YAML) en v
+// y falla con los campos que v no declara. El YAML se pasa por JSON para que
+// los tipos usen un único set de tags.
+func DecodeYAMLStrict(data []byte, v any) error {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
@@ -1,9 +1,12 @@
 package activities
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,15 +14,20 @@ import (
 )
 
 // Activities struct contiene las implementaciones de todas las activities
-type Activities struct{}
+type Activities struct {
+	// sleep simula el tiempo de procesamiento; los tests lo reemplazan para no esperar
+	sleep func(time.Duration)
+	// EscalationWebhook recibe los escalamientos por POST; vacío los deja en el log.
+	// Es un campo y no un setter porque cada método exportado se registra como activity.
+	EscalationWebhook string
+}
 
 // Activity1 procesa el input inicial y retorna un resultado transformado
 func (a *Activities) Activity1(ctx context.Context, input string) (string, error) {
 	logger := activity.GetLogger(ctx)
-	logger.Info("Activity1 started", "input", input)
 
 	// Simular procesamiento
-	time.Sleep(1 * time.Second)
+	a.simulateWork(1 * time.Second)
 
 	// Parsear el input si es JSON
 	var inputData map[string]interface{}
@@ -39,28 +47,24 @@ func (a *Activities) Activity1(ctx context.Context, input string) (string, error
 	result, err := json.Marshal(inputData)
 	if err != nil {
 		logger.Error("Failed to marshal result", "error", err)
-		return "", fmt.Errorf("failed to marshal result: %w", err)
+		return "", NewPermanentError("Activity1", "failed to marshal result", err)
 	}
 
-	resultStr := string(result)
-	logger.Info("Activity1 completed successfully", "result", resultStr)
-
-	return resultStr, nil
+	return string(result), nil
 }
 
 // Activity2 valida y enriquece los datos del paso anterior
 func (a *Activities) Activity2(ctx context.Context, input string) (string, error) {
 	logger := activity.GetLogger(ctx)
-	logger.Info("Activity2 started", "input", input)
 
 	// Simular procesamiento más largo
-	time.Sleep(2 * time.Second)
+	a.simulateWork(2 * time.Second)
 
 	// Parsear el input
 	var inputData map[string]interface{}
 	if err := json.Unmarshal([]byte(input), &inputData); err != nil {
 		logger.Error("Failed to parse input", "error", err)
-		return "", fmt.Errorf("failed to parse input: %w", err)
+		return "", NewValidationError("Activity2", "input", "input is not a JSON object", err)
 	}
 
 	// Validar que Activity1 se ejecutó
@@ -84,28 +88,24 @@ func (a *Activities) Activity2(ctx context.Context, input string) (string, error
 	result, err := json.Marshal(inputData)
 	if err != nil {
 		logger.Error("Failed to marshal result", "error", err)
-		return "", fmt.Errorf("failed to marshal result: %w", err)
+		return "", NewPermanentError("Activity2", "failed to marshal result", err)
 	}
 
-	resultStr := string(result)
-	logger.Info("Activity2 completed successfully", "result", resultStr)
-
-	return resultStr, nil
+	return string(result), nil
 }
 
 // Activity3 realiza el procesamiento final después del child workflow
 func (a *Activities) Activity3(ctx context.Context, input string) (string, error) {
 	logger := activity.GetLogger(ctx)
-	logger.Info("Activity3 (final) started", "input", input)
 
 	// Simular procesamiento
-	time.Sleep(1 * time.Second)
+	a.simulateWork(1 * time.Second)
 
 	// Parsear el input
 	var inputData map[string]interface{}
 	if err := json.Unmarshal([]byte(input), &inputData); err != nil {
 		logger.Error("Failed to parse input", "error", err)
-		return "", fmt.Errorf("failed to parse input: %w", err)
+		return "", NewValidationError("Activity3", "input", "input is not a JSON object", err)
 	}
 
 	// Validar que los pasos anteriores se ejecutaron
@@ -134,28 +134,24 @@ func (a *Activities) Activity3(ctx context.Context, input string) (string, error
 	result, err := json.Marshal(finalData)
 	if err != nil {
 		logger.Error("Failed to marshal result", "error", err)
-		return "", fmt.Errorf("failed to marshal result: %w", err)
+		return "", NewPermanentError("Activity3", "failed to marshal result", err)
 	}
 
-	resultStr := string(result)
-	logger.Info("Activity3 completed successfully - Workflow chain finished", "result", resultStr)
-
-	return resultStr, nil
+	return string(result), nil
 }
 
 // Activity4 es específica del child workflow (WorkflowB)
 func (a *Activities) Activity4(ctx context.Context, input string) (string, error) {
 	logger := activity.GetLogger(ctx)
-	logger.Info("Activity4 (in WorkflowB) started", "input", input)
 
 	// Simular procesamiento
-	time.Sleep(1500 * time.Millisecond)
+	a.simulateWork(1500 * time.Millisecond)
 
 	// Parsear el input
 	var inputData map[string]interface{}
 	if err := json.Unmarshal([]byte(input), &inputData); err != nil {
 		logger.Error("Failed to parse input", "error", err)
-		return "", fmt.Errorf("failed to parse input: %w", err)
+		return "", NewValidationError("Activity4", "input", "input is not a JSON object", err)
 	}
 
 	// Procesar en el contexto del child workflow
@@ -173,16 +169,111 @@ func (a *Activities) Activity4(ctx context.Context, input string) (string, error
 	result, err := json.Marshal(inputData)
 	if err != nil {
 		logger.Error("Failed to marshal result", "error", err)
-		return "", fmt.Errorf("failed to marshal result: %w", err)
+		return "", NewPermanentError("Activity4", "failed to marshal result", err)
+	}
+
+	return string(result), nil
+}
+
+// ==========================================
+// Compensaciones (usadas por la saga de WorkflowA)
+// ==========================================
+
+// CompensateActivity1 deshace el efecto de Activity1
+func (a *Activities) CompensateActivity1(ctx context.Context, result string) error {
+	return a.compensate(ctx, "Activity1", result)
+}
+
+// CompensateActivity2 deshace el efecto de Activity2
+func (a *Activities) CompensateActivity2(ctx context.Context, result string) error {
+	return a.compensate(ctx, "Activity2", result)
+}
+
+// CompensateActivity4 deshace el efecto de Activity4 (ejecutada por WorkflowB)
+func (a *Activities) CompensateActivity4(ctx context.Context, result string) error {
+	return a.compensate(ctx, "Activity4", result)
+}
+
+// compensate simula la reversión del paso indicado a partir de su resultado
+func (a *Activities) compensate(ctx context.Context, step string, result string) error {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Compensating step", "step", step, "result", result)
+
+	// Simular la reversión
+	a.simulateWork(500 * time.Millisecond)
+
+	logger.Info("Step compensated", "step", step)
+	return nil
+}
+
+// Escalation describe un aviso que requiere atención humana
+type Escalation struct {
+	Kind       string `json:"kind"`
+	WorkflowID string `json:"workflowId"`
+	RunID      string `json:"runId"`
+	Message    string `json:"message"`
+}
+
+// Escalate notifica un escalamiento (aprobación vencida, SLA incumplido, etc.).
+// Con webhook configurado lo envía por POST; si no, queda en el log del worker.
+func (a *Activities) Escalate(ctx context.Context, escalation Escalation) error {
+	logger := activity.GetLogger(ctx)
+	logger.Warn("Escalation raised",
+		"kind", escalation.Kind,
+		"workflowID", escalation.WorkflowID,
+		"runID", escalation.RunID,
+		"message", escalation.Message)
+
+	if a.EscalationWebhook == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(escalation)
+	if err != nil {
+		return NewPermanentError("Escalate", "failed to marshal escalation", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.EscalationWebhook, bytes.NewReader(body))
+	if err != nil {
+		return NewPermanentError("Escalate", "invalid escalation webhook", err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 
-	resultStr := string(result)
-	logger.Info("Activity4 completed successfully", "result", resultStr)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return NewTransientError("Escalate", "escalation webhook unreachable", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return NewRateLimitedError("Escalate", "escalation webhook rate limited", time.Duration(retryAfter)*time.Second, nil)
+	case resp.StatusCode >= 500:
+		return NewTransientError("Escalate", fmt.Sprintf("escalation webhook returned %d", resp.StatusCode), nil)
+	case resp.StatusCode >= 400:
+		return NewPermanentError("Escalate", fmt.Sprintf("escalation webhook returned %d", resp.StatusCode), nil)
+	}
 
-	return resultStr, nil
+	logger.Info("Escalation delivered to webhook", "kind", escalation.Kind, "status", resp.StatusCode)
+	return nil
+}
+
+// simulateWork bloquea durante d usando el sleep configurado
+func (a *Activities) simulateWork(d time.Duration) {
+	if a.sleep == nil {
+		time.Sleep(d)
+		return
+	}
+	a.sleep(d)
 }
 
 // NewActivities crea una nueva instancia de Activities
 func NewActivities() *Activities {
-	return &Activities{}
+	return &Activities{sleep: time.Sleep}
+}
+
+// NewActivitiesWithSleep crea Activities con una función de espera propia,
+// útil para que los tests no esperen el tiempo de procesamiento simulado
+func NewActivitiesWithSleep(sleep func(time.Duration)) *Activities {
+	return &Activities{sleep: sleep}
 }
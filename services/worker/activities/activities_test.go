@@ -0,0 +1,138 @@
+package activities
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+type ActivitiesTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env    *testsuite.TestActivityEnvironment
+	act    *Activities
+	sleeps []time.Duration
+}
+
+func TestActivitiesTestSuite(t *testing.T) {
+	suite.Run(t, new(ActivitiesTestSuite))
+}
+
+func (s *ActivitiesTestSuite) SetupTest() {
+	s.sleeps = nil
+	s.act = NewActivitiesWithSleep(func(d time.Duration) {
+		s.sleeps = append(s.sleeps, d)
+	})
+	s.env = s.NewTestActivityEnvironment()
+	s.env.RegisterActivity(s.act)
+}
+
+// requireValidationError comprueba que err sea un ValidationError no reintentable de activity
+func (s *ActivitiesTestSuite) requireValidationError(err error, activity string) {
+	var appErr *temporal.ApplicationError
+	s.Require().True(errors.As(err, &appErr), "expected ApplicationError, got %v", err)
+	s.Equal(contracts.ValidationErrorType, appErr.Type())
+	s.True(appErr.NonRetryable())
+
+	var details ErrorDetails
+	s.Require().NoError(appErr.Details(&details))
+	s.Equal(ErrorDetails{Activity: activity, Field: "input", Reason: "input is not a JSON object"}, details)
+}
+
+// execute corre la activity y decodifica su resultado JSON
+func (s *ActivitiesTestSuite) execute(activityFn interface{}, input string) map[string]interface{} {
+	value, err := s.env.ExecuteActivity(activityFn, input)
+	s.Require().NoError(err)
+
+	var result string
+	s.Require().NoError(value.Get(&result))
+
+	var data map[string]interface{}
+	s.Require().NoError(json.Unmarshal([]byte(result), &data))
+	return data
+}
+
+func (s *ActivitiesTestSuite) Test_Activity1_JSONInput() {
+	data := s.execute(s.act.Activity1, `{"message":"hola"}`)
+
+	s.Equal("hola", data["message"])
+	s.Equal(true, data["activity1_processed"])
+	s.Equal("Input received and validated", data["activity1_message"])
+	s.Contains(data, "activity1_timestamp")
+	s.Equal([]time.Duration{time.Second}, s.sleeps)
+}
+
+func (s *ActivitiesTestSuite) Test_Activity1_RawInput() {
+	data := s.execute(s.act.Activity1, "not json")
+
+	s.Equal("not json", data["raw_input"])
+	s.Equal(true, data["activity1_processed"])
+}
+
+func (s *ActivitiesTestSuite) Test_Activity2_EnrichesMessage() {
+	data := s.execute(s.act.Activity2, `{"message":"hola","activity1_processed":true}`)
+
+	s.Equal(true, data["activity2_processed"])
+	s.Equal("success", data["activity2_status"])
+	s.Equal("Data validated and enriched", data["activity2_validation"])
+	s.Equal("hola", data["original_message"])
+	s.Equal("Processed: hola", data["enriched_message"])
+	s.Contains(data, "activity2_timestamp")
+	s.Equal([]time.Duration{2 * time.Second}, s.sleeps)
+}
+
+func (s *ActivitiesTestSuite) Test_Activity2_InvalidInput() {
+	_, err := s.env.ExecuteActivity(s.act.Activity2, "not json")
+	s.requireValidationError(err, "Activity2")
+}
+
+func (s *ActivitiesTestSuite) Test_Activity3_BuildsFinalResult() {
+	data := s.execute(s.act.Activity3, `{"activity1_processed":true,"activity2_processed":true,"activity4_processed":true}`)
+
+	s.Equal(true, data["workflow_completed"])
+	s.Equal("SUCCESS", data["final_status"])
+	s.Contains(data, "completion_time")
+	s.Contains(data, "all_data")
+	s.ElementsMatch([]interface{}{"Activity1: OK", "Activity2: OK", "Activity4 (WorkflowB): OK"}, data["validations"])
+}
+
+func (s *ActivitiesTestSuite) Test_Activity3_InvalidInput() {
+	_, err := s.env.ExecuteActivity(s.act.Activity3, "not json")
+	s.requireValidationError(err, "Activity3")
+}
+
+func (s *ActivitiesTestSuite) Test_Activity4_TransformsEnrichedMessage() {
+	data := s.execute(s.act.Activity4, `{"enriched_message":"Processed: hola"}`)
+
+	s.Equal(true, data["activity4_processed"])
+	s.Equal("Processed in child workflow (WorkflowB)", data["activity4_message"])
+	s.Equal("WorkflowB", data["child_workflow_execution"])
+	s.Equal("PROCESSED: HOLA", data["child_transformation"])
+	s.Contains(data, "activity4_timestamp")
+	s.Equal([]time.Duration{1500 * time.Millisecond}, s.sleeps)
+}
+
+func (s *ActivitiesTestSuite) Test_Activity4_InvalidInput() {
+	_, err := s.env.ExecuteActivity(s.act.Activity4, "not json")
+	s.requireValidationError(err, "Activity4")
+}
+
+func (s *ActivitiesTestSuite) Test_Compensations() {
+	for _, fn := range []interface{}{s.act.CompensateActivity1, s.act.CompensateActivity2, s.act.CompensateActivity4} {
+		_, err := s.env.ExecuteActivity(fn, `{}`)
+		s.NoError(err)
+	}
+}
+
+func TestNewActivitiesDefaultsToRealSleep(t *testing.T) {
+	require.NotNil(t, NewActivities().sleep)
+}
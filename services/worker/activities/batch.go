@@ -0,0 +1,151 @@
+package activities
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+)
+
+// BatchItemsPage pide una página de la lista de items referenciada por Ref
+type BatchItemsPage struct {
+	Ref    string `json:"ref"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+// BatchItems es una página de items; cada item es el input de la activity
+// por item (los objetos JSON se entregan serializados)
+type BatchItems struct {
+	Items []string `json:"items"`
+	Total int      `json:"total"`
+}
+
+// StoredBatchItems es una lista de items que BatchWorkflow guarda con
+// StoreBatchItems para leerla después por páginas como Ref
+type StoredBatchItems struct {
+	Ref   string   `json:"ref"`
+	Items []string `json:"items"`
+}
+
+// BatchActivities resuelve las listas de items referenciadas por BatchWorkflow
+type BatchActivities struct {
+	dir string
+}
+
+// NewBatchActivities crea las activities de batch sobre el directorio de listas
+func NewBatchActivities(dir string) *BatchActivities {
+	return &BatchActivities{dir: dir}
+}
+
+// LoadBatchItems lee una página de la lista Ref desde el directorio del worker.
+// La lista es <ref>.json (arreglo JSON) o <ref>.jsonl (un item por línea).
+// Solo la página viaja al historial del workflow, no la lista completa.
+func (b *BatchActivities) LoadBatchItems(ctx context.Context, page BatchItemsPage) (BatchItems, error) {
+	logger := activity.GetLogger(ctx)
+
+	if !validRef(page.Ref) {
+		return BatchItems{}, NewValidationError("LoadBatchItems", "ref", fmt.Sprintf("invalid batch reference %q", page.Ref), nil)
+	}
+
+	items, err := b.read(page.Ref)
+	if os.IsNotExist(err) {
+		return BatchItems{}, NewValidationError("LoadBatchItems", "ref", fmt.Sprintf("batch %q not found in %s", page.Ref, b.dir), err)
+	}
+	if err != nil {
+		logger.Error("Failed to read batch items", "ref", page.Ref, "error", err)
+		return BatchItems{}, err
+	}
+
+	result := BatchItems{Items: []string{}, Total: len(items)}
+	if page.Offset < len(items) {
+		end := len(items)
+		if page.Limit > 0 && page.Offset+page.Limit < end {
+			end = page.Offset + page.Limit
+		}
+		result.Items = items[page.Offset:end]
+	}
+
+	return result, nil
+}
+
+// StoreBatchItems guarda Items como <ref>.json en el directorio del worker,
+// donde LoadBatchItems los lee por páginas. Escribe un archivo temporal y lo
+// renombra, así un reintento nunca deja una lista a medias.
+func (b *BatchActivities) StoreBatchItems(ctx context.Context, stored StoredBatchItems) error {
+	if !validRef(stored.Ref) {
+		return NewValidationError("StoreBatchItems", "ref", fmt.Sprintf("invalid batch reference %q", stored.Ref), nil)
+	}
+
+	// Cada item se guarda como string JSON, que itemInput entrega sin comillas
+	data, _ := json.Marshal(stored.Items)
+
+	path := filepath.Join(b.dir, stored.Ref+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return NewTransientError("StoreBatchItems", "failed to write batch items", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return NewTransientError("StoreBatchItems", "failed to write batch items", err)
+	}
+
+	activity.GetLogger(ctx).Info("Stored batch items", "ref", stored.Ref, "items", len(stored.Items))
+	return nil
+}
+
+// validRef acepta nombres de lista sin separadores de ruta
+func validRef(ref string) bool {
+	return ref != "" && !strings.ContainsAny(ref, `/\`)
+}
+
+// read decodifica la lista completa; busca primero .json y luego .jsonl
+func (b *BatchActivities) read(ref string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(b.dir, ref+".json"))
+	if err == nil {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, NewValidationError("LoadBatchItems", "ref", fmt.Sprintf("batch %q is not a JSON array", ref), err)
+		}
+		items := make([]string, len(raw))
+		for i, item := range raw {
+			items[i] = itemInput(item)
+		}
+		return items, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, NewTransientError("LoadBatchItems", "failed to read batch items", err)
+	}
+
+	data, err = os.ReadFile(filepath.Join(b.dir, ref+".jsonl"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, NewTransientError("LoadBatchItems", "failed to read batch items", err)
+	}
+
+	items := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			items = append(items, line)
+		}
+	}
+	return items, scanner.Err()
+}
+
+// itemInput convierte un item a input de activity: los strings JSON se
+// entregan sin comillas y el resto tal cual
+func itemInput(item json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(item, &s); err == nil {
+		return s
+	}
+	return string(item)
+}
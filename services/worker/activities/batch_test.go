@@ -0,0 +1,50 @@
+package activities
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+func (s *ActivitiesTestSuite) Test_LoadBatchItems_Pages() {
+	dir := s.T().TempDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "orders.json"), []byte(`[{"id":1},"two",{"id":3}]`), 0o644))
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "lines.jsonl"), []byte("{\"id\":1}\n\n{\"id\":2}\n"), 0o644))
+	batch := NewBatchActivities(dir)
+	s.env.RegisterActivity(batch)
+
+	value, err := s.env.ExecuteActivity(batch.LoadBatchItems, BatchItemsPage{Ref: "orders", Offset: 1, Limit: 5})
+	s.Require().NoError(err)
+	var page BatchItems
+	s.Require().NoError(value.Get(&page))
+	s.Equal(BatchItems{Items: []string{"two", `{"id":3}`}, Total: 3}, page)
+
+	value, err = s.env.ExecuteActivity(batch.LoadBatchItems, BatchItemsPage{Ref: "lines", Limit: 1})
+	s.Require().NoError(err)
+	s.Require().NoError(value.Get(&page))
+	s.Equal(BatchItems{Items: []string{`{"id":1}`}, Total: 2}, page)
+
+	_, err = s.env.ExecuteActivity(batch.LoadBatchItems, BatchItemsPage{Ref: "missing"})
+	s.Error(err)
+	s.Contains(err.Error(), contracts.ValidationErrorType)
+}
+
+func (s *ActivitiesTestSuite) Test_StoreBatchItems_RoundTripsThroughLoad() {
+	batch := NewBatchActivities(s.T().TempDir())
+	s.env.RegisterActivity(batch)
+
+	items := []string{`{"id":1}`, "plain", `"quoted"`}
+	_, err := s.env.ExecuteActivity(batch.StoreBatchItems, StoredBatchItems{Ref: "inline-run", Items: items})
+	s.Require().NoError(err)
+
+	value, err := s.env.ExecuteActivity(batch.LoadBatchItems, BatchItemsPage{Ref: "inline-run", Offset: 1, Limit: 5})
+	s.Require().NoError(err)
+	var page BatchItems
+	s.Require().NoError(value.Get(&page))
+	s.Equal(BatchItems{Items: items[1:], Total: 3}, page)
+
+	_, err = s.env.ExecuteActivity(batch.StoreBatchItems, StoredBatchItems{Ref: "../escape", Items: items})
+	s.Error(err)
+	s.Contains(err.Error(), contracts.ValidationErrorType)
+}
@@ -0,0 +1,60 @@
+package activities
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/activity"
+)
+
+// DefaultChunkSize es la cantidad de items procesados entre heartbeats
+const DefaultChunkSize = 10
+
+// ChunkProgress es el avance que se guarda en cada heartbeat: el próximo
+// item a procesar y el estado acumulado hasta ese punto
+type ChunkProgress[S any] struct {
+	Next  int `json:"next"`
+	State S   `json:"state"`
+}
+
+// processChunks recorre total items de a chunkSize, registrando un heartbeat
+// con el avance al terminar cada bloque. Si el intento anterior dejó detalles
+// de heartbeat, retoma desde ahí con el estado que había acumulado.
+// Devuelve el índice desde el que se retomó (0 en el primer intento).
+//
+// Si process falla, el último heartbeat apunta al inicio del bloque en curso,
+// así el reintento repite como mucho un bloque.
+func processChunks[S any](ctx context.Context, total, chunkSize int, state *S, process func(i int) error) (int, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	start := 0
+	if activity.HasHeartbeatDetails(ctx) {
+		var progress ChunkProgress[S]
+		if err := activity.GetHeartbeatDetails(ctx, &progress); err == nil && progress.Next <= total {
+			start = progress.Next
+			*state = progress.State
+			activity.GetLogger(ctx).Info("Resuming from heartbeat", "next", start, "total", total)
+		}
+	}
+
+	for chunkStart := start; chunkStart < total; chunkStart += chunkSize {
+		// La cancelación llega con el heartbeat; se revisa entre bloques
+		if err := ctx.Err(); err != nil {
+			return start, err
+		}
+
+		chunkEnd := chunkStart + chunkSize
+		if chunkEnd > total {
+			chunkEnd = total
+		}
+		for i := chunkStart; i < chunkEnd; i++ {
+			if err := process(i); err != nil {
+				return start, err
+			}
+		}
+
+		activity.RecordHeartbeat(ctx, ChunkProgress[S]{Next: chunkEnd, State: *state})
+	}
+	return start, nil
+}
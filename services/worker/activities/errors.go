@@ -0,0 +1,50 @@
+package activities
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// ErrorDetails es el detalle adjunto a cada error de la taxonomía
+type ErrorDetails struct {
+	Activity   string `json:"activity,omitempty"`
+	Field      string `json:"field,omitempty"`
+	Reason     string `json:"reason"`
+	RetryAfter string `json:"retryAfter,omitempty"`
+}
+
+// NewValidationError indica que el input de activity es inválido
+func NewValidationError(activity, field, reason string, cause error) error {
+	return temporal.NewNonRetryableApplicationError(
+		fmt.Sprintf("%s: invalid input: %s", activity, reason), contracts.ValidationErrorType, cause,
+		ErrorDetails{Activity: activity, Field: field, Reason: reason})
+}
+
+// NewPermanentError indica un fallo de activity que no se resolverá reintentando
+func NewPermanentError(activity, reason string, cause error) error {
+	return temporal.NewNonRetryableApplicationError(
+		fmt.Sprintf("%s: %s", activity, reason), contracts.PermanentErrorType, cause,
+		ErrorDetails{Activity: activity, Reason: reason})
+}
+
+// NewTransientError indica un fallo temporal; la RetryPolicy lo reintenta
+func NewTransientError(activity, reason string, cause error) error {
+	return temporal.NewApplicationErrorWithCause(
+		fmt.Sprintf("%s: %s", activity, reason), contracts.TransientErrorType, cause,
+		ErrorDetails{Activity: activity, Reason: reason})
+}
+
+// NewRateLimitedError indica que una dependencia limitó a activity.
+// retryAfter es informativo; el reintento lo programa la RetryPolicy.
+func NewRateLimitedError(activity, reason string, retryAfter time.Duration, cause error) error {
+	details := ErrorDetails{Activity: activity, Reason: reason}
+	if retryAfter > 0 {
+		details.RetryAfter = retryAfter.String()
+	}
+	return temporal.NewApplicationErrorWithCause(
+		fmt.Sprintf("%s: rate limited: %s", activity, reason), contracts.RateLimitedErrorType, cause, details)
+}
@@ -0,0 +1,22 @@
+package activities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/worker/profiles"
+)
+
+// TestProfilesDoNotRetryPermanentErrors evita que los perfiles y la
+// taxonomía de errores se desincronicen
+func TestProfilesDoNotRetryPermanentErrors(t *testing.T) {
+	registry := profiles.NewRegistry()
+	for _, name := range registry.Names() {
+		retry := registry.Options(name, "Activity1").RetryPolicy
+		if assert.NotNil(t, retry, name) {
+			assert.ElementsMatch(t, contracts.NonRetryableErrorTypes, retry.NonRetryableErrorTypes, name)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package activities
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+func (s *ActivitiesTestSuite) Test_Escalate_LogSink() {
+	_, err := s.env.ExecuteActivity(s.act.Escalate, Escalation{Kind: "sla", WorkflowID: "wf-1", Message: "late"})
+	s.NoError(err)
+}
+
+func (s *ActivitiesTestSuite) Test_Escalate_Webhook() {
+	var received Escalation
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal(http.MethodPost, r.Method)
+		s.NoError(json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	s.act.EscalationWebhook = server.URL
+
+	_, err := s.env.ExecuteActivity(s.act.Escalate, Escalation{Kind: "sla", WorkflowID: "wf-1", RunID: "run-1", Message: "late"})
+	s.Require().NoError(err)
+	s.Equal(Escalation{Kind: "sla", WorkflowID: "wf-1", RunID: "run-1", Message: "late"}, received)
+}
+
+func (s *ActivitiesTestSuite) Test_Escalate_WebhookErrors() {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	s.act.EscalationWebhook = server.URL
+
+	for code, errorType := range map[int]string{
+		http.StatusServiceUnavailable: contracts.TransientErrorType,
+		http.StatusTooManyRequests:    contracts.RateLimitedErrorType,
+		http.StatusBadRequest:         contracts.PermanentErrorType,
+	} {
+		status = code
+		_, err := s.env.ExecuteActivity(s.act.Escalate, Escalation{Kind: "sla"})
+		s.Require().Error(err)
+		s.Contains(err.Error(), errorType)
+	}
+}
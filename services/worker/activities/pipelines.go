@@ -0,0 +1,34 @@
+package activities
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/temporal-aws-poc/worker/dsl"
+)
+
+// PipelineActivities expone las definiciones de pipelines a DSLWorkflow
+type PipelineActivities struct {
+	loader *dsl.Loader
+}
+
+// NewPipelineActivities crea las activities de pipelines sobre un loader
+func NewPipelineActivities(loader *dsl.Loader) *PipelineActivities {
+	return &PipelineActivities{loader: loader}
+}
+
+// LoadPipeline lee y valida la definición de un pipeline por nombre.
+// Una definición inexistente o inválida no se arregla reintentando.
+func (p *PipelineActivities) LoadPipeline(ctx context.Context, name string) (*dsl.Definition, error) {
+	logger := activity.GetLogger(ctx)
+
+	definition, err := p.loader.Load(name)
+	if err != nil {
+		logger.Error("Failed to load pipeline", "pipeline", name, "error", err)
+		return nil, temporal.NewNonRetryableApplicationError(err.Error(), "InvalidPipeline", err)
+	}
+
+	return definition, nil
+}
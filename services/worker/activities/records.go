@@ -0,0 +1,79 @@
+package activities
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+)
+
+// RecordBatch es el input de ProcessRecords
+type RecordBatch struct {
+	BatchID string            `json:"batchId"`
+	Records []json.RawMessage `json:"records"`
+	// ChunkSize es la cantidad de records entre heartbeats; 0 usa DefaultChunkSize
+	ChunkSize int `json:"chunkSize,omitempty"`
+}
+
+// RecordFailure es un record que no se pudo procesar
+type RecordFailure struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// RecordBatchResult resume el procesamiento de un RecordBatch
+type RecordBatchResult struct {
+	BatchID     string          `json:"batchId"`
+	Total       int             `json:"total"`
+	Processed   int             `json:"processed"`
+	Failed      int             `json:"failed"`
+	Failures    []RecordFailure `json:"failures,omitempty"`
+	ResumedFrom int             `json:"resumedFrom,omitempty"`
+}
+
+// ProcessRecords procesa un lote de records en bloques con heartbeat.
+// Si el worker cae a mitad del lote, Temporal lo detecta al vencer el
+// HeartbeatTimeout y el reintento continúa desde el último bloque completo.
+// Un record inválido no detiene el lote: se reporta en Failures.
+func (a *Activities) ProcessRecords(ctx context.Context, batch RecordBatch) (RecordBatchResult, error) {
+	logger := activity.GetLogger(ctx)
+
+	if len(batch.Records) == 0 {
+		return RecordBatchResult{}, NewValidationError("ProcessRecords", "records", "batch has no records", nil)
+	}
+
+	result := RecordBatchResult{BatchID: batch.BatchID, Total: len(batch.Records)}
+	resumedFrom, err := processChunks(ctx, len(batch.Records), batch.ChunkSize, &result, func(i int) error {
+		// Simular procesamiento de cada record
+		a.simulateWork(200 * time.Millisecond)
+
+		if err := processRecord(batch.Records[i]); err != nil {
+			result.Failed++
+			result.Failures = append(result.Failures, RecordFailure{Index: i, Error: err.Error()})
+			return nil
+		}
+		result.Processed++
+		return nil
+	})
+	if err != nil {
+		logger.Error("ProcessRecords interrupted", "batchId", batch.BatchID, "error", err)
+		return RecordBatchResult{}, err
+	}
+	result.ResumedFrom = resumedFrom
+
+	return result, nil
+}
+
+// processRecord valida un record: debe ser un objeto JSON con campo "id"
+func processRecord(record json.RawMessage) error {
+	var data map[string]interface{}
+	if err := json.Unmarshal(record, &data); err != nil {
+		return fmt.Errorf("record is not a JSON object: %w", err)
+	}
+	if _, ok := data["id"]; !ok {
+		return fmt.Errorf("record has no id")
+	}
+	return nil
+}
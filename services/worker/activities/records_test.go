@@ -0,0 +1,66 @@
+package activities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+func records(raw ...string) []json.RawMessage {
+	out := make([]json.RawMessage, len(raw))
+	for i, r := range raw {
+		out[i] = json.RawMessage(r)
+	}
+	return out
+}
+
+func (s *ActivitiesTestSuite) Test_ProcessRecords_ReportsFailuresPerRecord() {
+	value, err := s.env.ExecuteActivity(s.act.ProcessRecords, RecordBatch{
+		BatchID:   "b1",
+		Records:   records(`{"id":1}`, `{"id":2}`, `"text"`, `{"name":"no id"}`, `{"id":5}`),
+		ChunkSize: 2,
+	})
+	s.Require().NoError(err)
+
+	var result RecordBatchResult
+	s.Require().NoError(value.Get(&result))
+	s.Equal(5, result.Total)
+	s.Equal(3, result.Processed)
+	s.Equal(2, result.Failed)
+	s.Equal([]int{2, 3}, []int{result.Failures[0].Index, result.Failures[1].Index})
+	s.Equal(0, result.ResumedFrom)
+	s.Len(s.sleeps, 5)
+}
+
+func (s *ActivitiesTestSuite) Test_ProcessRecords_ResumesFromHeartbeat() {
+	// Un intento anterior completó los dos primeros bloques con un fallo
+	s.env.SetHeartbeatDetails(ChunkProgress[RecordBatchResult]{
+		Next: 4,
+		State: RecordBatchResult{
+			BatchID: "b1", Total: 5, Processed: 3, Failed: 1,
+			Failures: []RecordFailure{{Index: 2, Error: "record is not a JSON object"}},
+		},
+	})
+
+	value, err := s.env.ExecuteActivity(s.act.ProcessRecords, RecordBatch{
+		BatchID:   "b1",
+		Records:   records(`{"id":1}`, `{"id":2}`, `"text"`, `{"id":4}`, `{"id":5}`),
+		ChunkSize: 2,
+	})
+	s.Require().NoError(err)
+
+	var result RecordBatchResult
+	s.Require().NoError(value.Get(&result))
+	s.Equal(4, result.ResumedFrom)
+	s.Equal(4, result.Processed)
+	s.Equal(1, result.Failed)
+	// Solo se procesó el record pendiente
+	s.Equal([]time.Duration{200 * time.Millisecond}, s.sleeps)
+}
+
+func (s *ActivitiesTestSuite) Test_ProcessRecords_EmptyBatch() {
+	_, err := s.env.ExecuteActivity(s.act.ProcessRecords, RecordBatch{BatchID: "empty"})
+	s.Error(err)
+	s.Contains(err.Error(), contracts.ValidationErrorType)
+}
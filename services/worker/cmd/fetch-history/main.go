@@ -0,0 +1,117 @@
+// fetch-history descarga el historial de una ejecución desde un cluster en
+// vivo y lo guarda en el corpus de replay (replay/testdata/histories).
+//
+// Uso:
+//
+//	go run ./cmd/fetch-history -workflow-id test-demo-a-002 [-run-id <runId>]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/sdk/client"
+
+	"github.com/temporal-aws-poc/contracts/temporalconn"
+	"github.com/temporal-aws-poc/worker/replay"
+)
+
+func main() {
+	defaultHostPort := os.Getenv("TEMPORAL_HOST_PORT")
+	if defaultHostPort == "" {
+		defaultHostPort = "localhost:7233"
+	}
+
+	hostPort := flag.String("address", defaultHostPort, "Temporal frontend host:port")
+	namespace := flag.String("namespace", client.DefaultNamespace, "Temporal namespace")
+	workflowID := flag.String("workflow-id", "", "workflow ID to download (required)")
+	runID := flag.String("run-id", "", "run ID to download (defaults to the latest run)")
+	corpusDir := flag.String("out", filepath.Join("replay", replay.CorpusDir), "replay corpus directory")
+	flag.Parse()
+
+	if *workflowID == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Misma configuración de TLS y API key que el worker (TEMPORAL_TLS_*)
+	options := client.Options{
+		HostPort:  *hostPort,
+		Namespace: *namespace,
+	}
+	security, err := temporalconn.SecurityFromEnv(os.Getenv)
+	if err == nil {
+		err = security.Apply(&options)
+	}
+	if err != nil {
+		log.Fatalf("Invalid TLS configuration: %v", err)
+	}
+
+	c, err := client.Dial(options)
+	if err != nil {
+		log.Fatalf("Unable to create Temporal client: %v", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	history := &historypb.History{}
+	iter := c.GetWorkflowHistory(ctx, *workflowID, *runID, false, enumspb.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+	for iter.HasNext() {
+		event, err := iter.Next()
+		if err != nil {
+			log.Fatalf("Failed to read history: %v", err)
+		}
+		history.Events = append(history.Events, event)
+	}
+	if len(history.Events) == 0 {
+		log.Fatalf("Workflow %s has no history events", *workflowID)
+	}
+
+	// Solo vale la pena guardar ejecuciones cerradas: una abierta puede cambiar
+	last := history.Events[len(history.Events)-1]
+	if !isCloseEvent(last.GetEventType()) {
+		log.Printf("Warning: workflow %s is still open, history ends at event %d", *workflowID, last.GetEventId())
+	}
+
+	workflowType := history.Events[0].GetWorkflowExecutionStartedEventAttributes().GetWorkflowType().GetName()
+	path := replay.HistoryPath(*corpusDir, workflowType, *workflowID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Fatalf("Failed to create corpus directory: %v", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", path, err)
+	}
+	defer f.Close()
+
+	marshaler := jsonpb.Marshaler{Indent: "  "}
+	if err := marshaler.Marshal(f, history); err != nil {
+		log.Fatalf("Failed to write history: %v", err)
+	}
+
+	log.Printf("Saved %d events of %s (%s) to %s", len(history.Events), *workflowID, workflowType, path)
+}
+
+// isCloseEvent indica si el evento cierra la ejecución
+func isCloseEvent(eventType enumspb.EventType) bool {
+	switch eventType {
+	case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_COMPLETED,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_FAILED,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_TIMED_OUT,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CANCELED,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_TERMINATED,
+		enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CONTINUED_AS_NEW:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+// Package connection conecta el worker con el frontend de Temporal. En ECS
+// el frontend puede tardar en arrancar: en lugar de fallar con el primer
+// error, Dial reintenta con backoff exponencial hasta un tiempo máximo, y
+// Monitor vigila la conexión ya establecida (gRPC reconecta por su cuenta).
+package connection
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.temporal.io/sdk/client"
+
+	"github.com/temporal-aws-poc/contracts/temporalconn"
+)
+
+// Métricas de la conexión
+const (
+	// DialAttemptsMetric cuenta los intentos de conexión, con tag result
+	DialAttemptsMetric = "worker_temporal_dial_attempts"
+	// ConnectedMetric vale 1 mientras el frontend responde y 0 si no
+	ConnectedMetric = "worker_temporal_connected"
+	// ReconnectsMetric cuenta las veces que se recuperó la conexión perdida
+	ReconnectsMetric = "worker_temporal_reconnects"
+)
+
+// checkTimeout es el tiempo máximo de cada health check
+const checkTimeout = 5 * time.Second
+
+// Dial crea el cliente y espera a que el frontend responda, reintentando
+// según backoff. Devuelve error si se agota MaxWait o se cancela ctx.
+// Las métricas se registran en options.MetricsHandler.
+func Dial(ctx context.Context, options client.Options, backoff temporalconn.Backoff) (client.Client, error) {
+	// El cliente lazy no conecta al crearse; solo falla con options inválidas
+	c, err := client.NewLazyClient(options)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := metricsHandler(options)
+	connected := handler.Gauge(ConnectedMetric)
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := check(ctx, c)
+		if err == nil {
+			handler.WithTags(map[string]string{"result": "success"}).Counter(DialAttemptsMetric).Inc(1)
+			connected.Update(1)
+			if attempt > 1 {
+				log.Printf("Connected to Temporal at %s after %d attempts", options.HostPort, attempt)
+			}
+			return c, nil
+		}
+		handler.WithTags(map[string]string{"result": "failure"}).Counter(DialAttemptsMetric).Inc(1)
+		connected.Update(0)
+
+		delay := backoff.Delay(attempt)
+		if backoff.MaxWait > 0 && time.Since(start)+delay > backoff.MaxWait {
+			c.Close()
+			return nil, fmt.Errorf("unable to connect to Temporal at %s after %d attempts in %s: %w",
+				options.HostPort, attempt, time.Since(start).Round(time.Second), err)
+		}
+		log.Printf("Temporal at %s is not reachable (attempt %d): %v; retrying in %s", options.HostPort, attempt, err, delay)
+
+		select {
+		case <-ctx.Done():
+			c.Close()
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// Monitor comprueba la conexión cada interval hasta que ctx se cancela.
+// gRPC restablece la conexión por su cuenta; Monitor solo actualiza las
+// métricas y llama a onChange (si no es nil) cuando el estado cambia.
+func Monitor(ctx context.Context, c client.Client, interval time.Duration, handler client.MetricsHandler, onChange func(connected bool)) {
+	if handler == nil {
+		handler = client.MetricsNopHandler
+	}
+	connected := true
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := check(ctx, c)
+		if ctx.Err() != nil {
+			return
+		}
+		switch {
+		case err != nil && connected:
+			log.Printf("Lost connection to Temporal: %v", err)
+		case err == nil && !connected:
+			log.Println("Connection to Temporal restored")
+			handler.Counter(ReconnectsMetric).Inc(1)
+		default:
+			continue
+		}
+		connected = err == nil
+		if connected {
+			handler.Gauge(ConnectedMetric).Update(1)
+		} else {
+			handler.Gauge(ConnectedMetric).Update(0)
+		}
+		if onChange != nil {
+			onChange(connected)
+		}
+	}
+}
+
+func check(ctx context.Context, c client.Client) error {
+	checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	_, err := c.CheckHealth(checkCtx, &client.CheckHealthRequest{})
+	return err
+}
+
+func metricsHandler(options client.Options) client.MetricsHandler {
+	if options.MetricsHandler == nil {
+		return client.MetricsNopHandler
+	}
+	return options.MetricsHandler
+}
@@ -0,0 +1,102 @@
+package connection
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/temporal-aws-poc/contracts/temporalconn"
+	"github.com/temporal-aws-poc/worker/metrics"
+)
+
+// fakeFrontend es un servidor gRPC que solo responde el health check del
+// WorkflowService, lo único que usan Dial y Monitor
+type fakeFrontend struct {
+	server *grpc.Server
+}
+
+func startFrontend(t *testing.T, address string) *fakeFrontend {
+	t.Helper()
+	listener, err := net.Listen("tcp", address)
+	require.NoError(t, err)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("temporal.api.workflowservice.v1.WorkflowService", healthpb.HealthCheckResponse_SERVING)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return &fakeFrontend{server: server}
+}
+
+// freeAddress devuelve una dirección local donde no escucha nadie
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+	return address
+}
+
+func TestDialWaitsForTheFrontend(t *testing.T) {
+	address := freeAddress(t)
+	registry := metrics.NewRegistry()
+
+	// El frontend arranca después del primer intento
+	time.AfterFunc(150*time.Millisecond, func() { startFrontend(t, address) })
+
+	c, err := Dial(context.Background(), client.Options{HostPort: address, MetricsHandler: registry.Handler()},
+		temporalconn.Backoff{Initial: 100 * time.Millisecond, Max: 200 * time.Millisecond, MaxWait: 10 * time.Second})
+	require.NoError(t, err)
+	defer c.Close()
+
+	assert.GreaterOrEqual(t, registry.CounterValue(DialAttemptsMetric, map[string]string{"result": "failure"}), int64(1))
+	assert.Equal(t, int64(1), registry.CounterValue(DialAttemptsMetric, map[string]string{"result": "success"}))
+}
+
+func TestDialGivesUpAfterMaxWait(t *testing.T) {
+	registry := metrics.NewRegistry()
+	_, err := Dial(context.Background(), client.Options{HostPort: freeAddress(t), MetricsHandler: registry.Handler()},
+		temporalconn.Backoff{Initial: 50 * time.Millisecond, Max: 50 * time.Millisecond, MaxWait: 200 * time.Millisecond})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to connect to Temporal")
+	assert.Equal(t, int64(0), registry.CounterValue(DialAttemptsMetric, map[string]string{"result": "success"}))
+}
+
+func TestDialStopsWhenContextIsCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err := Dial(ctx, client.Options{HostPort: freeAddress(t)}, temporalconn.Backoff{Initial: time.Hour})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestMonitorReportsLostAndRestoredConnection(t *testing.T) {
+	address := freeAddress(t)
+	frontend := startFrontend(t, address)
+	registry := metrics.NewRegistry()
+
+	c, err := Dial(context.Background(), client.Options{HostPort: address}, temporalconn.DefaultBackoff)
+	require.NoError(t, err)
+	defer c.Close()
+
+	changes := make(chan bool, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go Monitor(ctx, c, 50*time.Millisecond, registry.Handler(), func(connected bool) { changes <- connected })
+
+	frontend.server.Stop()
+	assert.False(t, <-changes)
+
+	startFrontend(t, address)
+	assert.True(t, <-changes)
+	assert.Equal(t, int64(1), registry.CounterValue(ReconnectsMetric, nil))
+}
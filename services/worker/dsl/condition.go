@@ -0,0 +1,175 @@
+package dsl
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Operadores soportados en las condiciones
+const (
+	OpExists    = "exists"
+	OpNotExists = "notExists"
+	OpEquals    = "equals"
+	OpNotEquals = "notEquals"
+	OpGreater   = "gt"
+	OpGreaterEq = "gte"
+	OpLess      = "lt"
+	OpLessEq    = "lte"
+)
+
+// Condition compara el valor de un JSONPath de los datos acumulados
+type Condition struct {
+	Path  string      `json:"path"`
+	Op    string      `json:"op"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+func (c *Condition) validate() error {
+	if _, err := parsePath(c.Path); err != nil {
+		return err
+	}
+	switch c.Op {
+	case OpExists, OpNotExists, OpEquals, OpNotEquals:
+	case OpGreater, OpGreaterEq, OpLess, OpLessEq:
+		if _, ok := toFloat(c.Value); !ok {
+			return fmt.Errorf("operator %s requires a numeric value", c.Op)
+		}
+	default:
+		return fmt.Errorf("unknown operator %q", c.Op)
+	}
+	return nil
+}
+
+// Evaluate aplica la condición sobre data
+func (c *Condition) Evaluate(data interface{}) (bool, error) {
+	value, found, err := Lookup(data, c.Path)
+	if err != nil {
+		return false, err
+	}
+
+	switch c.Op {
+	case OpExists:
+		return found, nil
+	case OpNotExists:
+		return !found, nil
+	case OpEquals:
+		return found && equal(value, c.Value), nil
+	case OpNotEquals:
+		return !found || !equal(value, c.Value), nil
+	}
+
+	if !found {
+		return false, nil
+	}
+	left, ok := toFloat(value)
+	if !ok {
+		return false, nil
+	}
+	right, _ := toFloat(c.Value)
+	switch c.Op {
+	case OpGreater:
+		return left > right, nil
+	case OpGreaterEq:
+		return left >= right, nil
+	case OpLess:
+		return left < right, nil
+	case OpLessEq:
+		return left <= right, nil
+	}
+	return false, fmt.Errorf("unknown operator %q", c.Op)
+}
+
+// Lookup resuelve un JSONPath simple ($.a.b[0].c) sobre datos decodificados de JSON
+func Lookup(data interface{}, path string) (interface{}, bool, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	current := data
+	for _, segment := range segments {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false, nil
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false, nil
+			}
+			current = node[index]
+		default:
+			return nil, false, nil
+		}
+	}
+	return current, true, nil
+}
+
+// parsePath convierte "$.a.b[0]" en ["a", "b", "0"]
+func parsePath(path string) ([]string, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with $", path)
+	}
+
+	var segments []string
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty field name", path)
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unclosed [", path)
+			}
+			key := strings.Trim(rest[1:end], `'"`)
+			if key == "" {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty index", path)
+			}
+			segments = append(segments, key)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", path, rest[0])
+		}
+	}
+	return segments, nil
+}
+
+func equal(a, b interface{}) bool {
+	if af, ok := toFloat(a); ok {
+		if bf, ok := toFloat(b); ok {
+			return af == bf
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,58 @@
+package dsl
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	var data interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"order":{"items":[{"sku":"A1"}],"total":120.5},"status":"ok"}`), &data))
+
+	value, found, err := Lookup(data, "$.order.items[0].sku")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "A1", value)
+
+	value, found, err = Lookup(data, "$['status']")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "ok", value)
+
+	_, found, err = Lookup(data, "$.order.items[3]")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	_, _, err = Lookup(data, "order.total")
+	require.Error(t, err)
+}
+
+func TestConditionEvaluate(t *testing.T) {
+	data := map[string]interface{}{"total": 120.5, "status": "ok", "valid": true}
+
+	cases := []struct {
+		condition Condition
+		expected  bool
+	}{
+		{Condition{Path: "$.status", Op: OpExists}, true},
+		{Condition{Path: "$.missing", Op: OpNotExists}, true},
+		{Condition{Path: "$.status", Op: OpEquals, Value: "ok"}, true},
+		{Condition{Path: "$.valid", Op: OpEquals, Value: true}, true},
+		{Condition{Path: "$.status", Op: OpNotEquals, Value: "ok"}, false},
+		{Condition{Path: "$.total", Op: OpGreater, Value: 100}, true},
+		{Condition{Path: "$.total", Op: OpLessEq, Value: 100.0}, false},
+		{Condition{Path: "$.missing", Op: OpGreaterEq, Value: 1}, false},
+	}
+
+	for _, c := range cases {
+		require.NoError(t, c.condition.validate())
+		ok, err := c.condition.Evaluate(data)
+		require.NoError(t, err)
+		require.Equal(t, c.expected, ok, "%+v", c.condition)
+	}
+
+	require.Error(t, (&Condition{Path: "$.total", Op: OpGreater, Value: "x"}).validate())
+	require.Error(t, (&Condition{Path: "$.total", Op: "between"}).validate())
+}
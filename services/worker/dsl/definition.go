@@ -0,0 +1,200 @@
+// Package dsl define el formato declarativo de pipelines que interpreta
+// DSLWorkflow: pasos secuenciales, ramas paralelas, pipelines hijos y pasos
+// condicionales evaluados con JSONPath sobre los datos acumulados.
+package dsl
+
+import (
+	"fmt"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/worker/profiles"
+)
+
+// Definition es un pipeline completo tal como se declara en YAML o JSON
+type Definition struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Root        Statement `json:"root"`
+
+	// Children lo completa el loader con los pipelines hijos referenciados,
+	// así el workflow recibe la definición completa y no lee archivos
+	Children map[string]*Definition `json:"children,omitempty"`
+}
+
+// Statement es un nodo del pipeline; debe tener exactamente uno de
+// Activity, Sequence, Parallel o Child. When es opcional.
+type Statement struct {
+	When     *Condition    `json:"when,omitempty"`
+	Activity *ActivityStep `json:"activity,omitempty"`
+	Sequence []Statement   `json:"sequence,omitempty"`
+	Parallel []Statement   `json:"parallel,omitempty"`
+	Child    *ChildStep    `json:"child,omitempty"`
+}
+
+// ActivityStep ejecuta una activity registrada en el worker.
+// El input es, en orden de prioridad: InputTemplate renderizado con las
+// variables, la variable Input, o el resultado del paso anterior.
+// Profile fija el perfil de ActivityOptions del paso, salvo que el caller
+// elija uno para toda la ejecución; Options ajusta valores sobre ese perfil.
+type ActivityStep struct {
+	Name          string       `json:"name"`
+	Input         string       `json:"input,omitempty"`
+	InputTemplate string       `json:"inputTemplate,omitempty"`
+	Result        string       `json:"result,omitempty"`
+	Profile       string       `json:"profile,omitempty"`
+	Options       *StepOptions `json:"options,omitempty"`
+}
+
+// ChildStep ejecuta otro pipeline como child workflow
+type ChildStep struct {
+	Pipeline string `json:"pipeline"`
+	Input    string `json:"input,omitempty"`
+	Result   string `json:"result,omitempty"`
+}
+
+// StepOptions sobreescribe las ActivityOptions del perfil de un paso
+type StepOptions = profiles.Options
+
+// Input es el input de DSLWorkflow: el contracts.PipelineInput que envía el
+// API y, en los pipelines hijos, la definición ya resuelta
+type Input struct {
+	contracts.PipelineInput
+	Definition *Definition `json:"definition,omitempty"`
+}
+
+// Validate revisa la estructura del pipeline y sus hijos
+func (d *Definition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("pipeline name is required")
+	}
+	if err := d.Root.validate("root"); err != nil {
+		return fmt.Errorf("pipeline %s: %w", d.Name, err)
+	}
+	for name, child := range d.Children {
+		if err := child.Validate(); err != nil {
+			return fmt.Errorf("child %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateProfiles comprueba que los perfiles de los pasos, también los de
+// los hijos, existan en registry; un perfil desconocido caería en "default"
+// sin aviso al ejecutar
+func (d *Definition) ValidateProfiles(registry *profiles.Registry) error {
+	var err error
+	d.Root.walk(func(s *Statement) {
+		if err != nil || s.Activity == nil || s.Activity.Profile == "" {
+			return
+		}
+		if !registry.Has(s.Activity.Profile) {
+			err = fmt.Errorf("pipeline %s: activity %s: unknown profile %q, available: %v",
+				d.Name, s.Activity.Name, s.Activity.Profile, registry.Names())
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for name, child := range d.Children {
+		if err := child.ValidateProfiles(registry); err != nil {
+			return fmt.Errorf("child %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateActivities comprueba que los pasos, también los de los hijos, solo
+// ejecuten activities de contracts.PipelineActivities; un pipeline no puede
+// invocar compensaciones ni el escalamiento aunque el worker los registre
+func (d *Definition) ValidateActivities() error {
+	var err error
+	d.Root.walk(func(s *Statement) {
+		if err != nil || s.Activity == nil || contracts.IsPipelineActivity(s.Activity.Name) {
+			return
+		}
+		err = fmt.Errorf("pipeline %s: activity %q is not allowed in pipelines, use one of %v",
+			d.Name, s.Activity.Name, contracts.PipelineActivities)
+	})
+	if err != nil {
+		return err
+	}
+	for name, child := range d.Children {
+		if err := child.ValidateActivities(); err != nil {
+			return fmt.Errorf("child %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ChildPipelines devuelve los nombres de los pipelines hijos referenciados
+func (d *Definition) ChildPipelines() []string {
+	var names []string
+	d.Root.walk(func(s *Statement) {
+		if s.Child != nil {
+			names = append(names, s.Child.Pipeline)
+		}
+	})
+	return names
+}
+
+func (s *Statement) validate(path string) error {
+	kinds := 0
+	if s.Activity != nil {
+		kinds++
+	}
+	if s.Sequence != nil {
+		kinds++
+	}
+	if s.Parallel != nil {
+		kinds++
+	}
+	if s.Child != nil {
+		kinds++
+	}
+	if kinds != 1 {
+		return fmt.Errorf("%s: statement must have exactly one of activity, sequence, parallel or child", path)
+	}
+
+	if s.When != nil {
+		if err := s.When.validate(); err != nil {
+			return fmt.Errorf("%s.when: %w", path, err)
+		}
+	}
+
+	switch {
+	case s.Activity != nil:
+		if s.Activity.Name == "" {
+			return fmt.Errorf("%s.activity: name is required", path)
+		}
+		if err := s.Activity.Options.Validate(); err != nil {
+			return fmt.Errorf("%s.activity.options: %w", path, err)
+		}
+	case s.Child != nil:
+		if s.Child.Pipeline == "" {
+			return fmt.Errorf("%s.child: pipeline is required", path)
+		}
+	case s.Sequence != nil:
+		for i := range s.Sequence {
+			if err := s.Sequence[i].validate(fmt.Sprintf("%s.sequence[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case s.Parallel != nil:
+		for i := range s.Parallel {
+			if err := s.Parallel[i].validate(fmt.Sprintf("%s.parallel[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Statement) walk(fn func(*Statement)) {
+	fn(s)
+	for i := range s.Sequence {
+		s.Sequence[i].walk(fn)
+	}
+	for i := range s.Parallel {
+		s.Parallel[i].walk(fn)
+	}
+}
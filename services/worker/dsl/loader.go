@@ -0,0 +1,144 @@
+package dsl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/worker/profiles"
+)
+
+// extensions son los formatos aceptados, en orden de búsqueda
+var extensions = []string{".yaml", ".yml", ".json"}
+
+// Loader lee definiciones de pipelines desde un directorio. Lee en cada
+// llamada, así un pipeline nuevo queda disponible sin redeploy del worker.
+type Loader struct {
+	Dir string
+}
+
+// NewLoader crea un loader sobre dir
+func NewLoader(dir string) *Loader {
+	return &Loader{Dir: dir}
+}
+
+// List devuelve los nombres de los pipelines disponibles
+func (l *Loader) List() ([]string, error) {
+	entries, err := os.ReadDir(l.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || !isSupported(ext) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ext))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Load lee el pipeline name, lo valida contra las activities permitidas y
+// los perfiles del worker y adjunta todos sus pipelines hijos
+func (l *Loader) Load(name string) (*Definition, error) {
+	root, err := l.read(name)
+	if err != nil {
+		return nil, err
+	}
+
+	children := map[string]*Definition{}
+	if err := l.resolveChildren(root, children, []string{name}); err != nil {
+		return nil, err
+	}
+	if len(children) > 0 {
+		root.Children = children
+	}
+
+	if err := root.Validate(); err != nil {
+		return nil, err
+	}
+	if err := root.ValidateActivities(); err != nil {
+		return nil, err
+	}
+	if err := root.ValidateProfiles(profiles.Current()); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// resolveChildren carga recursivamente los hijos de def en children,
+// detectando ciclos a través de stack
+func (l *Loader) resolveChildren(def *Definition, children map[string]*Definition, stack []string) error {
+	for _, childName := range def.ChildPipelines() {
+		for _, parent := range stack {
+			if parent == childName {
+				return fmt.Errorf("pipeline cycle detected: %s -> %s", strings.Join(stack, " -> "), childName)
+			}
+		}
+		if _, ok := children[childName]; ok {
+			continue
+		}
+
+		child, err := l.read(childName)
+		if err != nil {
+			return err
+		}
+		children[childName] = child
+		if err := l.resolveChildren(child, children, append(stack, childName)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// read busca name con cada extensión soportada y lo decodifica
+func (l *Loader) read(name string) (*Definition, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid pipeline name %q", name)
+	}
+
+	for _, ext := range extensions {
+		path := filepath.Join(l.Dir, name+ext)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		def, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if def.Name == "" {
+			def.Name = name
+		}
+		return def, nil
+	}
+	return nil, fmt.Errorf("pipeline %q not found in %s", name, l.Dir)
+}
+
+// Parse decodifica una definición en YAML o JSON
+func Parse(data []byte) (*Definition, error) {
+	var def Definition
+	if err := contracts.DecodeYAMLStrict(data, &def); err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+func isSupported(ext string) bool {
+	for _, e := range extensions {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package dsl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestShippedPipelines valida las definiciones que se empaquetan con el worker
+func TestShippedPipelines(t *testing.T) {
+	loader := NewLoader("../pipelines")
+
+	names, err := loader.List()
+	require.NoError(t, err)
+	require.Subset(t, names, []string{"workflow-a", "workflow-b", "workflow-c", "workflow-d"})
+
+	for _, name := range names {
+		def, err := loader.Load(name)
+		require.NoError(t, err, name)
+		require.Equal(t, name, def.Name)
+	}
+
+	def, err := loader.Load("workflow-a")
+	require.NoError(t, err)
+	require.Contains(t, def.Children, "workflow-b")
+}
+
+func TestLoaderRejectsInvalidDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	write("empty-step.yaml", "root:\n  sequence:\n    - {}\n")
+	write("cycle-a.json", `{"root":{"child":{"pipeline":"cycle-b"}}}`)
+	write("cycle-b.json", `{"root":{"child":{"pipeline":"cycle-a"}}}`)
+	write("bad-duration.yaml", "root:\n  activity:\n    name: Activity1\n    options:\n      startToCloseTimeout: soon\n")
+	write("unknown-field.yaml", "root:\n  activity:\n    nam: Activity1\n")
+	write("unknown-profile.yaml", "root:\n  activity:\n    name: Activity1\n    profile: turbo\n")
+	write("child-unknown-profile.json", `{"root":{"child":{"pipeline":"unknown-profile"}}}`)
+	write("compensation-step.yaml", "root:\n  activity:\n    name: CompensateActivity1\n")
+	write("child-escalate-step.json", `{"root":{"child":{"pipeline":"escalate-step"}}}`)
+	write("escalate-step.json", `{"root":{"activity":{"name":"Escalate"}}}`)
+
+	loader := NewLoader(dir)
+	for _, name := range []string{"empty-step", "cycle-a", "bad-duration", "unknown-field", "unknown-profile", "child-unknown-profile", "compensation-step", "child-escalate-step", "missing", "../etc"} {
+		_, err := loader.Load(name)
+		require.Error(t, err, name)
+	}
+}
+
+func TestLoaderRejectsActivitiesOutsideAllowList(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "escalate-step.yaml"), []byte("root:\n  activity:\n    name: Escalate\n"), 0o644))
+
+	_, err := NewLoader(dir).Load("escalate-step")
+	require.ErrorContains(t, err, `activity "Escalate" is not allowed in pipelines`)
+}
@@ -0,0 +1,79 @@
+package interceptors
+
+import (
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// currentStepChangeID versiona el upsert: las ejecuciones iniciadas antes de
+// este cambio no tienen los eventos de upsert en su historial
+const currentStepChangeID = "current-step-search-attribute"
+
+// CurrentStep es el interceptor que actualiza CurrentStep antes de cada
+// activity y child workflow
+type CurrentStep struct {
+	interceptor.WorkerInterceptorBase
+}
+
+var _ interceptor.WorkerInterceptor = (*CurrentStep)(nil)
+
+// NewCurrentStep crea el interceptor de CurrentStep
+func NewCurrentStep() *CurrentStep {
+	return &CurrentStep{}
+}
+
+func (c *CurrentStep) InterceptWorkflow(
+	ctx workflow.Context,
+	next interceptor.WorkflowInboundInterceptor,
+) interceptor.WorkflowInboundInterceptor {
+	i := &currentStepInbound{}
+	i.Next = next
+	return i
+}
+
+type currentStepInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+}
+
+func (c *currentStepInbound) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	i := &currentStepOutbound{}
+	i.Next = outbound
+	return c.Next.Init(i)
+}
+
+// currentStepOutbound guarda el último paso publicado de la ejecución para
+// no repetir el upsert (y el evento en el historial) si no cambió
+type currentStepOutbound struct {
+	interceptor.WorkflowOutboundInterceptorBase
+	versionChecked bool
+	enabled        bool
+	step           string
+}
+
+func (c *currentStepOutbound) ExecuteActivity(ctx workflow.Context, activityType string, args ...interface{}) workflow.Future {
+	c.upsert(ctx, activityType)
+	return c.Next.ExecuteActivity(ctx, activityType, args...)
+}
+
+func (c *currentStepOutbound) ExecuteChildWorkflow(ctx workflow.Context, childWorkflowType string, args ...interface{}) workflow.ChildWorkflowFuture {
+	c.upsert(ctx, childWorkflowType)
+	return c.Next.ExecuteChildWorkflow(ctx, childWorkflowType, args...)
+}
+
+func (c *currentStepOutbound) upsert(ctx workflow.Context, step string) {
+	if !c.versionChecked {
+		c.versionChecked = true
+		c.enabled = workflow.GetVersion(ctx, currentStepChangeID, workflow.DefaultVersion, 1) >= 1
+	}
+	if !c.enabled || step == c.step {
+		return
+	}
+
+	if err := workflow.UpsertSearchAttributes(ctx, map[string]interface{}{contracts.CurrentStepSearchAttribute: step}); err != nil {
+		workflow.GetLogger(ctx).Warn("Failed to upsert current step", "step", step, "error", err)
+		return
+	}
+	c.step = step
+}
@@ -0,0 +1,45 @@
+package interceptors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// stepsWorkflow repite una activity y termina con un child workflow
+func stepsWorkflow(ctx workflow.Context, input string) (string, error) {
+	for i := 0; i < 2; i++ {
+		if _, err := parentWorkflow(ctx, input); err != nil {
+			return "", err
+		}
+	}
+	return input, nil
+}
+
+func TestCurrentStepUpsertsOnlyWhenTheStepChanges(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{NewCurrentStep()},
+	})
+	env.RegisterWorkflow(childWorkflow)
+	env.RegisterActivity(echoActivity)
+
+	// GetVersion publica su propio search attribute
+	env.OnUpsertSearchAttributes(map[string]interface{}{
+		"TemporalChangeVersion": []string{currentStepChangeID + "-1"},
+	}).Return(nil).Once()
+	env.OnUpsertSearchAttributes(map[string]interface{}{contracts.CurrentStepSearchAttribute: "echoActivity"}).Return(nil).Twice()
+	env.OnUpsertSearchAttributes(map[string]interface{}{contracts.CurrentStepSearchAttribute: "childWorkflow"}).Return(nil).Twice()
+
+	env.ExecuteWorkflow(stepsWorkflow, "hello")
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
@@ -0,0 +1,219 @@
+// Package interceptors contiene los interceptors del worker. El de
+// observabilidad registra inicio, fin, duración, intento y error de cada
+// workflow, activity y child workflow, como logs y como métricas, para que
+// el código de negocio no tenga que repetirlo. El de CurrentStep publica el
+// paso en curso como search attribute.
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Chain devuelve los interceptors que registra el worker, en orden; el
+// replayer usa la misma cadena para verificar que sigan siendo deterministas
+func Chain() []interceptor.WorkerInterceptor {
+	return []interceptor.WorkerInterceptor{NewObservability(), NewCurrentStep()}
+}
+
+// Nombres de las métricas emitidas por el interceptor
+const (
+	WorkflowStartedMetric        = "worker_workflow_started"
+	WorkflowCompletedMetric      = "worker_workflow_completed"
+	WorkflowDurationMetric       = "worker_workflow_duration"
+	ActivityStartedMetric        = "worker_activity_started"
+	ActivityCompletedMetric      = "worker_activity_completed"
+	ActivityDurationMetric       = "worker_activity_duration"
+	ChildWorkflowStartedMetric   = "worker_child_workflow_started"
+	ChildWorkflowCompletedMetric = "worker_child_workflow_completed"
+	ChildWorkflowDurationMetric  = "worker_child_workflow_duration"
+)
+
+// Valores del tag "status" de las métricas *_completed y *_duration
+const (
+	StatusCompleted      = "completed"
+	StatusFailed         = "failed"
+	StatusCanceled       = "canceled"
+	StatusContinuedAsNew = "continued_as_new"
+)
+
+// Observability es el interceptor de logs y métricas del worker
+type Observability struct {
+	interceptor.WorkerInterceptorBase
+}
+
+var _ interceptor.WorkerInterceptor = (*Observability)(nil)
+
+// NewObservability crea el interceptor de observabilidad
+func NewObservability() *Observability {
+	return &Observability{}
+}
+
+func (o *Observability) InterceptActivity(
+	ctx context.Context,
+	next interceptor.ActivityInboundInterceptor,
+) interceptor.ActivityInboundInterceptor {
+	i := &activityInbound{}
+	i.Next = next
+	return i
+}
+
+func (o *Observability) InterceptWorkflow(
+	ctx workflow.Context,
+	next interceptor.WorkflowInboundInterceptor,
+) interceptor.WorkflowInboundInterceptor {
+	i := &workflowInbound{}
+	i.Next = next
+	return i
+}
+
+// activityInbound mide cada ejecución de activity. La duración es la del
+// intento actual, no la acumulada entre reintentos.
+type activityInbound struct {
+	interceptor.ActivityInboundInterceptorBase
+}
+
+func (a *activityInbound) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (interface{}, error) {
+	info := activity.GetInfo(ctx)
+	logger := activity.GetLogger(ctx)
+	metrics := activity.GetMetricsHandler(ctx).WithTags(map[string]string{"activity_type": info.ActivityType.Name})
+
+	// El logger de la activity ya incluye ActivityType, Attempt y el workflow
+	logger.Info("Activity started")
+	metrics.Counter(ActivityStartedMetric).Inc(1)
+
+	start := time.Now()
+	result, err := a.Next.ExecuteActivity(ctx, in)
+	duration := time.Since(start)
+
+	status, errorType := outcome(err)
+	tagged := metrics.WithTags(map[string]string{"status": status, "error_type": errorType})
+	tagged.Counter(ActivityCompletedMetric).Inc(1)
+	tagged.Timer(ActivityDurationMetric).Record(duration)
+	logEnd(logger, "Activity", duration, status, errorType, err)
+	return result, err
+}
+
+// workflowInbound mide cada run de workflow. La duración se calcula con el
+// reloj del workflow desde el inicio del run, así es la misma en el replay.
+type workflowInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+}
+
+func (w *workflowInbound) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	i := &workflowOutbound{}
+	i.Next = outbound
+	return w.Next.Init(i)
+}
+
+func (w *workflowInbound) ExecuteWorkflow(ctx workflow.Context, in *interceptor.ExecuteWorkflowInput) (interface{}, error) {
+	info := workflow.GetInfo(ctx)
+	logger := workflow.GetLogger(ctx)
+	metrics := workflow.GetMetricsHandler(ctx).WithTags(map[string]string{"workflow_type": info.WorkflowType.Name})
+
+	// El logger del workflow ya incluye WorkflowType, WorkflowID, RunID y Attempt
+	logger.Info("Workflow started")
+	metrics.Counter(WorkflowStartedMetric).Inc(1)
+
+	result, err := w.Next.ExecuteWorkflow(ctx, in)
+	duration := workflow.Now(ctx).Sub(info.WorkflowStartTime)
+
+	status, errorType := outcome(err)
+	tagged := metrics.WithTags(map[string]string{"status": status, "error_type": errorType})
+	tagged.Counter(WorkflowCompletedMetric).Inc(1)
+	tagged.Timer(WorkflowDurationMetric).Record(duration)
+	logEnd(logger, "Workflow", duration, status, errorType, err)
+	return result, err
+}
+
+// workflowOutbound mide los child workflows que inicia el workflow
+type workflowOutbound struct {
+	interceptor.WorkflowOutboundInterceptorBase
+}
+
+func (w *workflowOutbound) ExecuteChildWorkflow(
+	ctx workflow.Context,
+	childWorkflowType string,
+	args ...interface{},
+) workflow.ChildWorkflowFuture {
+	logger := log.With(workflow.GetLogger(ctx), "childWorkflowType", childWorkflowType)
+	metrics := workflow.GetMetricsHandler(ctx).WithTags(map[string]string{
+		"workflow_type":       workflow.GetInfo(ctx).WorkflowType.Name,
+		"child_workflow_type": childWorkflowType,
+	})
+
+	start := workflow.Now(ctx)
+	future := w.Next.ExecuteChildWorkflow(ctx, childWorkflowType, args...)
+	logger.Info("Child workflow started")
+	metrics.Counter(ChildWorkflowStartedMetric).Inc(1)
+
+	// El fin se observa en una goroutine del workflow que solo espera el
+	// future; no genera comandos, así no altera el historial
+	workflow.Go(ctx, func(gCtx workflow.Context) {
+		err := future.Get(gCtx, nil)
+		duration := workflow.Now(gCtx).Sub(start)
+
+		var execution workflow.Execution
+		_ = future.GetChildWorkflowExecution().Get(gCtx, &execution)
+
+		status, errorType := outcome(err)
+		tagged := metrics.WithTags(map[string]string{"status": status, "error_type": errorType})
+		tagged.Counter(ChildWorkflowCompletedMetric).Inc(1)
+		tagged.Timer(ChildWorkflowDurationMetric).Record(duration)
+		logEnd(log.With(logger, "childWorkflowId", execution.ID), "Child workflow", duration, status, errorType, err)
+	})
+	return future
+}
+
+// outcome clasifica el resultado de una ejecución. errorType es el tipo del
+// ApplicationError (p.ej. ValidationError) o el tipo de fallo de Temporal.
+func outcome(err error) (status, errorType string) {
+	if err == nil {
+		return StatusCompleted, ""
+	}
+
+	var continueAsNew *workflow.ContinueAsNewError
+	if errors.As(err, &continueAsNew) {
+		return StatusContinuedAsNew, ""
+	}
+	if temporal.IsCanceledError(err) {
+		return StatusCanceled, "Canceled"
+	}
+
+	var applicationErr *temporal.ApplicationError
+	if errors.As(err, &applicationErr) {
+		if applicationErr.Type() != "" {
+			return StatusFailed, applicationErr.Type()
+		}
+		return StatusFailed, "ApplicationError"
+	}
+	var timeoutErr *temporal.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return StatusFailed, "Timeout"
+	}
+	var terminatedErr *temporal.TerminatedError
+	if errors.As(err, &terminatedErr) {
+		return StatusFailed, "Terminated"
+	}
+	return StatusFailed, "Unknown"
+}
+
+// logEnd registra el fin de una ejecución con su duración y resultado
+func logEnd(logger log.Logger, kind string, duration time.Duration, status, errorType string, err error) {
+	keyvals := []interface{}{"duration", duration, "status", status}
+	switch status {
+	case StatusFailed:
+		logger.Error(kind+" failed", append(keyvals, "errorType", errorType, "error", err)...)
+	case StatusCanceled:
+		logger.Warn(kind+" canceled", append(keyvals, "error", err)...)
+	default:
+		logger.Info(kind+" completed", keyvals...)
+	}
+}
@@ -0,0 +1,115 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/worker/metrics"
+)
+
+func parentWorkflow(ctx workflow.Context, input string) (string, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+	var result string
+	if err := workflow.ExecuteActivity(ctx, echoActivity, input).Get(ctx, &result); err != nil {
+		return "", err
+	}
+	if err := workflow.ExecuteChildWorkflow(ctx, childWorkflow, result).Get(ctx, &result); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+func childWorkflow(ctx workflow.Context, input string) (string, error) {
+	if input == "" {
+		return "", temporal.NewNonRetryableApplicationError("empty input", "ValidationError", nil)
+	}
+	return input + "!", nil
+}
+
+func echoActivity(ctx context.Context, input string) (string, error) {
+	return input, nil
+}
+
+func newEnv(registry *metrics.Registry) *testsuite.TestWorkflowEnvironment {
+	var suite testsuite.WorkflowTestSuite
+	suite.SetMetricsHandler(registry.Handler())
+	env := suite.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{NewObservability()},
+	})
+	env.RegisterWorkflow(parentWorkflow)
+	env.RegisterWorkflow(childWorkflow)
+	env.RegisterActivity(echoActivity)
+	return env
+}
+
+func TestObservabilityRecordsWorkflowActivityAndChild(t *testing.T) {
+	registry := metrics.NewRegistry()
+	env := newEnv(registry)
+
+	env.ExecuteWorkflow(parentWorkflow, "hello")
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	completed := map[string]string{"status": StatusCompleted, "error_type": ""}
+	require.EqualValues(t, 1, registry.CounterValue(WorkflowStartedMetric, map[string]string{"workflow_type": "parentWorkflow"}))
+	require.EqualValues(t, 1, registry.CounterValue(WorkflowCompletedMetric, withType("workflow_type", "parentWorkflow", completed)))
+	require.EqualValues(t, 1, registry.CounterValue(ActivityCompletedMetric, withType("activity_type", "echoActivity", completed)))
+	require.EqualValues(t, 1, registry.CounterValue(ChildWorkflowStartedMetric, map[string]string{"workflow_type": "parentWorkflow", "child_workflow_type": "childWorkflow"}))
+	require.EqualValues(t, 1, registry.CounterValue(ChildWorkflowCompletedMetric,
+		withType("child_workflow_type", "childWorkflow", withType("workflow_type", "parentWorkflow", completed))))
+
+	var out strings.Builder
+	registry.Write(&out)
+	require.Contains(t, out.String(), "# TYPE "+WorkflowDurationMetric+"_seconds histogram")
+	require.Contains(t, out.String(), "# TYPE "+ActivityDurationMetric+"_seconds histogram")
+}
+
+func TestObservabilityTagsErrorType(t *testing.T) {
+	registry := metrics.NewRegistry()
+	env := newEnv(registry)
+
+	env.ExecuteWorkflow(childWorkflow, "")
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+
+	require.EqualValues(t, 1, registry.CounterValue(WorkflowCompletedMetric, map[string]string{
+		"workflow_type": "childWorkflow",
+		"status":        StatusFailed,
+		"error_type":    "ValidationError",
+	}))
+}
+
+func TestOutcome(t *testing.T) {
+	status, errorType := outcome(nil)
+	require.Equal(t, StatusCompleted, status)
+	require.Empty(t, errorType)
+
+	status, errorType = outcome(temporal.NewCanceledError())
+	require.Equal(t, StatusCanceled, status)
+	require.Equal(t, "Canceled", errorType)
+
+	status, errorType = outcome(temporal.NewApplicationError("boom", ""))
+	require.Equal(t, StatusFailed, status)
+	require.Equal(t, "ApplicationError", errorType)
+}
+
+func withType(key, value string, tags map[string]string) map[string]string {
+	merged := map[string]string{key: value}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,38 @@
+package lifecycle
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/interceptor"
+)
+
+// inFlightInterceptor lleva la cuenta de activities en curso del proceso
+type inFlightInterceptor struct {
+	interceptor.WorkerInterceptorBase
+	manager *Manager
+}
+
+func (i *inFlightInterceptor) InterceptActivity(
+	ctx context.Context,
+	next interceptor.ActivityInboundInterceptor,
+) interceptor.ActivityInboundInterceptor {
+	a := &inFlightActivity{manager: i.manager}
+	a.Next = next
+	return a
+}
+
+type inFlightActivity struct {
+	interceptor.ActivityInboundInterceptorBase
+	manager *Manager
+}
+
+func (a *inFlightActivity) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (interface{}, error) {
+	a.manager.inFlight.Add(1)
+	defer a.manager.inFlight.Add(-1)
+
+	result, err := a.Next.ExecuteActivity(ctx, in)
+	if a.manager.draining.Load() && ctx.Err() != nil {
+		a.manager.canceled.Add(1)
+	}
+	return result, err
+}
@@ -0,0 +1,232 @@
+// Package lifecycle arranca y detiene los workers del proceso por un único
+// camino. Ante SIGTERM (o SIGINT) marca el proceso como no listo, detiene el
+// polling y deja que las activities en curso terminen dentro del
+// WorkerStopTimeout de cada worker; al vencer, el SDK cancela el contexto de
+// las que sigan corriendo. El código de salida indica cómo terminó.
+package lifecycle
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/worker"
+)
+
+// Códigos de salida del proceso
+const (
+	// ExitOK: detenido por señal y todas las activities terminaron
+	ExitOK = 0
+	// ExitStartFailed: un worker no pudo arrancar
+	ExitStartFailed = 1
+	// ExitWorkerFailed: un worker se detuvo por un error fatal
+	ExitWorkerFailed = 2
+	// ExitDrainTimeout: hubo activities canceladas al vencer WorkerStopTimeout
+	ExitDrainTimeout = 3
+	// ExitForced: una segunda señal interrumpió el drenado
+	ExitForced = 4
+)
+
+// DefaultCancelGrace es cuánto se espera, tras cancelar las activities
+// pendientes, a que devuelvan el control antes de salir
+const DefaultCancelGrace = 5 * time.Second
+
+// Manager controla el ciclo de vida de los workers del proceso
+type Manager struct {
+	// CancelGrace es la espera tras la cancelación; cero usa DefaultCancelGrace
+	CancelGrace time.Duration
+
+	workers  []namedWorker
+	ready    atomic.Bool
+	draining atomic.Bool
+	inFlight atomic.Int64
+	// canceled cuenta las activities que terminaron con el contexto
+	// cancelado durante el drenado, es decir, tras vencer WorkerStopTimeout
+	canceled atomic.Int64
+	fatal    chan error
+
+	mu       sync.Mutex
+	fatalErr error
+}
+
+type namedWorker struct {
+	taskQueue string
+	worker    worker.Worker
+}
+
+// NewManager crea un manager sin workers
+func NewManager() *Manager {
+	return &Manager{fatal: make(chan error, 1)}
+}
+
+// Add agrega el worker de una task queue; se arranca en Run
+func (m *Manager) Add(taskQueue string, w worker.Worker) {
+	m.workers = append(m.workers, namedWorker{taskQueue: taskQueue, worker: w})
+}
+
+// Len devuelve cuántos workers administra
+func (m *Manager) Len() int {
+	return len(m.workers)
+}
+
+// OnFatalError es el callback de worker.Options.OnFatalError: un worker que
+// se detuvo por su cuenta inicia el apagado de todo el proceso
+func (m *Manager) OnFatalError(taskQueue string) func(error) {
+	return func(err error) {
+		log.Printf("Worker for task queue %s stopped with a fatal error: %v", taskQueue, err)
+		m.mu.Lock()
+		if m.fatalErr == nil {
+			m.fatalErr = err
+		}
+		m.mu.Unlock()
+		select {
+		case m.fatal <- err:
+		default:
+		}
+	}
+}
+
+// Interceptor cuenta las activities en curso, para saber si el drenado
+// terminó a tiempo
+func (m *Manager) Interceptor() interceptor.WorkerInterceptor {
+	return &inFlightInterceptor{manager: m}
+}
+
+// InFlight devuelve cuántas activities están en curso
+func (m *Manager) InFlight() int64 {
+	return m.inFlight.Load()
+}
+
+// Ready indica si el proceso está procesando tareas
+func (m *Manager) Ready() bool {
+	return m.ready.Load()
+}
+
+// Run arranca los workers y bloquea hasta una señal o un error fatal; luego
+// drena y devuelve el código de salida. Una segunda señal durante el
+// drenado corta la espera.
+func (m *Manager) Run(signals <-chan os.Signal) int {
+	for i, w := range m.workers {
+		if err := w.worker.Start(); err != nil {
+			log.Printf("Worker for task queue %s failed to start: %v", w.taskQueue, err)
+			for _, started := range m.workers[:i] {
+				started.worker.Stop()
+			}
+			return ExitStartFailed
+		}
+	}
+	m.ready.Store(true)
+	log.Printf("Worker started and listening for tasks on %d task queue(s)...", len(m.workers))
+
+	code := ExitOK
+	select {
+	case sig := <-signals:
+		log.Printf("Received %s, shutting down worker gracefully...", sig)
+	case <-m.fatal:
+		log.Println("Shutting down worker after a fatal error...")
+		code = ExitWorkerFailed
+	}
+
+	drained := make(chan int, 1)
+	go func() { drained <- m.drain() }()
+
+	select {
+	case drainCode := <-drained:
+		if code == ExitOK {
+			code = drainCode
+		}
+	case sig := <-signals:
+		log.Printf("Received %s while draining, exiting without waiting for %d activities", sig, m.InFlight())
+		code = ExitForced
+	}
+	log.Printf("Worker stopped with exit code %d", code)
+	return code
+}
+
+// drain detiene el polling de todos los workers a la vez y espera a que
+// terminen las activities en curso
+func (m *Manager) drain() int {
+	m.ready.Store(false)
+	m.draining.Store(true)
+	log.Printf("Draining %d in-flight activities", m.InFlight())
+
+	// Stop bloquea hasta que terminan las activities o vence WorkerStopTimeout
+	// del worker; en ese caso cancela el contexto de las que siguen
+	var wg sync.WaitGroup
+	for _, w := range m.workers {
+		wg.Add(1)
+		go func(w namedWorker) {
+			defer wg.Done()
+			w.worker.Stop()
+		}(w)
+	}
+	wg.Wait()
+
+	// Tras la cancelación se da un margen para que las activities devuelvan
+	// el control; así el conteo de canceladas es completo
+	grace := m.CancelGrace
+	if grace == 0 {
+		grace = DefaultCancelGrace
+	}
+	deadline := time.Now().Add(grace)
+	for m.InFlight() > 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if running := m.InFlight(); running > 0 {
+		log.Printf("Stop timeout reached: %d activities still running after cancellation", running)
+		return ExitDrainTimeout
+	}
+	if canceled := m.canceled.Load(); canceled > 0 {
+		log.Printf("Stop timeout reached: %d activities were canceled", canceled)
+		return ExitDrainTimeout
+	}
+	log.Println("All activities finished before the stop timeout")
+	return ExitOK
+}
+
+// HealthHandler responde 200 mientras ningún worker haya fallado
+// (liveness); ReadyHandler responde 200 solo mientras se procesan tareas
+// (readiness), así el orquestador deja de enviar tráfico al drenar.
+func (m *Manager) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := http.StatusOK
+		body := map[string]interface{}{"status": "healthy"}
+		m.mu.Lock()
+		err := m.fatalErr
+		m.mu.Unlock()
+		if err != nil {
+			status = http.StatusServiceUnavailable
+			body = map[string]interface{}{"status": "failed", "error": err.Error()}
+		}
+		writeJSON(w, status, body)
+	})
+}
+
+// ReadyHandler ver HealthHandler
+func (m *Manager) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]interface{}{"ready": m.Ready(), "inFlightActivities": m.InFlight()}
+		switch {
+		case m.Ready():
+			writeJSON(w, http.StatusOK, body)
+		case m.draining.Load():
+			body["status"] = "draining"
+			writeJSON(w, http.StatusServiceUnavailable, body)
+		default:
+			body["status"] = "starting"
+			writeJSON(w, http.StatusServiceUnavailable, body)
+		}
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,151 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+)
+
+// fakeWorker implementa solo Start y Stop; el resto de worker.Worker no se usa
+type fakeWorker struct {
+	worker.Worker
+	startErr error
+	started  atomic.Bool
+	stopped  atomic.Bool
+	// stop, si no es nil, se ejecuta dentro de Stop (p.ej. para bloquearlo)
+	stop func()
+}
+
+func (w *fakeWorker) Start() error {
+	if w.startErr != nil {
+		return w.startErr
+	}
+	w.started.Store(true)
+	return nil
+}
+
+func (w *fakeWorker) Stop() {
+	if w.stop != nil {
+		w.stop()
+	}
+	w.stopped.Store(true)
+}
+
+func status(t *testing.T, h http.Handler) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec.Code
+}
+
+// runAsync ejecuta Run y espera a que el manager quede listo
+func runAsync(t *testing.T, m *Manager, signals chan os.Signal) <-chan int {
+	t.Helper()
+	done := make(chan int, 1)
+	go func() { done <- m.Run(signals) }()
+	require.Eventually(t, m.Ready, time.Second, 5*time.Millisecond)
+	return done
+}
+
+func TestRunStopsAllWorkersOnSignal(t *testing.T) {
+	m := NewManager()
+	a, b := &fakeWorker{}, &fakeWorker{}
+	m.Add("a", a)
+	m.Add("b", b)
+	assert.Equal(t, http.StatusServiceUnavailable, status(t, m.ReadyHandler()))
+
+	signals := make(chan os.Signal, 2)
+	done := runAsync(t, m, signals)
+	assert.Equal(t, http.StatusOK, status(t, m.ReadyHandler()))
+
+	signals <- syscall.SIGTERM
+	assert.Equal(t, ExitOK, <-done)
+	assert.True(t, a.stopped.Load())
+	assert.True(t, b.stopped.Load())
+	assert.False(t, m.Ready())
+	assert.Equal(t, http.StatusServiceUnavailable, status(t, m.ReadyHandler()))
+	assert.Equal(t, http.StatusOK, status(t, m.HealthHandler()))
+}
+
+func TestRunStopsStartedWorkersWhenOneFailsToStart(t *testing.T) {
+	m := NewManager()
+	started, failing := &fakeWorker{}, &fakeWorker{startErr: errors.New("no such namespace")}
+	m.Add("a", started)
+	m.Add("b", failing)
+
+	assert.Equal(t, ExitStartFailed, m.Run(make(chan os.Signal)))
+	assert.True(t, started.stopped.Load())
+	assert.False(t, m.Ready())
+}
+
+func TestRunExitsOnFatalWorkerError(t *testing.T) {
+	m := NewManager()
+	m.Add("a", &fakeWorker{})
+	done := runAsync(t, m, make(chan os.Signal))
+
+	m.OnFatalError("a")(errors.New("namespace deleted"))
+	assert.Equal(t, ExitWorkerFailed, <-done)
+	assert.Equal(t, http.StatusServiceUnavailable, status(t, m.HealthHandler()))
+}
+
+func TestRunReportsActivitiesStillRunningAfterStop(t *testing.T) {
+	m := NewManager()
+	m.CancelGrace = 20 * time.Millisecond
+	m.Add("a", &fakeWorker{})
+	signals := make(chan os.Signal, 2)
+	done := runAsync(t, m, signals)
+
+	// Una activity que ignora la cancelación sigue en curso tras Stop
+	m.inFlight.Add(1)
+	signals <- syscall.SIGTERM
+	assert.Equal(t, ExitDrainTimeout, <-done)
+}
+
+func TestSecondSignalForcesExit(t *testing.T) {
+	m := NewManager()
+	release := make(chan struct{})
+	defer close(release)
+	m.Add("a", &fakeWorker{stop: func() { <-release }})
+	signals := make(chan os.Signal, 2)
+	done := runAsync(t, m, signals)
+
+	signals <- syscall.SIGTERM
+	require.Eventually(t, func() bool {
+		return status(t, m.ReadyHandler()) == http.StatusServiceUnavailable
+	}, time.Second, 5*time.Millisecond)
+	signals <- syscall.SIGINT
+	assert.Equal(t, ExitForced, <-done)
+}
+
+func TestInterceptorTracksInFlightActivities(t *testing.T) {
+	m := NewManager()
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestActivityEnvironment()
+	env.SetWorkerOptions(worker.Options{Interceptors: []interceptor.WorkerInterceptor{m.Interceptor()}})
+
+	var running int64
+	env.RegisterActivityWithOptions(func(ctx context.Context) error {
+		running = m.InFlight()
+		m.draining.Store(true)
+		return ctx.Err()
+	}, activity.RegisterOptions{Name: "Probe"})
+	_, err := env.ExecuteActivity("Probe")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), running)
+	assert.Equal(t, int64(0), m.InFlight())
+	// Terminó durante el drenado pero sin cancelación: no cuenta como cancelada
+	assert.Equal(t, int64(0), m.canceled.Load())
+}
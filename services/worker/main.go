@@ -1,15 +1,31 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
+	"time"
 
+	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
 
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/contracts/temporalconn"
 	"github.com/temporal-aws-poc/worker/activities"
+	"github.com/temporal-aws-poc/worker/connection"
+	"github.com/temporal-aws-poc/worker/dsl"
+	"github.com/temporal-aws-poc/worker/interceptors"
+	"github.com/temporal-aws-poc/worker/lifecycle"
+	"github.com/temporal-aws-poc/worker/metrics"
+	"github.com/temporal-aws-poc/worker/profiles"
+	"github.com/temporal-aws-poc/worker/settings"
 	"github.com/temporal-aws-poc/worker/workflows"
 )
 
@@ -22,61 +38,259 @@ func main() {
 		temporalHostPort = "localhost:7233"
 	}
 
-	taskQueue := os.Getenv("TASK_QUEUE")
-	if taskQueue == "" {
-		taskQueue = "hello-world-queue"
+	// Un proceso atiende un solo namespace: cada namespace en el que el API
+	// inicia workflows (p.ej. staging y production) necesita su propio
+	// despliegue del worker con su TEMPORAL_NAMESPACE
+	namespace := os.Getenv("TEMPORAL_NAMESPACE")
+	if namespace == "" {
+		namespace = client.DefaultNamespace
 	}
 
-	log.Printf("Connecting to Temporal at: %s", temporalHostPort)
-	log.Printf("Task Queue: %s", taskQueue)
+	// Task queues y worker.Options; sin archivo, una sola task queue (TASK_QUEUE)
+	// con todos los workflows y activities
+	workerConfig := settings.Default(os.Getenv("TASK_QUEUE"))
+	if configFile := os.Getenv("WORKER_CONFIG_FILE"); configFile != "" {
+		loaded, err := settings.LoadFile(configFile)
+		if err != nil {
+			log.Fatalf("Unable to load worker configuration from %s: %v", configFile, err)
+		}
+		workerConfig = loaded
+		log.Printf("Worker configuration loaded from: %s", configFile)
+	}
+	if err := workerConfig.ApplyEnv(os.Getenv); err != nil {
+		log.Fatalf("Invalid worker configuration: %v", err)
+	}
 
-	// Crear cliente Temporal
-	c, err := client.Dial(client.Options{
-		HostPort: temporalHostPort,
-	})
+	// Qué registra este proceso de lo declarado: workflows, activities o ambos
+	roles, err := settings.ParseRoles(os.Getenv("WORKER_ROLES"))
 	if err != nil {
-		log.Fatalf("Unable to create Temporal client: %v", err)
+		log.Fatalf("Invalid WORKER_ROLES: %v", err)
 	}
-	defer c.Close()
-	log.Println("Successfully connected to Temporal server")
+	log.Printf("Worker roles: %s", roles)
+
+	pipelinesDir := os.Getenv("PIPELINES_DIR")
+	if pipelinesDir == "" {
+		pipelinesDir = "pipelines"
+	}
+
+	// Perfiles de ActivityOptions; sin archivo se usan los incluidos
+	if profilesFile := os.Getenv("ACTIVITY_PROFILES_FILE"); profilesFile != "" {
+		registry, err := profiles.LoadFile(profilesFile)
+		if err != nil {
+			log.Fatalf("Unable to load activity profiles from %s: %v", profilesFile, err)
+		}
+		profiles.SetCurrent(registry)
+		log.Printf("Activity profiles loaded from: %s", profilesFile)
+	}
+	log.Printf("Activity profiles: %v", profiles.Current().Names())
 
-	// Crear worker
-	w := worker.New(c, taskQueue, worker.Options{
-		MaxConcurrentActivityExecutionSize:     5,
-		MaxConcurrentWorkflowTaskExecutionSize: 5,
-	})
+	batchItemsDir := os.Getenv("BATCH_ITEMS_DIR")
+	if batchItemsDir == "" {
+		batchItemsDir = "batches"
+	}
 
-	// Registrar workflows
-	w.RegisterWorkflow(workflows.WorkflowA)
-	w.RegisterWorkflow(workflows.WorkflowB)
-	w.RegisterWorkflow(workflows.WorkflowC)
-	w.RegisterWorkflow(workflows.WorkflowD)
-	log.Println("Registered workflows: WorkflowA, WorkflowB, WorkflowC, WorkflowD")
+	metricsPort := os.Getenv("METRICS_PORT")
+	if metricsPort == "" {
+		metricsPort = "9090"
+	}
 
-	// Crear instancia de activities y registrarlas
-	act := activities.NewActivities()
-	w.RegisterActivity(act.Activity1)
-	w.RegisterActivity(act.Activity2)
-	w.RegisterActivity(act.Activity3)
-	w.RegisterActivity(act.Activity4)
-	log.Println("Registered activities: Activity1, Activity2, Activity3, Activity4")
+	log.Printf("Connecting to Temporal at: %s (namespace %s)", temporalHostPort, namespace)
+	log.Printf("Pipelines directory: %s", pipelinesDir)
+	log.Printf("Batch items directory: %s", batchItemsDir)
 
-	// Canal para capturar señales de shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	// Único camino de apagado: las señales solo las atiende el lifecycle
+	// manager, que drena los workers y decide el código de salida
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	manager := lifecycle.NewManager()
 
-	// Iniciar worker en goroutine
+	// Métricas del SDK y de los interceptors en /metrics; liveness y
+	// readiness del proceso en /healthz y /readyz
+	metricsRegistry := metrics.NewRegistry()
 	go func() {
-		log.Println("Worker started and listening for tasks...")
-		err = w.Run(worker.InterruptCh())
-		if err != nil {
-			log.Fatalf("Worker failed: %v", err)
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metricsRegistry)
+		mux.Handle("/healthz", manager.HealthHandler())
+		mux.Handle("/readyz", manager.ReadyHandler())
+		log.Printf("Metrics and health checks listening on port %s", metricsPort)
+		if err := http.ListenAndServe(":"+metricsPort, mux); err != nil && err != http.ErrServerClosed {
+			log.Printf("Metrics server failed: %v", err)
 		}
 	}()
 
-	// Esperar señal de shutdown
-	<-sigChan
-	log.Println("Shutting down worker gracefully...")
-	w.Stop()
-	log.Println("Worker stopped successfully")
+	// Crear cliente Temporal; mientras el frontend arranca se reintenta con
+	// backoff, y una señal corta la espera
+	dialBackoff, err := temporalconn.BackoffFromEnv(temporalconn.DefaultBackoff, os.Getenv)
+	if err != nil {
+		log.Fatalf("Invalid dial configuration: %v", err)
+	}
+	security, err := temporalconn.SecurityFromEnv(os.Getenv)
+	if err != nil {
+		log.Fatalf("Invalid TLS configuration: %v", err)
+	}
+	clientOptions := client.Options{
+		HostPort:       temporalHostPort,
+		Namespace:      namespace,
+		MetricsHandler: metricsRegistry.Handler(),
+		DataConverter:  contracts.DataConverter(),
+	}
+	if err := security.Apply(&clientOptions); err != nil {
+		log.Fatalf("Invalid TLS configuration: %v", err)
+	}
+	log.Printf("Temporal connection security: %s", security)
+
+	dialCtx, dialStop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	c, err := connection.Dial(dialCtx, clientOptions, dialBackoff)
+	dialStop()
+	if errors.Is(err, context.Canceled) {
+		log.Println("Shutdown requested before connecting to Temporal")
+		os.Exit(lifecycle.ExitOK)
+	}
+	if err != nil {
+		log.Printf("Unable to create Temporal client: %v", err)
+		os.Exit(lifecycle.ExitStartFailed)
+	}
+	log.Println("Successfully connected to Temporal server")
+	go connection.Monitor(context.Background(), c, 15*time.Second, metricsRegistry.Handler(), nil)
+
+	// CurrentStep (interceptor) y ApprovalStatus (WorkflowA) deben existir en
+	// el namespace antes de que los workflows los actualicen; sin ellos el
+	// servidor rechaza el upsert y las workflow tasks fallan
+	registerCtx, registerCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	registered, err := temporalconn.RegisterSearchAttributes(registerCtx, c.OperatorService(), namespace,
+		[]string{contracts.CurrentStepSearchAttribute, contracts.ApprovalStatusSearchAttribute})
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	} else if len(registered) > 0 {
+		log.Printf("Registered search attributes in namespace %s: %v", namespace, registered)
+	}
+	registerCancel()
+
+	// Workflows y activities que este worker sabe registrar, por nombre
+	workflowRegistrations := []registration{
+		{contracts.WorkflowA, workflows.WorkflowA},
+		{contracts.WorkflowB, workflows.WorkflowB},
+		{contracts.WorkflowC, workflows.WorkflowC},
+		{contracts.WorkflowD, workflows.WorkflowD},
+		{contracts.DSLWorkflow, workflows.DSLWorkflow},
+		{contracts.BatchWorkflow, workflows.BatchWorkflow},
+	}
+
+	act := activities.NewActivities()
+	if webhook := os.Getenv("ESCALATION_WEBHOOK_URL"); webhook != "" {
+		act.EscalationWebhook = webhook
+		log.Printf("Escalations sent to webhook: %s", webhook)
+	}
+
+	// Activities de pipelines declarativos (DSLWorkflow)
+	loader := dsl.NewLoader(pipelinesDir)
+	if names, err := loader.List(); err != nil {
+		log.Printf("Warning: unable to list pipelines in %s: %v", pipelinesDir, err)
+	} else {
+		log.Printf("Available pipelines: %v", names)
+	}
+
+	// Activities de listas de items (BatchWorkflow)
+	batchActivities := activities.NewBatchActivities(batchItemsDir)
+
+	activityRegistrations := []registration{
+		{contracts.Activity1, act.Activity1},
+		{contracts.Activity2, act.Activity2},
+		{contracts.Activity3, act.Activity3},
+		{contracts.Activity4, act.Activity4},
+		{contracts.CompensateActivity1, act.CompensateActivity1},
+		{contracts.CompensateActivity2, act.CompensateActivity2},
+		{contracts.CompensateActivity4, act.CompensateActivity4},
+		{contracts.Escalate, act.Escalate},
+		{contracts.ProcessRecords, act.ProcessRecords},
+		{contracts.LoadPipeline, activities.NewPipelineActivities(loader).LoadPipeline},
+		{contracts.LoadBatchItems, batchActivities.LoadBatchItems},
+		{contracts.StoreBatchItems, batchActivities.StoreBatchItems},
+	}
+
+	if err := workerConfig.Validate(names(workflowRegistrations), names(activityRegistrations)); err != nil {
+		log.Fatalf("Invalid worker configuration: %v", err)
+	}
+	if err := roles.Validate(workerConfig, names(activityRegistrations)); err != nil {
+		log.Fatalf("Invalid WORKER_ROLES: %v", err)
+	}
+
+	// Los workflows envían cada activity a la task queue que la registra,
+	// aunque este proceso no la atienda
+	activityTaskQueues := workerConfig.ActivityTaskQueues(names(activityRegistrations))
+	profiles.Current().SetTaskQueues(activityTaskQueues)
+	log.Printf("Activity task queues: %v", activityTaskQueues)
+	if workerConfig.StickyWorkflowCacheSize > 0 {
+		worker.SetStickyWorkflowCacheSize(workerConfig.StickyWorkflowCacheSize)
+		log.Printf("Sticky workflow cache size: %d", workerConfig.StickyWorkflowCacheSize)
+	}
+
+	// Un worker por task queue con lo declarado en ella que corresponde a los
+	// roles del proceso; las task queues sin nada que registrar no se atienden
+	for _, queue := range workerConfig.TaskQueues {
+		var registeredWorkflows, registeredActivities []string
+		if roles.Workflows {
+			registeredWorkflows = settings.Selected(queue.Workflows, names(workflowRegistrations))
+		}
+		for _, name := range workerConfig.QueueActivities(queue, names(activityRegistrations)) {
+			if roles.Activity(name) {
+				registeredActivities = append(registeredActivities, name)
+			}
+		}
+		if len(registeredWorkflows) == 0 && len(registeredActivities) == 0 {
+			log.Printf("Task queue %s: nothing to register for roles %s, skipping", queue.Name, roles)
+			continue
+		}
+
+		options := workerConfig.Options(queue).Apply(worker.Options{
+			// Logs y métricas de inicio/fin de cada workflow, activity y child
+			// workflow, y el search attribute CurrentStep; el lifecycle manager
+			// cuenta las activities en curso para el drenado
+			Interceptors: append(interceptors.Chain(), manager.Interceptor()),
+			OnFatalError: manager.OnFatalError(queue.Name),
+			// El SDK hace polling aunque no haya nada registrado: un proceso
+			// sin workflows (o sin activities) no debe tomar esas tareas
+			DisableWorkflowWorker:   len(registeredWorkflows) == 0,
+			LocalActivityWorkerOnly: len(registeredActivities) == 0,
+		})
+		w := worker.New(c, queue.Name, options)
+
+		for _, r := range workflowRegistrations {
+			if slices.Contains(registeredWorkflows, r.name) {
+				w.RegisterWorkflowWithOptions(r.fn, workflow.RegisterOptions{Name: r.name})
+			}
+		}
+		for _, r := range activityRegistrations {
+			if slices.Contains(registeredActivities, r.name) {
+				w.RegisterActivityWithOptions(r.fn, activity.RegisterOptions{Name: r.name})
+			}
+		}
+
+		log.Printf("Task queue %s: workflows %v", queue.Name, registeredWorkflows)
+		log.Printf("Task queue %s: activities %v", queue.Name, registeredActivities)
+		log.Printf("Task queue %s: options %+v", queue.Name, workerConfig.Options(queue))
+		manager.Add(queue.Name, w)
+	}
+	if manager.Len() == 0 {
+		log.Fatalf("Nothing to run: no task queue declares workflows or activities for roles %s", roles)
+	}
+
+	// Arranca los workers y bloquea hasta SIGTERM/SIGINT o un error fatal
+	code := manager.Run(signals)
+	c.Close()
+	os.Exit(code)
+}
+
+// registration es un workflow o activity que el worker sabe registrar
+type registration struct {
+	name string
+	fn   interface{}
+}
+
+func names(registrations []registration) []string {
+	result := make([]string, len(registrations))
+	for i, r := range registrations {
+		result[i] = r.name
+	}
+	return result
 }
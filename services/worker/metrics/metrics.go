@@ -0,0 +1,266 @@
+// Package metrics implementa un client.MetricsHandler en memoria que se
+// expone en formato de texto de Prometheus. Recibe tanto las métricas del
+// SDK de Temporal como las de los interceptors del worker.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.temporal.io/sdk/client"
+)
+
+// DefaultBuckets son los límites en segundos de los histogramas de los timers
+var DefaultBuckets = []float64{0.005, 0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 300, 1800}
+
+// Registry guarda los valores de todas las series
+type Registry struct {
+	mu       sync.Mutex
+	counters map[seriesKey]int64
+	gauges   map[seriesKey]float64
+	timers   map[seriesKey]*histogram
+}
+
+type seriesKey struct {
+	name string
+	tags string
+}
+
+type histogram struct {
+	buckets []int64
+	count   int64
+	sum     float64
+}
+
+// NewRegistry crea un registry vacío
+func NewRegistry() *Registry {
+	return &Registry{
+		counters: map[seriesKey]int64{},
+		gauges:   map[seriesKey]float64{},
+		timers:   map[seriesKey]*histogram{},
+	}
+}
+
+// Handler devuelve el client.MetricsHandler raíz, sin tags
+func (r *Registry) Handler() client.MetricsHandler {
+	return &handler{registry: r, tags: map[string]string{}}
+}
+
+// CounterValue suma los counters con ese nombre cuyos tags incluyen los
+// indicados; útil en tests, donde el SDK agrega sus propios tags
+func (r *Registry) CounterValue(name string, tags map[string]string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	labels := strings.Split(formatTags(tags), ",")
+	var total int64
+	for key, value := range r.counters {
+		if key.name == sanitize(name) && hasLabels(key.tags, labels) {
+			total += value
+		}
+	}
+	return total
+}
+
+// ServeHTTP escribe todas las series en formato de texto de Prometheus
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	r.Write(w)
+}
+
+// Write escribe todas las series en formato de texto de Prometheus.
+// Cada familia lleva una sola línea TYPE, antes de su primera serie.
+func (r *Registry) Write(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := ""
+	for _, key := range sortedKeys(r.counters) {
+		if key.name != previous {
+			writeType(w, key.name, "counter")
+			previous = key.name
+		}
+		fmt.Fprintf(w, "%s%s %d\n", key.name, braces(key.tags), r.counters[key])
+	}
+	for _, key := range sortedKeys(r.gauges) {
+		if key.name != previous {
+			writeType(w, key.name, "gauge")
+			previous = key.name
+		}
+		fmt.Fprintf(w, "%s%s %g\n", key.name, braces(key.tags), r.gauges[key])
+	}
+	for _, key := range sortedKeys(r.timers) {
+		name := key.name + "_seconds"
+		if name != previous {
+			writeType(w, name, "histogram")
+			previous = name
+		}
+		h := r.timers[key]
+		var cumulative int64
+		for i, le := range DefaultBuckets {
+			cumulative += h.buckets[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", name, braces(joinTags(key.tags, fmt.Sprintf("le=%q", fmt.Sprint(le)))), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, braces(joinTags(key.tags, `le="+Inf"`)), h.count)
+		fmt.Fprintf(w, "%s_sum%s %g\n", name, braces(key.tags), h.sum)
+		fmt.Fprintf(w, "%s_count%s %d\n", name, braces(key.tags), h.count)
+	}
+}
+
+// handler es un client.MetricsHandler con un set fijo de tags
+type handler struct {
+	registry *Registry
+	tags     map[string]string
+}
+
+func (h *handler) WithTags(tags map[string]string) client.MetricsHandler {
+	merged := make(map[string]string, len(h.tags)+len(tags))
+	for k, v := range h.tags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return &handler{registry: h.registry, tags: merged}
+}
+
+func (h *handler) Counter(name string) client.MetricsCounter {
+	return &counter{registry: h.registry, key: h.key(name)}
+}
+
+func (h *handler) Gauge(name string) client.MetricsGauge {
+	return &gauge{registry: h.registry, key: h.key(name)}
+}
+
+func (h *handler) Timer(name string) client.MetricsTimer {
+	return &timer{registry: h.registry, key: h.key(name)}
+}
+
+func (h *handler) key(name string) seriesKey {
+	return seriesKey{name: sanitize(name), tags: formatTags(h.tags)}
+}
+
+type counter struct {
+	registry *Registry
+	key      seriesKey
+}
+
+func (c *counter) Inc(delta int64) {
+	c.registry.mu.Lock()
+	defer c.registry.mu.Unlock()
+	c.registry.counters[c.key] += delta
+}
+
+type gauge struct {
+	registry *Registry
+	key      seriesKey
+}
+
+func (g *gauge) Update(value float64) {
+	g.registry.mu.Lock()
+	defer g.registry.mu.Unlock()
+	g.registry.gauges[g.key] = value
+}
+
+type timer struct {
+	registry *Registry
+	key      seriesKey
+}
+
+func (t *timer) Record(d time.Duration) {
+	t.registry.mu.Lock()
+	defer t.registry.mu.Unlock()
+
+	h, ok := t.registry.timers[t.key]
+	if !ok {
+		h = &histogram{buckets: make([]int64, len(DefaultBuckets))}
+		t.registry.timers[t.key] = h
+	}
+	seconds := d.Seconds()
+	for i, le := range DefaultBuckets {
+		if seconds <= le {
+			h.buckets[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// formatTags serializa los tags ordenados como labels de Prometheus
+func formatTags(tags map[string]string) string {
+	labels := make([]string, 0, len(tags))
+	for k, v := range tags {
+		labels = append(labels, fmt.Sprintf("%s=%q", sanitize(k), v))
+	}
+	sort.Strings(labels)
+	return strings.Join(labels, ",")
+}
+
+// sanitize adapta un nombre a los caracteres válidos en Prometheus
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == ':' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// hasLabels indica si la serie tiene todos los labels indicados
+func hasLabels(tags string, labels []string) bool {
+	present := strings.Split(tags, ",")
+	for _, label := range labels {
+		if label == "" {
+			continue
+		}
+		found := false
+		for _, p := range present {
+			if p == label {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func joinTags(tags, extra string) string {
+	if tags == "" {
+		return extra
+	}
+	return tags + "," + extra
+}
+
+func braces(tags string) string {
+	if tags == "" {
+		return ""
+	}
+	return "{" + tags + "}"
+}
+
+func writeType(w io.Writer, name, kind string) {
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
+
+func sortedKeys[V any](m map[seriesKey]V) []seriesKey {
+	keys := make([]seriesKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].tags < keys[j].tags
+	})
+	return keys
+}
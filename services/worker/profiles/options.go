@@ -0,0 +1,108 @@
+package profiles
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Options es la forma declarativa (YAML/JSON) de un set de ActivityOptions.
+// Las duraciones usan el formato de time.ParseDuration ("30s", "2m") y los
+// campos vacíos conservan el valor de la base sobre la que se aplican.
+type Options struct {
+	StartToCloseTimeout    string       `json:"startToCloseTimeout,omitempty"`
+	ScheduleToCloseTimeout string       `json:"scheduleToCloseTimeout,omitempty"`
+	ScheduleToStartTimeout string       `json:"scheduleToStartTimeout,omitempty"`
+	HeartbeatTimeout       string       `json:"heartbeatTimeout,omitempty"`
+	Retry                  *RetryPolicy `json:"retry,omitempty"`
+}
+
+// RetryPolicy es la forma declarativa de temporal.RetryPolicy
+type RetryPolicy struct {
+	InitialInterval        string   `json:"initialInterval,omitempty"`
+	BackoffCoefficient     float64  `json:"backoffCoefficient,omitempty"`
+	MaximumInterval        string   `json:"maximumInterval,omitempty"`
+	MaximumAttempts        int32    `json:"maximumAttempts,omitempty"`
+	NonRetryableErrorTypes []string `json:"nonRetryableErrorTypes,omitempty"`
+}
+
+// Validate revisa que todas las duraciones sean válidas
+func (o *Options) Validate() error {
+	if o == nil {
+		return nil
+	}
+	durations := []string{o.StartToCloseTimeout, o.ScheduleToCloseTimeout, o.ScheduleToStartTimeout, o.HeartbeatTimeout}
+	if o.Retry != nil {
+		durations = append(durations, o.Retry.InitialInterval, o.Retry.MaximumInterval)
+	}
+	for _, d := range durations {
+		if _, err := ParseDuration(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply devuelve base con los valores definidos en o.
+// Las duraciones deben haber sido validadas con Validate.
+func (o *Options) Apply(base workflow.ActivityOptions) workflow.ActivityOptions {
+	if o == nil {
+		return base
+	}
+
+	options := base
+	if d, _ := ParseDuration(o.StartToCloseTimeout); d > 0 {
+		options.StartToCloseTimeout = d
+	}
+	if d, _ := ParseDuration(o.ScheduleToCloseTimeout); d > 0 {
+		options.ScheduleToCloseTimeout = d
+	}
+	if d, _ := ParseDuration(o.ScheduleToStartTimeout); d > 0 {
+		options.ScheduleToStartTimeout = d
+	}
+	if d, _ := ParseDuration(o.HeartbeatTimeout); d > 0 {
+		options.HeartbeatTimeout = d
+	}
+
+	if o.Retry != nil {
+		retry := &temporal.RetryPolicy{}
+		if base.RetryPolicy != nil {
+			*retry = *base.RetryPolicy
+		}
+		if d, _ := ParseDuration(o.Retry.InitialInterval); d > 0 {
+			retry.InitialInterval = d
+		}
+		if d, _ := ParseDuration(o.Retry.MaximumInterval); d > 0 {
+			retry.MaximumInterval = d
+		}
+		if o.Retry.BackoffCoefficient > 0 {
+			retry.BackoffCoefficient = o.Retry.BackoffCoefficient
+		}
+		if o.Retry.MaximumAttempts != 0 {
+			retry.MaximumAttempts = o.Retry.MaximumAttempts
+		}
+		if len(o.Retry.NonRetryableErrorTypes) > 0 {
+			retry.NonRetryableErrorTypes = o.Retry.NonRetryableErrorTypes
+		}
+		options.RetryPolicy = retry
+	}
+	return options
+}
+
+// ParseDuration acepta cadenas vacías como "sin valor" y rechaza las
+// duraciones negativas
+func ParseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid duration %q: must not be negative", value)
+	}
+	return d, nil
+}
@@ -0,0 +1,265 @@
+// Package profiles centraliza las ActivityOptions de los workflows en perfiles
+// con nombre ("default", "slow-io", "critical"). Los workflows piden las
+// opciones por nombre de activity y el worker puede ajustar perfiles y
+// activities puntuales desde su configuración sin tocar código.
+package profiles
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// Nombres de los perfiles incluidos en el worker
+const (
+	Default  = "default"
+	SlowIO   = "slow-io"
+	Critical = "critical"
+	// LongRunning es para activities que procesan en bloques con heartbeat
+	LongRunning = "long-running"
+)
+
+// builtin son los perfiles disponibles sin configuración. "default" conserva
+// los valores que los workflows usaban antes de existir los perfiles.
+func builtin() map[string]workflow.ActivityOptions {
+	return map[string]workflow.ActivityOptions{
+		Default: {
+			StartToCloseTimeout: 30 * time.Second,
+			RetryPolicy: &temporal.RetryPolicy{
+				InitialInterval:        time.Second,
+				BackoffCoefficient:     2.0,
+				MaximumInterval:        time.Minute,
+				MaximumAttempts:        3,
+				NonRetryableErrorTypes: contracts.NonRetryableErrorTypes,
+			},
+		},
+		// Activities que esperan a sistemas lentos: más tiempo por intento
+		// y reintentos más espaciados
+		SlowIO: {
+			StartToCloseTimeout: 5 * time.Minute,
+			RetryPolicy: &temporal.RetryPolicy{
+				InitialInterval:        5 * time.Second,
+				BackoffCoefficient:     2.0,
+				MaximumInterval:        5 * time.Minute,
+				MaximumAttempts:        5,
+				NonRetryableErrorTypes: contracts.NonRetryableErrorTypes,
+			},
+		},
+		// Activities que deben completarse: reintentos sin límite de intentos
+		// acotados por ScheduleToCloseTimeout
+		Critical: {
+			StartToCloseTimeout:    30 * time.Second,
+			ScheduleToCloseTimeout: time.Hour,
+			RetryPolicy: &temporal.RetryPolicy{
+				InitialInterval:        time.Second,
+				BackoffCoefficient:     2.0,
+				MaximumInterval:        time.Minute,
+				MaximumAttempts:        0,
+				NonRetryableErrorTypes: contracts.NonRetryableErrorTypes,
+			},
+		},
+		// Activities largas que registran heartbeat: una caída del worker se
+		// detecta al vencer HeartbeatTimeout, no al final de StartToCloseTimeout
+		LongRunning: {
+			StartToCloseTimeout: time.Hour,
+			HeartbeatTimeout:    30 * time.Second,
+			RetryPolicy: &temporal.RetryPolicy{
+				InitialInterval:        time.Second,
+				BackoffCoefficient:     2.0,
+				MaximumInterval:        time.Minute,
+				MaximumAttempts:        5,
+				NonRetryableErrorTypes: contracts.NonRetryableErrorTypes,
+			},
+		},
+	}
+}
+
+// builtinActivities son los overrides por activity sin configuración.
+// El HeartbeatTimeout va como ajuste de la activity para que se mantenga
+// aunque el caller elija otro perfil; la configuración puede cambiarlo.
+// Escalate queda acotada para que una escalación no reintente sin límite.
+// Las compensaciones reintentan más que los pasos normales porque dejar una
+// compensación a medias es peor que tardar en completarla.
+func builtinActivities() map[string]ActivityOverride {
+	compensation := ActivityOverride{Options: &Options{Retry: &RetryPolicy{MaximumAttempts: 5}}}
+	return map[string]ActivityOverride{
+		contracts.CompensateActivity1: compensation,
+		contracts.CompensateActivity2: compensation,
+		contracts.CompensateActivity4: compensation,
+		contracts.Escalate: {
+			Profile: Critical,
+			Options: &Options{ScheduleToCloseTimeout: "5m", Retry: &RetryPolicy{MaximumAttempts: 10}},
+		},
+		contracts.ProcessRecords: {
+			Profile: LongRunning,
+			Options: &Options{HeartbeatTimeout: "30s"},
+		},
+	}
+}
+
+// Config es la configuración de perfiles del worker (YAML o JSON):
+//
+//	profiles:
+//	  slow-io:
+//	    startToCloseTimeout: 10m
+//	activities:
+//	  Activity2:
+//	    profile: slow-io
+//	    options:
+//	      retry:
+//	        maximumAttempts: 8
+//
+// Un perfil con el nombre de uno incluido lo ajusta; uno nuevo parte de "default".
+// Una activity configurada reemplaza por completo su override incluido.
+type Config struct {
+	Profiles   map[string]*Options         `json:"profiles,omitempty"`
+	Activities map[string]ActivityOverride `json:"activities,omitempty"`
+}
+
+// ActivityOverride fija el perfil de una activity y ajustes puntuales
+// que se aplican sobre cualquier perfil que le toque
+type ActivityOverride struct {
+	Profile string   `json:"profile,omitempty"`
+	Options *Options `json:"options,omitempty"`
+}
+
+// Registry resuelve las ActivityOptions de cada activity
+type Registry struct {
+	profiles   map[string]workflow.ActivityOptions
+	activities map[string]ActivityOverride
+	// taskQueues enruta cada activity a la task queue del worker que la registra
+	taskQueues map[string]string
+}
+
+// NewRegistry crea un registry con los perfiles y overrides incluidos
+func NewRegistry() *Registry {
+	return &Registry{
+		profiles:   builtin(),
+		activities: builtinActivities(),
+	}
+}
+
+// NewRegistryFromConfig aplica config sobre los perfiles incluidos
+func NewRegistryFromConfig(config Config) (*Registry, error) {
+	r := NewRegistry()
+
+	// Los perfiles se aplican en orden para que el resultado sea estable
+	names := make([]string, 0, len(config.Profiles))
+	for name := range config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		options := config.Profiles[name]
+		if err := options.Validate(); err != nil {
+			return nil, fmt.Errorf("profile %s: %w", name, err)
+		}
+		base, ok := r.profiles[name]
+		if !ok {
+			base = r.profiles[Default]
+		}
+		r.profiles[name] = options.Apply(base)
+	}
+
+	for activity, override := range config.Activities {
+		if override.Profile != "" && !r.Has(override.Profile) {
+			return nil, fmt.Errorf("activity %s: unknown profile %q", activity, override.Profile)
+		}
+		if err := override.Options.Validate(); err != nil {
+			return nil, fmt.Errorf("activity %s: %w", activity, err)
+		}
+		r.activities[activity] = override
+	}
+	return r, nil
+}
+
+// LoadFile lee la configuración desde un archivo .yaml, .yml o .json
+func LoadFile(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := contracts.DecodeYAMLStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return NewRegistryFromConfig(config)
+}
+
+// Has indica si existe el perfil name
+func (r *Registry) Has(name string) bool {
+	_, ok := r.profiles[name]
+	return ok
+}
+
+// Names devuelve los perfiles disponibles ordenados
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.profiles))
+	for name := range r.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Options devuelve las ActivityOptions de activity. El perfil es, en orden de
+// prioridad: profile (elegido por el caller), el configurado para la activity
+// o "default". Los ajustes puntuales de la activity se aplican al final, y
+// TaskQueue se fija si la activity está enrutada (ver SetTaskQueues).
+func (r *Registry) Options(profile, activity string) workflow.ActivityOptions {
+	override := r.activities[activity]
+	if profile == "" {
+		profile = override.Profile
+	}
+
+	options, ok := r.profiles[profile]
+	if !ok {
+		options = r.profiles[Default]
+	}
+	if options.RetryPolicy != nil {
+		// Copia para que el caller no modifique el perfil compartido
+		retry := *options.RetryPolicy
+		options.RetryPolicy = &retry
+	}
+	options = override.Options.Apply(options)
+	if taskQueue, ok := r.taskQueues[activity]; ok {
+		options.TaskQueue = taskQueue
+	}
+	return options
+}
+
+// SetTaskQueues fija la task queue de cada activity. Las que no figuran se
+// ejecutan en la task queue del workflow. Se llama al arrancar el worker,
+// antes de empezar a procesar tareas.
+func (r *Registry) SetTaskQueues(taskQueues map[string]string) {
+	r.taskQueues = taskQueues
+}
+
+var (
+	current   = NewRegistry()
+	currentMu sync.RWMutex
+)
+
+// Current devuelve el registry que usan los workflows del worker
+func Current() *Registry {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+// SetCurrent reemplaza el registry que usan los workflows; se llama al
+// arrancar el worker, antes de empezar a procesar tareas
+func SetCurrent(r *Registry) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = r
+}
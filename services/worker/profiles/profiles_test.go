@@ -0,0 +1,108 @@
+package profiles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultProfileKeepsLegacyOptions(t *testing.T) {
+	options := NewRegistry().Options("", "Activity1")
+
+	assert.Equal(t, 30*time.Second, options.StartToCloseTimeout)
+	require.NotNil(t, options.RetryPolicy)
+	assert.Equal(t, time.Second, options.RetryPolicy.InitialInterval)
+	assert.Equal(t, 2.0, options.RetryPolicy.BackoffCoefficient)
+	assert.Equal(t, time.Minute, options.RetryPolicy.MaximumInterval)
+	assert.Equal(t, int32(3), options.RetryPolicy.MaximumAttempts)
+}
+
+func TestRegistryResolution(t *testing.T) {
+	registry, err := NewRegistryFromConfig(Config{
+		Profiles: map[string]*Options{
+			"batch": {StartToCloseTimeout: "10m"},
+		},
+		Activities: map[string]ActivityOverride{
+			"Activity2": {Profile: SlowIO},
+			"Activity3": {Options: &Options{Retry: &RetryPolicy{MaximumAttempts: 7}}},
+		},
+	})
+	require.NoError(t, err)
+
+	// Perfil configurado para la activity
+	assert.Equal(t, 5*time.Minute, registry.Options("", "Activity2").StartToCloseTimeout)
+	// El perfil elegido por el caller tiene prioridad
+	assert.Equal(t, 30*time.Second, registry.Options(Critical, "Activity2").StartToCloseTimeout)
+	// Un perfil nuevo parte de "default"
+	batch := registry.Options("batch", "Activity1")
+	assert.Equal(t, 10*time.Minute, batch.StartToCloseTimeout)
+	assert.Equal(t, int32(3), batch.RetryPolicy.MaximumAttempts)
+	// Los ajustes de la activity se aplican sobre cualquier perfil
+	assert.Equal(t, int32(7), registry.Options(SlowIO, "Activity3").RetryPolicy.MaximumAttempts)
+	assert.Equal(t, int32(5), registry.Options(SlowIO, "Activity1").RetryPolicy.MaximumAttempts)
+}
+
+func TestRegistryRejectsInvalidConfig(t *testing.T) {
+	_, err := NewRegistryFromConfig(Config{
+		Activities: map[string]ActivityOverride{"Activity1": {Profile: "missing"}},
+	})
+	assert.ErrorContains(t, err, "unknown profile")
+
+	_, err = NewRegistryFromConfig(Config{
+		Profiles: map[string]*Options{"broken": {StartToCloseTimeout: "soon"}},
+	})
+	assert.ErrorContains(t, err, "invalid duration")
+
+	_, err = NewRegistryFromConfig(Config{
+		Profiles: map[string]*Options{"broken": {HeartbeatTimeout: "-5s"}},
+	})
+	assert.ErrorContains(t, err, "must not be negative")
+}
+
+func TestLoadShippedConfig(t *testing.T) {
+	registry, err := LoadFile("../config/activity-profiles.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, 5*time.Minute, registry.Options("", "Escalate").ScheduleToCloseTimeout)
+	assert.Equal(t, int32(10), registry.Options("", "Escalate").RetryPolicy.MaximumAttempts)
+	assert.Equal(t, 10*time.Second, registry.Options("", "LoadPipeline").StartToCloseTimeout)
+	assert.Equal(t, 30*time.Second, registry.Options("", "ProcessRecords").HeartbeatTimeout)
+}
+
+func TestHeartbeatTimeoutSurvivesCallerProfile(t *testing.T) {
+	registry := NewRegistry()
+
+	assert.Equal(t, time.Hour, registry.Options("", "ProcessRecords").StartToCloseTimeout)
+	assert.Equal(t, 30*time.Second, registry.Options(Critical, "ProcessRecords").HeartbeatTimeout)
+
+	registry, err := NewRegistryFromConfig(Config{
+		Activities: map[string]ActivityOverride{
+			"ProcessRecords": {Profile: LongRunning, Options: &Options{HeartbeatTimeout: "5s"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, registry.Options("", "ProcessRecords").HeartbeatTimeout)
+}
+
+func TestTaskQueueRouting(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetTaskQueues(map[string]string{"Activity2": "activity2-queue"})
+
+	assert.Equal(t, "activity2-queue", registry.Options("", "Activity2").TaskQueue)
+	assert.Equal(t, "activity2-queue", registry.Options(SlowIO, "Activity2").TaskQueue)
+	// Sin ruta se ejecuta en la task queue del workflow
+	assert.Empty(t, registry.Options("", "Activity1").TaskQueue)
+}
+
+func TestCompensationsRetryMoreThanSteps(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetTaskQueues(map[string]string{"CompensateActivity2": "activity2-queue"})
+
+	options := registry.Options("", "CompensateActivity2")
+	assert.Equal(t, 30*time.Second, options.StartToCloseTimeout)
+	assert.Equal(t, int32(5), options.RetryPolicy.MaximumAttempts)
+	assert.Equal(t, "activity2-queue", options.TaskQueue)
+	assert.Equal(t, int32(3), registry.Options("", "Activity2").RetryPolicy.MaximumAttempts)
+}
@@ -0,0 +1,103 @@
+// Package replay verifica que los cambios en los workflows sigan siendo
+// deterministas reproduciendo historiales reales guardados en el repo.
+//
+// El corpus vive en testdata/histories/<WorkflowType>/<workflowID>.json y se
+// alimenta con el comando cmd/fetch-history a partir de un cluster en vivo.
+package replay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/worker/interceptors"
+	"github.com/temporal-aws-poc/worker/workflows"
+)
+
+// CorpusDir es el directorio, relativo a este paquete, con los historiales golden
+const CorpusDir = "testdata/histories"
+
+// HistoryFile es un historial del corpus junto con la ejecución que lo generó
+type HistoryFile struct {
+	Path         string
+	WorkflowType string
+	WorkflowID   string
+}
+
+// NewReplayer crea un replayer con todos los workflows y los interceptors
+// que registra el worker, así el replay cubre también a los interceptors
+func NewReplayer() (worker.WorkflowReplayer, error) {
+	replayer, err := worker.NewWorkflowReplayerWithOptions(worker.WorkflowReplayerOptions{
+		Interceptors: interceptors.Chain(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workflow replayer: %w", err)
+	}
+	replayer.RegisterWorkflow(workflows.WorkflowA)
+	replayer.RegisterWorkflow(workflows.WorkflowB)
+	replayer.RegisterWorkflow(workflows.WorkflowC)
+	replayer.RegisterWorkflow(workflows.WorkflowD)
+	replayer.RegisterWorkflow(workflows.DSLWorkflow)
+	replayer.RegisterWorkflow(workflows.BatchWorkflow)
+	return replayer, nil
+}
+
+// HistoryPath devuelve dónde se guarda el historial de una ejecución dentro del corpus
+func HistoryPath(corpusDir, workflowType, workflowID string) string {
+	return filepath.Join(corpusDir, workflowType, workflowID+".json")
+}
+
+// ListHistories recorre el corpus y devuelve los historiales ordenados por ruta
+func ListHistories(corpusDir string) ([]HistoryFile, error) {
+	paths, err := filepath.Glob(filepath.Join(corpusDir, "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	files := make([]HistoryFile, 0, len(paths))
+	for _, path := range paths {
+		files = append(files, HistoryFile{
+			Path:         path,
+			WorkflowType: filepath.Base(filepath.Dir(path)),
+			WorkflowID:   strings.TrimSuffix(filepath.Base(path), ".json"),
+		})
+	}
+	return files, nil
+}
+
+// LoadHistory lee un historial en el formato JSON del CLI de Temporal
+func LoadHistory(path string) (*historypb.History, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	history, err := client.HistoryFromJSON(f, client.HistoryJSONOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse history %s: %w", path, err)
+	}
+	return history, nil
+}
+
+// Replay reproduce un historial del corpus con el código actual de los workflows.
+// El workflow ID se pasa como ejecución original porque los IDs de los child
+// workflows se derivan de él.
+func Replay(replayer worker.WorkflowReplayer, file HistoryFile) error {
+	history, err := LoadHistory(file.Path)
+	if err != nil {
+		return err
+	}
+
+	return replayer.ReplayWorkflowHistoryWithOptions(nil, history, worker.ReplayWorkflowHistoryOptions{
+		OriginalExecution: workflow.Execution{ID: file.WorkflowID},
+	})
+}
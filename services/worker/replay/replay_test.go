@@ -0,0 +1,50 @@
+package replay
+
+import (
+	"testing"
+)
+
+// TestReplayCorpus reproduce cada historial golden con el código actual.
+// Si falla, el cambio rompe el determinismo de ejecuciones en curso: hay que
+// protegerlo con workflow.GetVersion en lugar de actualizar el historial.
+func TestReplayCorpus(t *testing.T) {
+	files, err := ListHistories(CorpusDir)
+	if err != nil {
+		t.Fatalf("failed to list histories: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no histories found in %s", CorpusDir)
+	}
+
+	replayer, err := NewReplayer()
+	if err != nil {
+		t.Fatalf("failed to create replayer: %v", err)
+	}
+	for _, file := range files {
+		file := file
+		t.Run(file.WorkflowType+"/"+file.WorkflowID, func(t *testing.T) {
+			if err := Replay(replayer, file); err != nil {
+				t.Fatalf("replay of %s failed: %v", file.Path, err)
+			}
+		})
+	}
+}
+
+// TestCorpusCoversAllWorkflows exige al menos un historial por workflow registrado
+func TestCorpusCoversAllWorkflows(t *testing.T) {
+	files, err := ListHistories(CorpusDir)
+	if err != nil {
+		t.Fatalf("failed to list histories: %v", err)
+	}
+
+	covered := map[string]bool{}
+	for _, file := range files {
+		covered[file.WorkflowType] = true
+	}
+
+	for _, workflowType := range []string{"WorkflowA", "WorkflowB", "WorkflowC", "WorkflowD", "DSLWorkflow", "BatchWorkflow"} {
+		if !covered[workflowType] {
+			t.Errorf("no replay history for %s in %s", workflowType, CorpusDir)
+		}
+	}
+}
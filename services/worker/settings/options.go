@@ -0,0 +1,157 @@
+package settings
+
+import (
+	"fmt"
+
+	"go.temporal.io/sdk/worker"
+
+	"github.com/temporal-aws-poc/worker/profiles"
+)
+
+// WorkerOptions es la forma declarativa (YAML/JSON) de worker.Options.
+// Los campos en cero conservan el valor de la base sobre la que se aplican
+// (y en último término el valor por defecto del SDK). Las duraciones usan el
+// formato de time.ParseDuration ("10s", "1m").
+type WorkerOptions struct {
+	MaxConcurrentActivityExecutionSize      int     `json:"maxConcurrentActivityExecutionSize,omitempty"`
+	MaxConcurrentWorkflowTaskExecutionSize  int     `json:"maxConcurrentWorkflowTaskExecutionSize,omitempty"`
+	MaxConcurrentLocalActivityExecutionSize int     `json:"maxConcurrentLocalActivityExecutionSize,omitempty"`
+	MaxConcurrentActivityTaskPollers        int     `json:"maxConcurrentActivityTaskPollers,omitempty"`
+	MaxConcurrentWorkflowTaskPollers        int     `json:"maxConcurrentWorkflowTaskPollers,omitempty"`
+	WorkerActivitiesPerSecond               float64 `json:"workerActivitiesPerSecond,omitempty"`
+	WorkerLocalActivitiesPerSecond          float64 `json:"workerLocalActivitiesPerSecond,omitempty"`
+	TaskQueueActivitiesPerSecond            float64 `json:"taskQueueActivitiesPerSecond,omitempty"`
+	StickyScheduleToStartTimeout            string  `json:"stickyScheduleToStartTimeout,omitempty"`
+	WorkerStopTimeout                       string  `json:"workerStopTimeout,omitempty"`
+	DeadlockDetectionTimeout                string  `json:"deadlockDetectionTimeout,omitempty"`
+	MaxHeartbeatThrottleInterval            string  `json:"maxHeartbeatThrottleInterval,omitempty"`
+	DefaultHeartbeatThrottleInterval        string  `json:"defaultHeartbeatThrottleInterval,omitempty"`
+}
+
+// Validate revisa que los valores no sean negativos y las duraciones válidas
+func (o WorkerOptions) Validate() error {
+	ints := map[string]int{
+		"maxConcurrentActivityExecutionSize":      o.MaxConcurrentActivityExecutionSize,
+		"maxConcurrentWorkflowTaskExecutionSize":  o.MaxConcurrentWorkflowTaskExecutionSize,
+		"maxConcurrentLocalActivityExecutionSize": o.MaxConcurrentLocalActivityExecutionSize,
+		"maxConcurrentActivityTaskPollers":        o.MaxConcurrentActivityTaskPollers,
+		"maxConcurrentWorkflowTaskPollers":        o.MaxConcurrentWorkflowTaskPollers,
+	}
+	for name, v := range ints {
+		if v < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+	// El SDK exige al menos 2 pollers de workflow tasks (cola normal y sticky)
+	if o.MaxConcurrentWorkflowTaskPollers == 1 {
+		return fmt.Errorf("maxConcurrentWorkflowTaskPollers must be at least 2")
+	}
+
+	rates := map[string]float64{
+		"workerActivitiesPerSecond":      o.WorkerActivitiesPerSecond,
+		"workerLocalActivitiesPerSecond": o.WorkerLocalActivitiesPerSecond,
+		"taskQueueActivitiesPerSecond":   o.TaskQueueActivitiesPerSecond,
+	}
+	for name, v := range rates {
+		if v < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+
+	durations := map[string]string{
+		"stickyScheduleToStartTimeout":     o.StickyScheduleToStartTimeout,
+		"workerStopTimeout":                o.WorkerStopTimeout,
+		"deadlockDetectionTimeout":         o.DeadlockDetectionTimeout,
+		"maxHeartbeatThrottleInterval":     o.MaxHeartbeatThrottleInterval,
+		"defaultHeartbeatThrottleInterval": o.DefaultHeartbeatThrottleInterval,
+	}
+	for name, v := range durations {
+		if _, err := profiles.ParseDuration(v); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Merge devuelve o con los valores definidos en override
+func (o WorkerOptions) Merge(override WorkerOptions) WorkerOptions {
+	merged := o
+	mergeInt(&merged.MaxConcurrentActivityExecutionSize, override.MaxConcurrentActivityExecutionSize)
+	mergeInt(&merged.MaxConcurrentWorkflowTaskExecutionSize, override.MaxConcurrentWorkflowTaskExecutionSize)
+	mergeInt(&merged.MaxConcurrentLocalActivityExecutionSize, override.MaxConcurrentLocalActivityExecutionSize)
+	mergeInt(&merged.MaxConcurrentActivityTaskPollers, override.MaxConcurrentActivityTaskPollers)
+	mergeInt(&merged.MaxConcurrentWorkflowTaskPollers, override.MaxConcurrentWorkflowTaskPollers)
+	mergeFloat(&merged.WorkerActivitiesPerSecond, override.WorkerActivitiesPerSecond)
+	mergeFloat(&merged.WorkerLocalActivitiesPerSecond, override.WorkerLocalActivitiesPerSecond)
+	mergeFloat(&merged.TaskQueueActivitiesPerSecond, override.TaskQueueActivitiesPerSecond)
+	mergeString(&merged.StickyScheduleToStartTimeout, override.StickyScheduleToStartTimeout)
+	mergeString(&merged.WorkerStopTimeout, override.WorkerStopTimeout)
+	mergeString(&merged.DeadlockDetectionTimeout, override.DeadlockDetectionTimeout)
+	mergeString(&merged.MaxHeartbeatThrottleInterval, override.MaxHeartbeatThrottleInterval)
+	mergeString(&merged.DefaultHeartbeatThrottleInterval, override.DefaultHeartbeatThrottleInterval)
+	return merged
+}
+
+// Apply devuelve base con los valores definidos en o.
+// Las duraciones deben haber sido validadas con Validate.
+func (o WorkerOptions) Apply(base worker.Options) worker.Options {
+	options := base
+	if o.MaxConcurrentActivityExecutionSize > 0 {
+		options.MaxConcurrentActivityExecutionSize = o.MaxConcurrentActivityExecutionSize
+	}
+	if o.MaxConcurrentWorkflowTaskExecutionSize > 0 {
+		options.MaxConcurrentWorkflowTaskExecutionSize = o.MaxConcurrentWorkflowTaskExecutionSize
+	}
+	if o.MaxConcurrentLocalActivityExecutionSize > 0 {
+		options.MaxConcurrentLocalActivityExecutionSize = o.MaxConcurrentLocalActivityExecutionSize
+	}
+	if o.MaxConcurrentActivityTaskPollers > 0 {
+		options.MaxConcurrentActivityTaskPollers = o.MaxConcurrentActivityTaskPollers
+	}
+	if o.MaxConcurrentWorkflowTaskPollers > 0 {
+		options.MaxConcurrentWorkflowTaskPollers = o.MaxConcurrentWorkflowTaskPollers
+	}
+	if o.WorkerActivitiesPerSecond > 0 {
+		options.WorkerActivitiesPerSecond = o.WorkerActivitiesPerSecond
+	}
+	if o.WorkerLocalActivitiesPerSecond > 0 {
+		options.WorkerLocalActivitiesPerSecond = o.WorkerLocalActivitiesPerSecond
+	}
+	if o.TaskQueueActivitiesPerSecond > 0 {
+		options.TaskQueueActivitiesPerSecond = o.TaskQueueActivitiesPerSecond
+	}
+	if d, _ := profiles.ParseDuration(o.StickyScheduleToStartTimeout); d > 0 {
+		options.StickyScheduleToStartTimeout = d
+	}
+	if d, _ := profiles.ParseDuration(o.WorkerStopTimeout); d > 0 {
+		options.WorkerStopTimeout = d
+	}
+	if d, _ := profiles.ParseDuration(o.DeadlockDetectionTimeout); d > 0 {
+		options.DeadlockDetectionTimeout = d
+	}
+	if d, _ := profiles.ParseDuration(o.MaxHeartbeatThrottleInterval); d > 0 {
+		options.MaxHeartbeatThrottleInterval = d
+	}
+	if d, _ := profiles.ParseDuration(o.DefaultHeartbeatThrottleInterval); d > 0 {
+		options.DefaultHeartbeatThrottleInterval = d
+	}
+	return options
+}
+
+func mergeInt(dst *int, v int) {
+	if v != 0 {
+		*dst = v
+	}
+}
+
+func mergeFloat(dst *float64, v float64) {
+	if v != 0 {
+		*dst = v
+	}
+}
+
+func mergeString(dst *string, v string) {
+	if v != "" {
+		*dst = v
+	}
+}
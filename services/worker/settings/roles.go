@@ -0,0 +1,106 @@
+package settings
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Roles indica qué registra este proceso de todo lo declarado en la
+// configuración (WORKER_ROLES). Permite escalar por separado los workers
+// de workflow tasks y los de una activity lenta:
+//
+//	WORKER_ROLES=workflows
+//	WORKER_ROLES=activities:Activity2
+//	WORKER_ROLES=workflows,activities:Activity1,activities:Activity3
+//	WORKER_ROLES=all
+//
+// Una activity nombrada sola necesita su propia task queue (ver Validate).
+type Roles struct {
+	Workflows bool
+	// AllActivities registra todas las activities; si es false solo las de Activities
+	AllActivities bool
+	Activities    []string
+}
+
+// AllRoles es el rol por defecto: el proceso registra todo
+var AllRoles = Roles{Workflows: true, AllActivities: true}
+
+// ParseRoles interpreta WORKER_ROLES; vacío equivale a "all"
+func ParseRoles(s string) (Roles, error) {
+	if strings.TrimSpace(s) == "" {
+		return AllRoles, nil
+	}
+
+	var roles Roles
+	for _, role := range strings.Split(s, ",") {
+		role = strings.TrimSpace(role)
+		switch {
+		case role == "all":
+			roles.Workflows = true
+			roles.AllActivities = true
+		case role == "workflows":
+			roles.Workflows = true
+		case role == "activities":
+			roles.AllActivities = true
+		case strings.HasPrefix(role, "activities:"):
+			name := strings.TrimPrefix(role, "activities:")
+			if name == "" {
+				return Roles{}, fmt.Errorf("role %q names no activity", role)
+			}
+			roles.Activities = append(roles.Activities, name)
+		default:
+			return Roles{}, fmt.Errorf("unknown role %q (use all, workflows, activities or activities:<name>)", role)
+		}
+	}
+	return roles, nil
+}
+
+// Validate revisa que las activities nombradas existan en el worker y que
+// el proceso registre todas o ninguna de las activities de cada task queue:
+// con solo algunas, Temporal le entrega tareas de las otras, que fallan por
+// no estar registradas. Una activity que se escala aparte necesita su
+// propia task queue en la configuración.
+func (r Roles) Validate(config Config, activities []string) error {
+	for _, name := range r.Activities {
+		if !slices.Contains(activities, name) {
+			return fmt.Errorf("role activities:%s: activity is not known to this worker", name)
+		}
+	}
+
+	for _, queue := range config.TaskQueues {
+		var registered, missing []string
+		for _, name := range config.QueueActivities(queue, activities) {
+			if r.Activity(name) {
+				registered = append(registered, name)
+			} else {
+				missing = append(missing, name)
+			}
+		}
+		if len(registered) > 0 && len(missing) > 0 {
+			return fmt.Errorf("roles %s register only %v of task queue %s, which also serves %v; assign them a dedicated task queue",
+				r, registered, queue.Name, missing)
+		}
+	}
+	return nil
+}
+
+// Activity indica si el proceso registra la activity name
+func (r Roles) Activity(name string) bool {
+	return r.AllActivities || slices.Contains(r.Activities, name)
+}
+
+// String devuelve los roles en el formato de WORKER_ROLES
+func (r Roles) String() string {
+	var parts []string
+	if r.Workflows {
+		parts = append(parts, "workflows")
+	}
+	if r.AllActivities {
+		parts = append(parts, "activities")
+	}
+	for _, name := range r.Activities {
+		parts = append(parts, "activities:"+name)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,62 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRoles(t *testing.T) {
+	roles, err := ParseRoles("")
+	require.NoError(t, err)
+	assert.Equal(t, AllRoles, roles)
+
+	roles, err = ParseRoles("workflows")
+	require.NoError(t, err)
+	assert.True(t, roles.Workflows)
+	assert.False(t, roles.Activity("Activity1"))
+
+	roles, err = ParseRoles("activities:Activity2")
+	require.NoError(t, err)
+	assert.False(t, roles.Workflows)
+	assert.True(t, roles.Activity("Activity2"))
+	assert.False(t, roles.Activity("Activity1"))
+
+	roles, err = ParseRoles("workflows, activities:Activity1,activities:Activity2")
+	require.NoError(t, err)
+	assert.Equal(t, "workflows,activities:Activity1,activities:Activity2", roles.String())
+}
+
+func TestParseRolesRejectsInvalidRoles(t *testing.T) {
+	for _, s := range []string{"workers", "activities:", "all,pollers"} {
+		_, err := ParseRoles(s)
+		assert.Error(t, err, s)
+	}
+
+	roles, err := ParseRoles("activities:ActivityZ")
+	require.NoError(t, err)
+	assert.Error(t, roles.Validate(Default(""), knownActivities))
+}
+
+func TestValidateRolesRequiresWholeTaskQueues(t *testing.T) {
+	roles, err := ParseRoles("activities:Activity2")
+	require.NoError(t, err)
+
+	// En la task queue por defecto Activity1 quedaría sin registrar
+	err = roles.Validate(Default(""), knownActivities)
+	assert.ErrorContains(t, err, "dedicated task queue")
+
+	// Con su propia task queue el proceso atiende solo Activity2
+	split := Config{TaskQueues: []TaskQueue{
+		{Name: "main", Workflows: []string{All}, Activities: []string{All}},
+		{Name: "slow", Activities: []string{"Activity2"}},
+	}}
+	assert.NoError(t, roles.Validate(split, knownActivities))
+
+	for _, s := range []string{"all", "workflows", "activities", "workflows,activities:Activity1,activities:Activity2"} {
+		roles, err := ParseRoles(s)
+		require.NoError(t, err)
+		assert.NoError(t, roles.Validate(Default(""), knownActivities), s)
+	}
+}
@@ -0,0 +1,273 @@
+// Package settings carga la configuración del proceso worker: las
+// worker.Options y las task queues que atiende, cada una con los workflows
+// y activities que registra.
+package settings
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// All en la lista de workflows o activities de una task queue registra todos;
+// en activities, todas las que no estén asignadas por nombre a otra task queue
+const All = "*"
+
+// DefaultTaskQueue es la task queue cuando no hay configuración; es la misma
+// en la que el API inicia los workflows
+const DefaultTaskQueue = contracts.DefaultTaskQueue
+
+// DefaultWorkerStopTimeout es el WorkerStopTimeout sin configuración
+const DefaultWorkerStopTimeout = "30s"
+
+// Config es la configuración del worker (YAML o JSON):
+//
+//	stickyWorkflowCacheSize: 1000
+//	defaults:
+//	  maxConcurrentActivityExecutionSize: 5
+//	  workerStopTimeout: 30s
+//	taskQueues:
+//	  - name: hello-world-queue
+//	    workflows: ["*"]
+//	    activities: ["*"]
+//	    options:
+//	      maxConcurrentActivityTaskPollers: 4
+//
+// Las options de cada task queue se aplican sobre defaults.
+type Config struct {
+	// StickyWorkflowCacheSize es global al proceso (worker.SetStickyWorkflowCacheSize)
+	StickyWorkflowCacheSize int           `json:"stickyWorkflowCacheSize,omitempty"`
+	Defaults                WorkerOptions `json:"defaults"`
+	TaskQueues              []TaskQueue   `json:"taskQueues"`
+}
+
+// TaskQueue es una task queue que el proceso atiende con su propio worker
+type TaskQueue struct {
+	Name       string        `json:"name"`
+	Workflows  []string      `json:"workflows,omitempty"`
+	Activities []string      `json:"activities,omitempty"`
+	Options    WorkerOptions `json:"options"`
+}
+
+// Default es la configuración sin archivo: una task queue con todos los
+// workflows y activities y la concurrencia que el worker usó siempre. El
+// WorkerStopTimeout deja terminar las activities en curso al recibir SIGTERM
+// (el valor por defecto del SDK es 0: las cancela de inmediato).
+func Default(taskQueue string) Config {
+	if taskQueue == "" {
+		taskQueue = DefaultTaskQueue
+	}
+	return Config{
+		Defaults: WorkerOptions{
+			MaxConcurrentActivityExecutionSize:     5,
+			MaxConcurrentWorkflowTaskExecutionSize: 5,
+			WorkerStopTimeout:                      DefaultWorkerStopTimeout,
+		},
+		TaskQueues: []TaskQueue{{
+			Name:       taskQueue,
+			Workflows:  []string{All},
+			Activities: []string{All},
+		}},
+	}
+}
+
+// LoadFile lee la configuración desde un archivo .yaml, .yml o .json
+func LoadFile(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var config Config
+	if err := contracts.DecodeYAMLStrict(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// envOptions son las variables de entorno que ajustan las options por
+// defecto de todas las task queues; tienen prioridad sobre el archivo
+var envOptions = map[string]func(o *WorkerOptions, v string) error{
+	"WORKER_MAX_CONCURRENT_ACTIVITY_EXECUTION_SIZE":       intOption(func(o *WorkerOptions) *int { return &o.MaxConcurrentActivityExecutionSize }),
+	"WORKER_MAX_CONCURRENT_WORKFLOW_TASK_EXECUTION_SIZE":  intOption(func(o *WorkerOptions) *int { return &o.MaxConcurrentWorkflowTaskExecutionSize }),
+	"WORKER_MAX_CONCURRENT_LOCAL_ACTIVITY_EXECUTION_SIZE": intOption(func(o *WorkerOptions) *int { return &o.MaxConcurrentLocalActivityExecutionSize }),
+	"WORKER_ACTIVITY_TASK_POLLERS":                        intOption(func(o *WorkerOptions) *int { return &o.MaxConcurrentActivityTaskPollers }),
+	"WORKER_WORKFLOW_TASK_POLLERS":                        intOption(func(o *WorkerOptions) *int { return &o.MaxConcurrentWorkflowTaskPollers }),
+	"WORKER_ACTIVITIES_PER_SECOND":                        floatOption(func(o *WorkerOptions) *float64 { return &o.WorkerActivitiesPerSecond }),
+	"WORKER_LOCAL_ACTIVITIES_PER_SECOND":                  floatOption(func(o *WorkerOptions) *float64 { return &o.WorkerLocalActivitiesPerSecond }),
+	"WORKER_TASK_QUEUE_ACTIVITIES_PER_SECOND":             floatOption(func(o *WorkerOptions) *float64 { return &o.TaskQueueActivitiesPerSecond }),
+	"WORKER_STICKY_SCHEDULE_TO_START_TIMEOUT":             stringOption(func(o *WorkerOptions) *string { return &o.StickyScheduleToStartTimeout }),
+	"WORKER_STOP_TIMEOUT":                                 stringOption(func(o *WorkerOptions) *string { return &o.WorkerStopTimeout }),
+	"WORKER_DEADLOCK_DETECTION_TIMEOUT":                   stringOption(func(o *WorkerOptions) *string { return &o.DeadlockDetectionTimeout }),
+}
+
+// ApplyEnv ajusta la configuración con las variables de entorno WORKER_*
+func (c *Config) ApplyEnv(getenv func(string) string) error {
+	names := make([]string, 0, len(envOptions))
+	for name := range envOptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if v := getenv(name); v != "" {
+			if err := envOptions[name](&c.Defaults, v); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	if v := getenv("WORKER_STICKY_CACHE_SIZE"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("WORKER_STICKY_CACHE_SIZE: %w", err)
+		}
+		c.StickyWorkflowCacheSize = size
+	}
+	return nil
+}
+
+// Validate revisa la configuración contra los workflows y activities que el
+// worker sabe registrar
+func (c Config) Validate(workflows, activities []string) error {
+	if c.StickyWorkflowCacheSize < 0 {
+		return fmt.Errorf("stickyWorkflowCacheSize must not be negative")
+	}
+	if err := c.Defaults.Validate(); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+	if len(c.TaskQueues) == 0 {
+		return fmt.Errorf("at least one task queue is required")
+	}
+
+	seen := map[string]bool{}
+	assigned := map[string]string{}
+	for _, queue := range c.TaskQueues {
+		if queue.Name == "" {
+			return fmt.Errorf("task queue name is required")
+		}
+		if seen[queue.Name] {
+			return fmt.Errorf("task queue %s is declared twice", queue.Name)
+		}
+		seen[queue.Name] = true
+
+		if len(queue.Workflows) == 0 && len(queue.Activities) == 0 {
+			return fmt.Errorf("task queue %s registers no workflows or activities", queue.Name)
+		}
+		if err := checkNames(queue.Workflows, workflows); err != nil {
+			return fmt.Errorf("task queue %s: workflow %w", queue.Name, err)
+		}
+		if err := checkNames(queue.Activities, activities); err != nil {
+			return fmt.Errorf("task queue %s: activity %w", queue.Name, err)
+		}
+		for _, name := range queue.Activities {
+			if other, ok := assigned[name]; ok && name != All {
+				return fmt.Errorf("activity %s is assigned to task queues %s and %s", name, other, queue.Name)
+			}
+			assigned[name] = queue.Name
+		}
+		if err := queue.Options.Validate(); err != nil {
+			return fmt.Errorf("task queue %s: %w", queue.Name, err)
+		}
+	}
+	return nil
+}
+
+// Options devuelve las options de la task queue: defaults más sus ajustes
+func (c Config) Options(queue TaskQueue) WorkerOptions {
+	return c.Defaults.Merge(queue.Options)
+}
+
+// Selected devuelve, en el orden de known, los nombres que declara la lista
+func Selected(declared, known []string) []string {
+	if slices.Contains(declared, All) {
+		return append([]string(nil), known...)
+	}
+
+	selected := []string{}
+	for _, name := range known {
+		if slices.Contains(declared, name) {
+			selected = append(selected, name)
+		}
+	}
+	return selected
+}
+
+// QueueActivities devuelve las activities que registra la task queue. Con
+// All son todas menos las asignadas por nombre a otra task queue.
+func (c Config) QueueActivities(queue TaskQueue, known []string) []string {
+	routes := c.ActivityTaskQueues(known)
+	selected := []string{}
+	for _, name := range Selected(queue.Activities, known) {
+		if routes[name] == queue.Name {
+			selected = append(selected, name)
+		}
+	}
+	return selected
+}
+
+// ActivityTaskQueues devuelve la task queue de cada activity: la que la
+// declara por nombre o, si no, la primera que declara All. Los workflows la
+// usan en ActivityOptions.TaskQueue para enrutar cada activity a su worker.
+func (c Config) ActivityTaskQueues(known []string) map[string]string {
+	routes := map[string]string{}
+	for _, queue := range c.TaskQueues {
+		for _, name := range queue.Activities {
+			if name != All && slices.Contains(known, name) {
+				routes[name] = queue.Name
+			}
+		}
+	}
+	for _, queue := range c.TaskQueues {
+		if !slices.Contains(queue.Activities, All) {
+			continue
+		}
+		for _, name := range known {
+			if _, ok := routes[name]; !ok {
+				routes[name] = queue.Name
+			}
+		}
+	}
+	return routes
+}
+
+func checkNames(declared, known []string) error {
+	for _, name := range declared {
+		if name != All && !slices.Contains(known, name) {
+			return fmt.Errorf("%q is not known to this worker", name)
+		}
+	}
+	return nil
+}
+
+func intOption(field func(o *WorkerOptions) *int) func(o *WorkerOptions, v string) error {
+	return func(o *WorkerOptions, v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		*field(o) = n
+		return nil
+	}
+}
+
+func floatOption(field func(o *WorkerOptions) *float64) func(o *WorkerOptions, v string) error {
+	return func(o *WorkerOptions, v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		*field(o) = f
+		return nil
+	}
+}
+
+func stringOption(field func(o *WorkerOptions) *string) func(o *WorkerOptions, v string) error {
+	return func(o *WorkerOptions, v string) error {
+		*field(o) = v
+		return nil
+	}
+}
@@ -0,0 +1,121 @@
+package settings
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/worker"
+)
+
+var (
+	knownWorkflows  = []string{"WorkflowA", "WorkflowB"}
+	knownActivities = []string{"Activity1", "Activity2"}
+)
+
+func TestDefaultKeepsLegacyWorker(t *testing.T) {
+	config := Default("")
+	require.NoError(t, config.Validate(knownWorkflows, knownActivities))
+	require.Len(t, config.TaskQueues, 1)
+
+	queue := config.TaskQueues[0]
+	assert.Equal(t, DefaultTaskQueue, queue.Name)
+	assert.Equal(t, knownWorkflows, Selected(queue.Workflows, knownWorkflows))
+	assert.Equal(t, knownActivities, Selected(queue.Activities, knownActivities))
+
+	options := config.Options(queue).Apply(worker.Options{})
+	assert.Equal(t, 5, options.MaxConcurrentActivityExecutionSize)
+	assert.Equal(t, 5, options.MaxConcurrentWorkflowTaskExecutionSize)
+	assert.Equal(t, 30*time.Second, options.WorkerStopTimeout)
+}
+
+func TestQueueOptionsOverrideDefaultsAndEnvOverridesFile(t *testing.T) {
+	config := Config{
+		Defaults: WorkerOptions{MaxConcurrentActivityExecutionSize: 5, WorkerStopTimeout: "10s"},
+		TaskQueues: []TaskQueue{
+			{Name: "workflows", Workflows: []string{All}},
+			{Name: "slow", Activities: []string{"Activity2"}, Options: WorkerOptions{
+				MaxConcurrentActivityExecutionSize: 20,
+				TaskQueueActivitiesPerSecond:       50,
+			}},
+		},
+	}
+	env := map[string]string{"WORKER_STOP_TIMEOUT": "45s", "WORKER_STICKY_CACHE_SIZE": "200"}
+	require.NoError(t, config.ApplyEnv(func(name string) string { return env[name] }))
+	require.NoError(t, config.Validate(knownWorkflows, knownActivities))
+
+	assert.Equal(t, 200, config.StickyWorkflowCacheSize)
+
+	workflowsQueue := config.Options(config.TaskQueues[0]).Apply(worker.Options{})
+	assert.Equal(t, 5, workflowsQueue.MaxConcurrentActivityExecutionSize)
+	assert.Equal(t, 45*time.Second, workflowsQueue.WorkerStopTimeout)
+
+	slow := config.Options(config.TaskQueues[1]).Apply(worker.Options{})
+	assert.Equal(t, 20, slow.MaxConcurrentActivityExecutionSize)
+	assert.Equal(t, 50.0, slow.TaskQueueActivitiesPerSecond)
+	assert.Equal(t, 45*time.Second, slow.WorkerStopTimeout)
+	assert.Equal(t, []string{"Activity2"}, Selected(config.TaskQueues[1].Activities, knownActivities))
+}
+
+func TestValidateRejectsInvalidConfig(t *testing.T) {
+	cases := map[string]Config{
+		"no queues":        {},
+		"unknown workflow": {TaskQueues: []TaskQueue{{Name: "q", Workflows: []string{"WorkflowZ"}}}},
+		"unknown activity": {TaskQueues: []TaskQueue{{Name: "q", Activities: []string{"ActivityZ"}}}},
+		"empty queue":      {TaskQueues: []TaskQueue{{Name: "q"}}},
+		"duplicate queue": {TaskQueues: []TaskQueue{
+			{Name: "q", Workflows: []string{All}},
+			{Name: "q", Activities: []string{All}},
+		}},
+		"bad duration": {
+			Defaults:   WorkerOptions{WorkerStopTimeout: "soon"},
+			TaskQueues: []TaskQueue{{Name: "q", Workflows: []string{All}}},
+		},
+		"one workflow poller": {TaskQueues: []TaskQueue{
+			{Name: "q", Workflows: []string{All}, Options: WorkerOptions{MaxConcurrentWorkflowTaskPollers: 1}},
+		}},
+	}
+	for name, config := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, config.Validate(knownWorkflows, knownActivities))
+		})
+	}
+}
+
+func TestApplyEnvRejectsInvalidNumbers(t *testing.T) {
+	config := Default("")
+	err := config.ApplyEnv(func(name string) string {
+		if name == "WORKER_ACTIVITY_TASK_POLLERS" {
+			return "many"
+		}
+		return ""
+	})
+	assert.Error(t, err)
+}
+
+func TestShippedConfigIsValid(t *testing.T) {
+	config, err := LoadFile("../config/worker.yaml")
+	require.NoError(t, err)
+	require.NoError(t, config.Validate(knownWorkflows, knownActivities))
+}
+
+func TestActivityTaskQueuesPreferNamedAssignment(t *testing.T) {
+	config := Config{TaskQueues: []TaskQueue{
+		{Name: "main", Workflows: []string{All}, Activities: []string{All}},
+		{Name: "slow", Activities: []string{"Activity2"}},
+	}}
+	require.NoError(t, config.Validate(knownWorkflows, knownActivities))
+
+	assert.Equal(t, map[string]string{"Activity1": "main", "Activity2": "slow"}, config.ActivityTaskQueues(knownActivities))
+	assert.Equal(t, []string{"Activity1"}, config.QueueActivities(config.TaskQueues[0], knownActivities))
+	assert.Equal(t, []string{"Activity2"}, config.QueueActivities(config.TaskQueues[1], knownActivities))
+}
+
+func TestValidateRejectsActivityOnTwoQueues(t *testing.T) {
+	config := Config{TaskQueues: []TaskQueue{
+		{Name: "a", Activities: []string{"Activity2"}},
+		{Name: "b", Activities: []string{"Activity2"}},
+	}}
+	assert.Error(t, config.Validate(knownWorkflows, knownActivities))
+}
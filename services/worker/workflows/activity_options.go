@@ -0,0 +1,51 @@
+package workflows
+
+import (
+	"fmt"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/worker/profiles"
+)
+
+// UnknownProfileErrorType es el tipo de error cuando el perfil pedido no existe
+const UnknownProfileErrorType = "UnknownActivityProfile"
+
+// activityProfile lee el perfil del memo y comprueba que el worker lo conozca.
+// Una cadena vacía significa que cada activity usa su perfil configurado.
+func activityProfile(ctx workflow.Context) (string, error) {
+	profile := memoActivityProfile(workflow.GetInfo(ctx))
+	if profile != "" && !profiles.Current().Has(profile) {
+		return "", temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("unknown activity profile %q, available: %v", profile, profiles.Current().Names()),
+			UnknownProfileErrorType, nil)
+	}
+	return profile, nil
+}
+
+// withActivityOptions devuelve ctx con las ActivityOptions que corresponden
+// a activity según el perfil de la ejecución. El perfil se valida al inicio
+// del workflow con activityProfile; aquí uno desconocido cae en "default".
+func withActivityOptions(ctx workflow.Context, activity string) workflow.Context {
+	profile, _ := activityProfile(ctx)
+	return workflow.WithActivityOptions(ctx, profiles.Current().Options(profile, activity))
+}
+
+// memoActivityProfile lee el perfil enviado por el caller en el memo
+func memoActivityProfile(info *workflow.Info) string {
+	if info.Memo == nil {
+		return ""
+	}
+	payload, ok := info.Memo.GetFields()[contracts.ActivityProfileMemoKey]
+	if !ok {
+		return ""
+	}
+
+	var profile string
+	if err := contracts.DataConverter().FromPayload(payload, &profile); err != nil {
+		return ""
+	}
+	return profile
+}
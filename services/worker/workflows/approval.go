@@ -0,0 +1,162 @@
+package workflows
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/worker/activities"
+)
+
+// DefaultApprovalPolicy completa los campos que el caller no envía en su
+// política; sin política en el memo WorkflowA no pide aprobación
+var DefaultApprovalPolicy = contracts.ApprovalPolicy{
+	Field:         "amount",
+	Threshold:     10000,
+	EscalateAfter: "1h",
+	Deadline:      "24h",
+}
+
+// requiresApproval evalúa la regla de la política sobre el resultado de un paso
+func requiresApproval(p contracts.ApprovalPolicy, result string) (bool, float64) {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(result), &data); err != nil {
+		return false, 0
+	}
+	value, ok := data[p.Field].(float64)
+	if !ok {
+		return false, 0
+	}
+	return value >= p.Threshold, value
+}
+
+// approvalDurations valida y convierte los plazos de la política
+func approvalDurations(p contracts.ApprovalPolicy) (escalateAfter, deadline time.Duration, err error) {
+	if escalateAfter, err = time.ParseDuration(p.EscalateAfter); err != nil {
+		return 0, 0, fmt.Errorf("invalid escalateAfter %q: %w", p.EscalateAfter, err)
+	}
+	if deadline, err = time.ParseDuration(p.Deadline); err != nil {
+		return 0, 0, fmt.Errorf("invalid deadline %q: %w", p.Deadline, err)
+	}
+	if escalateAfter <= 0 || deadline < escalateAfter {
+		return 0, 0, fmt.Errorf("escalateAfter must be positive and not after deadline")
+	}
+	return escalateAfter, deadline, nil
+}
+
+// approvalPolicy lee la política del memo sobre DefaultApprovalPolicy; ok
+// indica si el caller pidió aprobación manual
+func approvalPolicy(info *workflow.Info) (policy contracts.ApprovalPolicy, ok bool) {
+	policy = DefaultApprovalPolicy
+	if info.Memo == nil {
+		return policy, false
+	}
+	payload, ok := info.Memo.GetFields()[contracts.ApprovalPolicyMemoKey]
+	if !ok {
+		return policy, false
+	}
+	if err := contracts.DataConverter().FromPayload(payload, &policy); err != nil {
+		return DefaultApprovalPolicy, false
+	}
+	return policy, true
+}
+
+// publishApproval deja el estado de la aprobación en el search attribute
+// ApprovalStatus y en el memo, así el API lista las pendientes sin consultar
+// cada ejecución
+func publishApproval(ctx workflow.Context, state *contracts.ApprovalState) {
+	logger := workflow.GetLogger(ctx)
+	if err := workflow.UpsertSearchAttributes(ctx, map[string]interface{}{contracts.ApprovalStatusSearchAttribute: state.Status}); err != nil {
+		logger.Warn("Failed to upsert approval status", "status", state.Status, "error", err)
+	}
+	if err := workflow.UpsertMemo(ctx, map[string]interface{}{contracts.ApprovalStateMemoKey: *state}); err != nil {
+		logger.Warn("Failed to upsert approval state", "status", state.Status, "error", err)
+	}
+}
+
+// awaitApproval bloquea hasta recibir la señal de aprobación. Si vence
+// EscalateAfter ejecuta la activity de escalamiento y sigue esperando;
+// si vence Deadline rechaza automáticamente. Cada cambio de estado se
+// publica con publishApproval.
+func awaitApproval(ctx workflow.Context, state *contracts.ApprovalState, policy contracts.ApprovalPolicy) error {
+	logger := workflow.GetLogger(ctx)
+
+	escalateAfter, deadline, err := approvalDurations(policy)
+	if err != nil {
+		return err
+	}
+
+	setStatus := func(status string) {
+		state.Status = status
+		publishApproval(ctx, state)
+	}
+
+	now := workflow.Now(ctx)
+	state.RequestedAt = now
+	state.EscalateAt = now.Add(escalateAfter)
+	state.DeadlineAt = now.Add(deadline)
+	setStatus(contracts.ApprovalPending)
+
+	signalChan := workflow.GetSignalChannel(ctx, contracts.ApprovalSignalName)
+	timerCtx, cancelTimer := workflow.WithCancel(ctx)
+	defer cancelTimer()
+
+	escalated := false
+	timer := workflow.NewTimer(timerCtx, escalateAfter)
+	for {
+		var decision contracts.ApprovalDecision
+		timerFired := false
+
+		selector := workflow.NewSelector(ctx)
+		selector.AddReceive(signalChan, func(c workflow.ReceiveChannel, more bool) {
+			c.Receive(ctx, &decision)
+		})
+		selector.AddFuture(timer, func(f workflow.Future) {
+			timerFired = true
+		})
+		selector.Select(ctx)
+
+		if !timerFired {
+			state.Approver = decision.Approver
+			state.Comment = decision.Comment
+			if decision.Approved {
+				setStatus(contracts.ApprovalApproved)
+				logger.Info("Approval granted", "approver", decision.Approver)
+				return nil
+			}
+			setStatus(contracts.ApprovalRejected)
+			logger.Info("Approval rejected", "approver", decision.Approver)
+			return fmt.Errorf("approval rejected by %s: %s", decision.Approver, decision.Comment)
+		}
+
+		if escalated {
+			setStatus(contracts.ApprovalAutoRejected)
+			logger.Warn("Approval deadline reached, auto-rejecting")
+			return fmt.Errorf("approval not received before deadline %s", state.DeadlineAt.Format(time.RFC3339))
+		}
+
+		// Primer vencimiento: escalar y esperar hasta el deadline final
+		logger.Warn("Approval timed out, escalating", "escalateAfter", escalateAfter)
+		escalation := activities.Escalation{
+			Kind:       "approval",
+			WorkflowID: workflow.GetInfo(ctx).WorkflowExecution.ID,
+			RunID:      workflow.GetInfo(ctx).WorkflowExecution.RunID,
+			Message:    fmt.Sprintf("approval pending since %s, auto-reject at %s", state.RequestedAt.Format(time.RFC3339), state.DeadlineAt.Format(time.RFC3339)),
+		}
+		if err := workflow.ExecuteActivity(withActivityOptions(ctx, contracts.Escalate), contracts.Escalate, escalation).Get(ctx, nil); err != nil {
+			logger.Error("Escalation failed", "error", err)
+		}
+		escalated = true
+		setStatus(contracts.ApprovalEscalated)
+
+		remaining := state.DeadlineAt.Sub(workflow.Now(ctx))
+		if remaining <= 0 {
+			setStatus(contracts.ApprovalAutoRejected)
+			return fmt.Errorf("approval not received before deadline %s", state.DeadlineAt.Format(time.RFC3339))
+		}
+		timer = workflow.NewTimer(timerCtx, remaining)
+	}
+}
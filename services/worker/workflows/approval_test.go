@@ -0,0 +1,141 @@
+package workflows
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+const highValueResult = `{"amount":25000}`
+
+// approvalTestWorkflow ejecuta WorkflowA con la política en el memo, como la
+// envía el API (el entorno de pruebas no propaga el memo de inicio)
+func approvalTestWorkflow(ctx workflow.Context, policy contracts.ApprovalPolicy, input string) (string, error) {
+	if err := workflow.UpsertMemo(ctx, map[string]interface{}{contracts.ApprovalPolicyMemoKey: policy}); err != nil {
+		return "", err
+	}
+	return WorkflowA(ctx, input)
+}
+
+func (s *WorkflowsTestSuite) executeWithApproval(policy contracts.ApprovalPolicy) {
+	s.env.RegisterWorkflow(approvalTestWorkflow)
+	s.env.ExecuteWorkflow(approvalTestWorkflow, policy, "input")
+}
+
+func (s *WorkflowsTestSuite) mockApprovalPath() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("result1", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "result1").Return(highValueResult, nil).Once()
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowA_LowValueSkipsApproval() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("result1", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "result1").Return(`{"amount":10}`, nil).Once()
+	s.env.OnActivity("Activity4", mock.Anything, mock.Anything).Return("result4", nil).Once()
+	s.env.OnActivity("Activity3", mock.Anything, "result4").Return("final", nil).Once()
+
+	s.env.ExecuteWorkflow(WorkflowA, "input")
+
+	s.NoError(s.env.GetWorkflowError())
+
+	value, err := s.env.QueryWorkflow(contracts.ApprovalQueryName)
+	s.Require().NoError(err)
+	var state contracts.ApprovalState
+	s.Require().NoError(value.Get(&state))
+	s.Equal(contracts.ApprovalNotRequired, state.Status)
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowA_HighValueWithoutPolicySkipsApproval() {
+	s.mockApprovalPath()
+	s.env.OnActivity("Activity4", mock.Anything, highValueResult).Return("result4", nil).Once()
+	s.env.OnActivity("Activity3", mock.Anything, "result4").Return("final", nil).Once()
+
+	s.env.ExecuteWorkflow(WorkflowA, "input")
+
+	s.NoError(s.env.GetWorkflowError())
+
+	value, err := s.env.QueryWorkflow(contracts.ApprovalQueryName)
+	s.Require().NoError(err)
+	var state contracts.ApprovalState
+	s.Require().NoError(value.Get(&state))
+	s.Equal(contracts.ApprovalNotRequired, state.Status)
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowA_ApprovedBySignal() {
+	s.mockApprovalPath()
+	s.env.OnActivity("Activity4", mock.Anything, highValueResult).Return("result4", nil).Once()
+	s.env.OnActivity("Activity3", mock.Anything, "result4").Return("final", nil).Once()
+	s.env.OnUpsertSearchAttributes(map[string]interface{}{contracts.ApprovalStatusSearchAttribute: contracts.ApprovalPending}).Return(nil).Once()
+	s.env.OnUpsertSearchAttributes(map[string]interface{}{contracts.ApprovalStatusSearchAttribute: contracts.ApprovalApproved}).Return(nil).Once()
+	// GetVersion también hace upsert de TemporalChangeVersion
+	s.env.OnUpsertSearchAttributes(mock.Anything).Return(nil)
+
+	s.env.RegisterDelayedCallback(func() {
+		value, err := s.env.QueryWorkflow(contracts.ApprovalQueryName)
+		s.Require().NoError(err)
+		var state contracts.ApprovalState
+		s.Require().NoError(value.Get(&state))
+		s.Equal(contracts.ApprovalPending, state.Status)
+		s.Equal(25000.0, state.Value)
+
+		s.env.SignalWorkflow(contracts.ApprovalSignalName, contracts.ApprovalDecision{Approved: true, Approver: "ana"})
+	}, 10*time.Minute)
+
+	s.executeWithApproval(contracts.ApprovalPolicy{})
+
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowA_RejectedBySignalCompensates() {
+	s.mockApprovalPath()
+	s.env.OnActivity("CompensateActivity2", mock.Anything, highValueResult).Return(nil).Once()
+	s.env.OnActivity("CompensateActivity1", mock.Anything, "result1").Return(nil).Once()
+
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(contracts.ApprovalSignalName, contracts.ApprovalDecision{Approved: false, Approver: "ana", Comment: "too risky"})
+	}, time.Minute)
+
+	s.executeWithApproval(contracts.ApprovalPolicy{})
+
+	err := s.env.GetWorkflowError()
+	s.Error(err)
+	s.Contains(err.Error(), "approval rejected by ana: too risky")
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowA_ApprovalEscalatesThenAutoRejects() {
+	s.mockApprovalPath()
+	s.env.OnActivity("Escalate", mock.Anything, mock.Anything).Return(nil).Once()
+	s.env.OnActivity("CompensateActivity2", mock.Anything, highValueResult).Return(nil).Once()
+	s.env.OnActivity("CompensateActivity1", mock.Anything, "result1").Return(nil).Once()
+
+	s.env.RegisterDelayedCallback(func() {
+		value, err := s.env.QueryWorkflow(contracts.ApprovalQueryName)
+		s.Require().NoError(err)
+		var state contracts.ApprovalState
+		s.Require().NoError(value.Get(&state))
+		s.Equal(contracts.ApprovalEscalated, state.Status)
+	}, 2*time.Hour)
+
+	s.executeWithApproval(contracts.ApprovalPolicy{})
+
+	err := s.env.GetWorkflowError()
+	s.Error(err)
+	s.Contains(err.Error(), "approval not received before deadline")
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowA_ApprovedAfterEscalation() {
+	s.mockApprovalPath()
+	s.env.OnActivity("Escalate", mock.Anything, mock.Anything).Return(nil).Once()
+	s.env.OnActivity("Activity4", mock.Anything, highValueResult).Return("result4", nil).Once()
+	s.env.OnActivity("Activity3", mock.Anything, "result4").Return("final", nil).Once()
+
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(contracts.ApprovalSignalName, contracts.ApprovalDecision{Approved: true, Approver: "manager"})
+	}, 3*time.Hour)
+
+	s.executeWithApproval(contracts.ApprovalPolicy{})
+
+	s.NoError(s.env.GetWorkflowError())
+}
@@ -0,0 +1,252 @@
+package workflows
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/worker/activities"
+)
+
+// Valores por defecto de BatchInput
+const (
+	DefaultBatchActivity       = contracts.Activity1
+	DefaultBatchMaxParallelism = 5
+	DefaultBatchItemsPerRun    = 500
+	// maxReportedFailures acota los fallos que viajan en el resumen, así el
+	// input de continue-as-new no crece con el tamaño del batch
+	maxReportedFailures = 100
+)
+
+// BatchWorkflow ejecuta una activity por item con a lo sumo MaxParallelism
+// activities en curso. Cada ItemsPerRun items continúa como nueva ejecución
+// para que el historial no crezca con el tamaño del batch. Un item fallido
+// no detiene el batch: queda registrado en el resumen. Con Activity
+// ProcessRecords los items de cada run se reparten en MaxParallelism lotes
+// que se procesan con heartbeat. Los items inline que no caben en el primer
+// run se guardan con StoreBatchItems y los runs siguientes los leen por
+// referencia, como ItemsRef.
+func BatchWorkflow(ctx workflow.Context, input contracts.BatchInput) (contracts.BatchSummary, error) {
+	logger := workflow.GetLogger(ctx)
+
+	if _, err := activityProfile(ctx); err != nil {
+		return contracts.BatchSummary{}, err
+	}
+	if err := normalizeBatchInput(&input); err != nil {
+		return contracts.BatchSummary{}, temporal.NewNonRetryableApplicationError(err.Error(), contracts.ValidationErrorType, nil)
+	}
+	// Solo se agenda una activity de la lista permitida; las ejecuciones
+	// anteriores a este control no lo aplican
+	if !contracts.IsBatchItemActivity(input.Activity) && workflow.GetVersion(ctx, "batch-activity-allow-list", workflow.DefaultVersion, 1) == 1 {
+		return contracts.BatchSummary{}, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("batch %s: activity %q is not allowed in batches", input.BatchID, input.Activity), contracts.ValidationErrorType, nil)
+	}
+
+	summary := input.Summary
+	summary.Runs++
+	progress := &contracts.BatchProgress{
+		BatchID:   input.BatchID,
+		Processed: input.Offset,
+		Succeeded: summary.Succeeded,
+		Failed:    summary.Failed,
+		Run:       summary.Runs,
+	}
+	err := workflow.SetQueryHandler(ctx, contracts.BatchProgressQueryName, func() (contracts.BatchProgress, error) {
+		return *progress, nil
+	})
+	if err != nil {
+		return contracts.BatchSummary{}, fmt.Errorf("failed to register batch progress query: %w", err)
+	}
+
+	// ==========================================
+	// PASO 1: Obtener los items de este run
+	// ==========================================
+	var items []string
+	if input.ItemsRef != "" {
+		var page activities.BatchItems
+		err := workflow.ExecuteActivity(withActivityOptions(ctx, contracts.LoadBatchItems), contracts.LoadBatchItems, activities.BatchItemsPage{
+			Ref:    input.ItemsRef,
+			Offset: input.Offset,
+			Limit:  input.ItemsPerRun,
+		}).Get(ctx, &page)
+		if err != nil {
+			logger.Error("Failed to load batch items", "ref", input.ItemsRef, "error", err)
+			return summary, fmt.Errorf("failed to load batch items: %w", err)
+		}
+		items = page.Items
+		progress.Total = page.Total
+	} else {
+		items = input.Items
+		if len(items) > input.ItemsPerRun {
+			items = items[:input.ItemsPerRun]
+		}
+		progress.Total = input.Offset + len(input.Items)
+	}
+
+	// ==========================================
+	// PASO 2: Procesar con paralelismo acotado
+	// ==========================================
+	// ProcessRecords procesa los items del run por lotes con heartbeat; las
+	// ejecuciones anteriores a este cambio lo llamaban por item
+	if input.Activity == contracts.ProcessRecords && workflow.GetVersion(ctx, "batch-process-records", workflow.DefaultVersion, 1) == 1 {
+		if err := processRecords(ctx, input, items, &summary, progress); err != nil {
+			return summary, err
+		}
+	} else {
+		for i, item := range items {
+			if err := workflow.Await(ctx, func() bool { return progress.InFlight < input.MaxParallelism }); err != nil {
+				return summary, err
+			}
+
+			index := input.Offset + i
+			item := item
+			progress.InFlight++
+			workflow.Go(ctx, func(gCtx workflow.Context) {
+				defer func() {
+					progress.InFlight--
+					progress.Processed++
+				}()
+
+				err := workflow.ExecuteActivity(withActivityOptions(gCtx, input.Activity), input.Activity, item).Get(gCtx, nil)
+				if err != nil {
+					logger.Warn("Batch item failed", "index", index, "error", err)
+					summary.Failed++
+					progress.Failed++
+					if len(summary.Failures) < maxReportedFailures {
+						summary.Failures = append(summary.Failures, contracts.BatchItemFailure{Index: index, Error: err.Error()})
+					}
+					return
+				}
+				summary.Succeeded++
+				progress.Succeeded++
+			})
+		}
+
+		if err := workflow.Await(ctx, func() bool { return progress.InFlight == 0 }); err != nil {
+			return summary, err
+		}
+	}
+
+	// ==========================================
+	// PASO 3: Continuar como nueva ejecución si quedan items
+	// ==========================================
+	next := input
+	next.Offset = input.Offset + len(items)
+	next.Summary = summary
+	if input.ItemsRef != "" {
+		if next.Offset < progress.Total && len(items) > 0 {
+			logger.Info("Continuing batch as new run", "batchId", input.BatchID, "offset", next.Offset)
+			return summary, workflow.NewContinueAsNewError(ctx, BatchWorkflow, next)
+		}
+	} else if len(items) < len(input.Items) {
+		// Los items inline se guardan una sola vez y los runs siguientes los
+		// leen por página, así el input de continue-as-new no arrastra la
+		// lista pendiente; los runs ya continuados con items inline y las
+		// ejecuciones anteriores a este cambio siguen pasándolos en el input
+		if input.Offset == 0 && workflow.GetVersion(ctx, "batch-stored-items", workflow.DefaultVersion, 1) == 1 {
+			ref := storedItemsRef(workflow.GetInfo(ctx))
+			err := workflow.ExecuteActivity(withActivityOptions(ctx, contracts.StoreBatchItems), contracts.StoreBatchItems, activities.StoredBatchItems{
+				Ref:   ref,
+				Items: input.Items,
+			}).Get(ctx, nil)
+			if err != nil {
+				logger.Error("Failed to store batch items", "ref", ref, "error", err)
+				return summary, fmt.Errorf("failed to store batch items: %w", err)
+			}
+			next.Items = nil
+			next.ItemsRef = ref
+		} else {
+			next.Items = input.Items[len(items):]
+		}
+		logger.Info("Continuing batch as new run", "batchId", input.BatchID, "offset", next.Offset)
+		return summary, workflow.NewContinueAsNewError(ctx, BatchWorkflow, next)
+	}
+
+	return summary, nil
+}
+
+// processRecords reparte los items del run en hasta MaxParallelism lotes
+// consecutivos y envía cada uno como RecordBatch a ProcessRecords; los lotes
+// corren en paralelo y sus resultados se suman al resumen.
+func processRecords(ctx workflow.Context, input contracts.BatchInput, items []string, summary *contracts.BatchSummary, progress *contracts.BatchProgress) error {
+	if len(items) == 0 {
+		return nil
+	}
+	chunks := min(input.MaxParallelism, len(items))
+	size := (len(items) + chunks - 1) / chunks
+	for start := 0; start < len(items); start += size {
+		offset := input.Offset + start
+		chunk := items[start:min(start+size, len(items))]
+		progress.InFlight++
+		workflow.Go(ctx, func(gCtx workflow.Context) {
+			defer func() {
+				progress.InFlight--
+				progress.Processed += len(chunk)
+			}()
+			processRecordChunk(gCtx, input.BatchID, offset, chunk, summary, progress)
+		})
+	}
+	return workflow.Await(ctx, func() bool { return progress.InFlight == 0 })
+}
+
+// processRecordChunk procesa un lote que empieza en el item offset del batch.
+// Los items que no son JSON viajan como string y ProcessRecords los reporta
+// como fallidos. Si la activity falla tras agotar los reintentos, todos los
+// items del lote cuentan como fallidos.
+func processRecordChunk(ctx workflow.Context, batchID string, offset int, items []string, summary *contracts.BatchSummary, progress *contracts.BatchProgress) {
+	batch := activities.RecordBatch{BatchID: batchID}
+	for _, item := range items {
+		record := json.RawMessage(item)
+		if !json.Valid(record) {
+			record, _ = json.Marshal(item)
+		}
+		batch.Records = append(batch.Records, record)
+	}
+
+	var result activities.RecordBatchResult
+	err := workflow.ExecuteActivity(withActivityOptions(ctx, contracts.ProcessRecords), contracts.ProcessRecords, batch).Get(ctx, &result)
+	if err != nil {
+		workflow.GetLogger(ctx).Warn("Batch records failed", "offset", offset, "error", err)
+		result = activities.RecordBatchResult{Failed: len(items)}
+		for i := range items {
+			result.Failures = append(result.Failures, activities.RecordFailure{Index: i, Error: err.Error()})
+		}
+	}
+
+	summary.Succeeded += result.Processed
+	progress.Succeeded += result.Processed
+	summary.Failed += result.Failed
+	progress.Failed += result.Failed
+	for _, failure := range result.Failures {
+		if len(summary.Failures) >= maxReportedFailures {
+			break
+		}
+		summary.Failures = append(summary.Failures, contracts.BatchItemFailure{Index: offset + failure.Index, Error: failure.Error})
+	}
+}
+
+// storedItemsRef nombra la lista guardada de un batch inline con el primer
+// run de la ejecución, que no cambia entre continue-as-new
+func storedItemsRef(info *workflow.Info) string {
+	return "inline-" + info.FirstRunID
+}
+
+// normalizeBatchInput completa los valores por defecto y revisa el input
+func normalizeBatchInput(in *contracts.BatchInput) error {
+	if (len(in.Items) == 0) == (in.ItemsRef == "") {
+		return fmt.Errorf("batch %s: exactly one of items or itemsRef is required", in.BatchID)
+	}
+	if in.Activity == "" {
+		in.Activity = DefaultBatchActivity
+	}
+	if in.MaxParallelism <= 0 {
+		in.MaxParallelism = DefaultBatchMaxParallelism
+	}
+	if in.ItemsPerRun <= 0 {
+		in.ItemsPerRun = DefaultBatchItemsPerRun
+	}
+	return nil
+}
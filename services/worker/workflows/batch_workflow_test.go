@@ -0,0 +1,215 @@
+package workflows
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/worker/activities"
+)
+
+func (s *WorkflowsTestSuite) Test_BatchWorkflow_BoundedParallelism() {
+	s.env.RegisterWorkflow(BatchWorkflow)
+	for _, item := range []string{"a", "b", "c", "d", "e"} {
+		s.env.OnActivity("Activity1", mock.Anything, item).After(10*time.Second).Return("ok", nil).Once()
+	}
+
+	// A mitad del primer bloque solo puede haber MaxParallelism activities en curso
+	s.env.RegisterDelayedCallback(func() {
+		value, err := s.env.QueryWorkflow(contracts.BatchProgressQueryName)
+		s.Require().NoError(err)
+		var progress contracts.BatchProgress
+		s.Require().NoError(value.Get(&progress))
+		s.Equal(contracts.BatchProgress{BatchID: "b1", Total: 5, InFlight: 2, Run: 1}, progress)
+	}, 5*time.Second)
+
+	s.env.ExecuteWorkflow(BatchWorkflow, contracts.BatchInput{
+		BatchID:        "b1",
+		Items:          []string{"a", "b", "c", "d", "e"},
+		MaxParallelism: 2,
+	})
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var summary contracts.BatchSummary
+	s.NoError(s.env.GetWorkflowResult(&summary))
+	s.Equal(contracts.BatchSummary{Succeeded: 5, Runs: 1}, summary)
+}
+
+func (s *WorkflowsTestSuite) Test_BatchWorkflow_AggregatesFailures() {
+	s.env.RegisterWorkflow(BatchWorkflow)
+	s.env.OnActivity("Activity2", mock.Anything, "ok").Return("done", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "bad").
+		Return("", activities.NewValidationError("Activity2", "input", "input is not a JSON object", nil)).Once()
+
+	s.env.ExecuteWorkflow(BatchWorkflow, contracts.BatchInput{
+		BatchID:  "b2",
+		Items:    []string{"ok", "bad"},
+		Activity: "Activity2",
+	})
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var summary contracts.BatchSummary
+	s.NoError(s.env.GetWorkflowResult(&summary))
+	s.Equal(1, summary.Succeeded)
+	s.Equal(1, summary.Failed)
+	s.Require().Len(summary.Failures, 1)
+	s.Equal(1, summary.Failures[0].Index)
+}
+
+func (s *WorkflowsTestSuite) Test_BatchWorkflow_ContinuesAsNew() {
+	s.env.RegisterWorkflow(BatchWorkflow)
+	s.env.RegisterActivity(activities.NewBatchActivities("batches"))
+	s.env.OnActivity("Activity1", mock.Anything, "a").Return("ok", nil).Once()
+	s.env.OnActivity("Activity1", mock.Anything, "b").Return("", errors.New("boom")).Times(3)
+	var stored activities.StoredBatchItems
+	s.env.OnActivity("StoreBatchItems", mock.Anything, mock.Anything).Return(func(_ context.Context, items activities.StoredBatchItems) error {
+		stored = items
+		return nil
+	}).Once()
+
+	s.env.ExecuteWorkflow(BatchWorkflow, contracts.BatchInput{
+		BatchID:     "b3",
+		Items:       []string{"a", "b", "c"},
+		ItemsPerRun: 2,
+	})
+
+	s.True(s.env.IsWorkflowCompleted())
+	var continueAsNew *workflow.ContinueAsNewError
+	s.Require().True(errors.As(s.env.GetWorkflowError(), &continueAsNew))
+	s.Equal("BatchWorkflow", continueAsNew.WorkflowType.Name)
+
+	var next contracts.BatchInput
+	s.Require().NoError(converter.GetDefaultDataConverter().FromPayloads(continueAsNew.Input, &next))
+	// El siguiente run lee los items pendientes por referencia, no inline
+	s.Equal([]string{"a", "b", "c"}, stored.Items)
+	s.Nil(next.Items)
+	s.Equal(stored.Ref, next.ItemsRef)
+	s.Equal(2, next.Offset)
+	s.Equal(1, next.Summary.Succeeded)
+	s.Equal(1, next.Summary.Failed)
+	s.Equal(1, next.Summary.Runs)
+}
+
+func (s *WorkflowsTestSuite) Test_BatchWorkflow_ContinuedInlineRunKeepsItemsInline() {
+	s.env.RegisterWorkflow(BatchWorkflow)
+	s.env.OnActivity("Activity1", mock.Anything, "c").Return("ok", nil).Once()
+
+	// Run continuado antes de guardar los items: sigue pasándolos inline
+	s.env.ExecuteWorkflow(BatchWorkflow, contracts.BatchInput{
+		BatchID:     "b3",
+		Items:       []string{"c", "d"},
+		ItemsPerRun: 1,
+		Offset:      2,
+	})
+
+	s.True(s.env.IsWorkflowCompleted())
+	var continueAsNew *workflow.ContinueAsNewError
+	s.Require().True(errors.As(s.env.GetWorkflowError(), &continueAsNew))
+
+	var next contracts.BatchInput
+	s.Require().NoError(converter.GetDefaultDataConverter().FromPayloads(continueAsNew.Input, &next))
+	s.Equal([]string{"d"}, next.Items)
+	s.Empty(next.ItemsRef)
+	s.Equal(3, next.Offset)
+}
+
+func (s *WorkflowsTestSuite) Test_BatchWorkflow_LoadsItemsByReference() {
+	s.env.RegisterWorkflow(BatchWorkflow)
+	s.env.RegisterActivity(activities.NewBatchActivities("batches"))
+	s.env.OnActivity("LoadBatchItems", mock.Anything, activities.BatchItemsPage{Ref: "orders", Offset: 2, Limit: 2}).
+		Return(activities.BatchItems{Items: []string{`{"id":3}`}, Total: 3}, nil).Once()
+	s.env.OnActivity("Activity1", mock.Anything, `{"id":3}`).Return("ok", nil).Once()
+
+	// Último run de un batch por referencia: no quedan items y termina
+	s.env.ExecuteWorkflow(BatchWorkflow, contracts.BatchInput{
+		BatchID:     "b4",
+		ItemsRef:    "orders",
+		ItemsPerRun: 2,
+		Offset:      2,
+		Summary:     contracts.BatchSummary{Succeeded: 2, Runs: 1},
+	})
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var summary contracts.BatchSummary
+	s.NoError(s.env.GetWorkflowResult(&summary))
+	s.Equal(contracts.BatchSummary{Succeeded: 3, Runs: 2}, summary)
+}
+
+func (s *WorkflowsTestSuite) Test_BatchWorkflow_RejectsInvalidInput() {
+	s.env.RegisterWorkflow(BatchWorkflow)
+
+	s.env.ExecuteWorkflow(BatchWorkflow, contracts.BatchInput{BatchID: "empty"})
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+	s.Contains(s.env.GetWorkflowError().Error(), "exactly one of items or itemsRef")
+}
+
+func (s *WorkflowsTestSuite) Test_BatchWorkflow_ProcessRecordsSplitsByParallelism() {
+	s.env.RegisterWorkflow(BatchWorkflow)
+	records := func(batch activities.RecordBatch) string {
+		out := make([]string, len(batch.Records))
+		for i, record := range batch.Records {
+			out[i] = string(record)
+		}
+		return strings.Join(out, " ")
+	}
+	s.env.OnActivity("ProcessRecords", mock.Anything, mock.MatchedBy(func(batch activities.RecordBatch) bool {
+		return batch.BatchID == "b5" && records(batch) == `{"id":1} "plain" {"id":3}`
+	})).Return(activities.RecordBatchResult{
+		BatchID:   "b5",
+		Total:     3,
+		Processed: 2,
+		Failed:    1,
+		Failures:  []activities.RecordFailure{{Index: 1, Error: "record is not a JSON object"}},
+	}, nil).Once()
+	s.env.OnActivity("ProcessRecords", mock.Anything, mock.MatchedBy(func(batch activities.RecordBatch) bool {
+		return batch.BatchID == "b5" && records(batch) == `{"id":4} {"id":5}`
+	})).Return(activities.RecordBatchResult{BatchID: "b5", Total: 2, Processed: 2}, nil).Once()
+
+	s.env.ExecuteWorkflow(BatchWorkflow, contracts.BatchInput{
+		BatchID:        "b5",
+		Items:          []string{`{"id":1}`, "plain", `{"id":3}`, `{"id":4}`, `{"id":5}`},
+		Activity:       "ProcessRecords",
+		MaxParallelism: 2,
+	})
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var summary contracts.BatchSummary
+	s.NoError(s.env.GetWorkflowResult(&summary))
+	s.Equal(contracts.BatchSummary{
+		Succeeded: 4,
+		Failed:    1,
+		Failures:  []contracts.BatchItemFailure{{Index: 1, Error: "record is not a JSON object"}},
+		Runs:      1,
+	}, summary)
+}
+
+func (s *WorkflowsTestSuite) Test_BatchWorkflow_RejectsActivityOutsideAllowList() {
+	s.env.RegisterWorkflow(BatchWorkflow)
+
+	s.env.ExecuteWorkflow(BatchWorkflow, contracts.BatchInput{
+		BatchID:  "b6",
+		Items:    []string{"a"},
+		Activity: "CompensateActivity1",
+	})
+
+	s.True(s.env.IsWorkflowCompleted())
+	err := s.env.GetWorkflowError()
+	s.Require().Error(err)
+	s.Contains(err.Error(), `activity "CompensateActivity1" is not allowed in batches`)
+}
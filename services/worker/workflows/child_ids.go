@@ -0,0 +1,51 @@
+package workflows
+
+import (
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+// newChildWorkflowOptions construye las opciones de un child workflow con un ID
+// determinista para el paso indicado, la task queue y el perfil del padre
+func newChildWorkflowOptions(ctx workflow.Context, step string) (workflow.ChildWorkflowOptions, error) {
+	info := workflow.GetInfo(ctx)
+
+	childID, err := contracts.RenderChildWorkflowID(childWorkflowIDTemplate(info), contracts.ChildWorkflowIDData{
+		WorkflowID:   info.WorkflowExecution.ID,
+		RunID:        info.WorkflowExecution.RunID,
+		WorkflowType: info.WorkflowType.Name,
+		Step:         step,
+	})
+	if err != nil {
+		return workflow.ChildWorkflowOptions{}, err
+	}
+
+	options := workflow.ChildWorkflowOptions{
+		WorkflowID: childID,
+		TaskQueue:  info.TaskQueueName,
+	}
+
+	// El hijo hereda el perfil de ActivityOptions elegido para el padre
+	if profile := memoActivityProfile(info); profile != "" {
+		options.Memo = map[string]interface{}{contracts.ActivityProfileMemoKey: profile}
+	}
+	return options, nil
+}
+
+// childWorkflowIDTemplate lee la plantilla enviada por el caller en el memo
+func childWorkflowIDTemplate(info *workflow.Info) string {
+	if info.Memo == nil {
+		return ""
+	}
+	payload, ok := info.Memo.GetFields()[contracts.ChildWorkflowIDTemplateMemoKey]
+	if !ok {
+		return ""
+	}
+
+	var tmpl string
+	if err := contracts.DataConverter().FromPayload(payload, &tmpl); err != nil {
+		return ""
+	}
+	return tmpl
+}
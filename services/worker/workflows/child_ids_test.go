@@ -0,0 +1,32 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+func TestRenderChildWorkflowID(t *testing.T) {
+	data := contracts.ChildWorkflowIDData{
+		WorkflowID:   "order-42",
+		RunID:        "run-1",
+		WorkflowType: "WorkflowA",
+		Step:         "workflow-b",
+	}
+
+	id, err := contracts.RenderChildWorkflowID("", data)
+	require.NoError(t, err)
+	require.Equal(t, "order-42-workflow-b-run-1", id)
+
+	id, err = contracts.RenderChildWorkflowID("{{.WorkflowType}}/{{.Step}}/{{.WorkflowID}}", data)
+	require.NoError(t, err)
+	require.Equal(t, "WorkflowA/workflow-b/order-42", id)
+
+	_, err = contracts.RenderChildWorkflowID("{{.Unknown}}", data)
+	require.Error(t, err)
+
+	_, err = contracts.RenderChildWorkflowID("{{", data)
+	require.Error(t, err)
+}
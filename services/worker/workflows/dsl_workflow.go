@@ -0,0 +1,258 @@
+package workflows
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/worker/dsl"
+	"github.com/temporal-aws-poc/worker/profiles"
+)
+
+// DSLWorkflow interpreta un pipeline declarativo (ver paquete dsl).
+// Si el input trae solo el nombre del pipeline, la definición se carga con
+// la activity LoadPipeline y queda registrada en el historial, así el replay
+// no depende de los archivos del worker.
+func DSLWorkflow(ctx workflow.Context, input dsl.Input) (string, error) {
+	logger := workflow.GetLogger(ctx)
+
+	// Cada paso usa el perfil de la ejecución, el suyo propio o el configurado
+	// para su activity; sus Options ajustan valores puntuales encima
+	profile, err := activityProfile(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// ==========================================
+	// PASO 1: Obtener la definición del pipeline
+	// ==========================================
+	definition := input.Definition
+	if definition == nil {
+		if err := workflow.ExecuteActivity(withActivityOptions(ctx, contracts.LoadPipeline), contracts.LoadPipeline, input.Pipeline).Get(ctx, &definition); err != nil {
+			logger.Error("Failed to load pipeline", "pipeline", input.Pipeline, "error", err)
+			return "", fmt.Errorf("failed to load pipeline %s: %w", input.Pipeline, err)
+		}
+	}
+	if err := definition.Validate(); err != nil {
+		return "", temporal.NewNonRetryableApplicationError(err.Error(), "InvalidPipeline", nil)
+	}
+	// Solo se ejecutan las activities permitidas en pipelines; las
+	// ejecuciones anteriores a este control no lo aplican
+	if err := definition.ValidateActivities(); err != nil && workflow.GetVersion(ctx, "dsl-activity-allow-list", workflow.DefaultVersion, 1) == 1 {
+		return "", temporal.NewNonRetryableApplicationError(err.Error(), "InvalidPipeline", nil)
+	}
+
+	// ==========================================
+	// PASO 2: Ejecutar el pipeline
+	// ==========================================
+	executor := &dslExecutor{
+		definition: definition,
+		profile:    profile,
+		vars:       map[string]string{"input": input.Data},
+		data:       map[string]interface{}{},
+	}
+	executor.merge("", input.Data)
+
+	result, err := executor.run(ctx, &definition.Root, input.Data)
+	if err != nil {
+		logger.Error("DSLWorkflow failed", "pipeline", definition.Name, "error", err)
+		return "", err
+	}
+
+	return result, nil
+}
+
+// dslExecutor mantiene las variables y los datos acumulados del pipeline.
+// Las ramas paralelas los comparten; es seguro porque las goroutines de
+// workflow.Go se ejecutan cooperativamente, nunca a la vez.
+type dslExecutor struct {
+	definition *dsl.Definition
+	profile    string
+	vars       map[string]string
+	data       map[string]interface{}
+	children   int
+}
+
+// run ejecuta un statement a partir del resultado actual y devuelve el nuevo
+func (e *dslExecutor) run(ctx workflow.Context, stmt *dsl.Statement, current string) (string, error) {
+	if stmt.When != nil {
+		ok, err := stmt.When.Evaluate(e.data)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			workflow.GetLogger(ctx).Info("Skipping step, condition not met", "path", stmt.When.Path, "op", stmt.When.Op)
+			return current, nil
+		}
+	}
+
+	switch {
+	case stmt.Activity != nil:
+		return e.runActivity(ctx, stmt.Activity, current)
+	case stmt.Child != nil:
+		return e.runChild(ctx, stmt.Child, current)
+	case stmt.Sequence != nil:
+		for i := range stmt.Sequence {
+			var err error
+			if current, err = e.run(ctx, &stmt.Sequence[i], current); err != nil {
+				return "", err
+			}
+		}
+		return current, nil
+	case stmt.Parallel != nil:
+		return e.runParallel(ctx, stmt.Parallel, current)
+	}
+	return current, nil
+}
+
+func (e *dslExecutor) runActivity(ctx workflow.Context, step *dsl.ActivityStep, current string) (string, error) {
+	logger := workflow.GetLogger(ctx)
+
+	input, err := e.resolveInput(step.Input, step.InputTemplate, current)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", step.Name, err)
+	}
+
+	logger.Info("Executing pipeline activity", "activity", step.Name)
+	profile := e.profile
+	if profile == "" {
+		profile = step.Profile
+	}
+	options := profiles.Current().Options(profile, step.Name)
+	activityCtx := workflow.WithActivityOptions(ctx, step.Options.Apply(options))
+
+	var result string
+	if err := workflow.ExecuteActivity(activityCtx, step.Name, input).Get(activityCtx, &result); err != nil {
+		logger.Error("Pipeline activity failed", "activity", step.Name, "error", err)
+		return "", fmt.Errorf("%s failed: %w", step.Name, err)
+	}
+
+	e.merge(step.Result, result)
+	return result, nil
+}
+
+func (e *dslExecutor) runChild(ctx workflow.Context, step *dsl.ChildStep, current string) (string, error) {
+	logger := workflow.GetLogger(ctx)
+
+	child, ok := e.definition.Children[step.Pipeline]
+	if !ok {
+		return "", temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("child pipeline %s not loaded", step.Pipeline), "InvalidPipeline", nil)
+	}
+
+	input, err := e.resolveInput(step.Input, "", current)
+	if err != nil {
+		return "", fmt.Errorf("child %s: %w", step.Pipeline, err)
+	}
+
+	// El hijo recibe la definición completa, incluidos sus propios hijos
+	childDefinition := *child
+	childDefinition.Children = e.definition.Children
+
+	e.children++
+	childOptions, err := newChildWorkflowOptions(ctx, fmt.Sprintf("%s-%d", step.Pipeline, e.children))
+	if err != nil {
+		return "", err
+	}
+	childCtx := workflow.WithChildOptions(ctx, childOptions)
+
+	logger.Info("Starting child pipeline", "pipeline", step.Pipeline, "childWorkflowID", childOptions.WorkflowID)
+	var result string
+	err = workflow.ExecuteChildWorkflow(childCtx, DSLWorkflow, dsl.Input{
+		PipelineInput: contracts.PipelineInput{Pipeline: step.Pipeline, Data: input},
+		Definition:    &childDefinition,
+	}).Get(childCtx, &result)
+	if err != nil {
+		logger.Error("Child pipeline failed", "pipeline", step.Pipeline, "error", err)
+		return "", fmt.Errorf("child pipeline %s failed: %w", step.Pipeline, err)
+	}
+
+	e.merge(step.Result, result)
+	return result, nil
+}
+
+// runParallel ejecuta cada rama con el mismo input y espera a todas.
+// El resultado es un arreglo JSON con el resultado de cada rama, en orden.
+func (e *dslExecutor) runParallel(ctx workflow.Context, branches []dsl.Statement, current string) (string, error) {
+	results := make([]string, len(branches))
+	errs := make([]error, len(branches))
+	pending := len(branches)
+
+	for i := range branches {
+		i := i
+		workflow.Go(ctx, func(gCtx workflow.Context) {
+			results[i], errs[i] = e.run(gCtx, &branches[i], current)
+			pending--
+		})
+	}
+
+	if err := workflow.Await(ctx, func() bool { return pending == 0 }); err != nil {
+		return "", err
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			return "", fmt.Errorf("parallel branch %d: %w", i, err)
+		}
+	}
+
+	combined, err := json.Marshal(results)
+	if err != nil {
+		return "", err
+	}
+	return string(combined), nil
+}
+
+// templateFuncs son las funciones disponibles en InputTemplate
+var templateFuncs = template.FuncMap{
+	// consolidate combina resultados en un objeto JSON, como WorkflowD
+	"consolidate": consolidateResults,
+}
+
+// resolveInput elige el input de un paso: plantilla, variable o resultado actual
+func (e *dslExecutor) resolveInput(variable, tmpl, current string) (string, error) {
+	if tmpl != "" {
+		t, err := template.New("input").Option("missingkey=error").Funcs(templateFuncs).Parse(tmpl)
+		if err != nil {
+			return "", fmt.Errorf("invalid input template: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, e.vars); err != nil {
+			return "", fmt.Errorf("failed to render input template: %w", err)
+		}
+		return buf.String(), nil
+	}
+
+	if variable != "" {
+		value, ok := e.vars[variable]
+		if !ok {
+			return "", fmt.Errorf("unknown variable %q", variable)
+		}
+		return value, nil
+	}
+	return current, nil
+}
+
+// merge guarda un resultado: como variable si tiene nombre y, si es un
+// objeto JSON, sus campos pasan a los datos acumulados para las condiciones
+func (e *dslExecutor) merge(name, result string) {
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(result), &parsed); err != nil {
+		parsed = result
+	}
+
+	if object, ok := parsed.(map[string]interface{}); ok {
+		for k, v := range object {
+			e.data[k] = v
+		}
+	}
+	if name != "" {
+		e.vars[name] = result
+		e.data[name] = parsed
+	}
+}
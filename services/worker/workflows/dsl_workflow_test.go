@@ -0,0 +1,130 @@
+package workflows
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/worker/activities"
+	"github.com/temporal-aws-poc/worker/dsl"
+)
+
+// loadPipeline lee una de las definiciones empaquetadas con el worker
+func (s *WorkflowsTestSuite) loadPipeline(name string) *dsl.Definition {
+	definition, err := dsl.NewLoader("../pipelines").Load(name)
+	s.Require().NoError(err)
+	return definition
+}
+
+func (s *WorkflowsTestSuite) Test_DSLWorkflow_LoadsDefinitionByName() {
+	s.env.RegisterActivity(activities.NewPipelineActivities(dsl.NewLoader("../pipelines")))
+	s.env.OnActivity("Activity1", mock.Anything, `{"a":1}`).Return(`{"activity1_processed":true}`, nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, `{"activity1_processed":true}`).Return("processed", nil).Once()
+
+	s.env.ExecuteWorkflow(DSLWorkflow, dsl.Input{PipelineInput: contracts.PipelineInput{Pipeline: "workflow-c", Data: `{"a":1}`}})
+
+	s.NoError(s.env.GetWorkflowError())
+	var result string
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal("processed", result)
+}
+
+func (s *WorkflowsTestSuite) Test_DSLWorkflow_ConditionSkipsStep() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return(`{"activity1_processed":false}`, nil).Once()
+
+	s.env.ExecuteWorkflow(DSLWorkflow, dsl.Input{PipelineInput: contracts.PipelineInput{Data: "input"}, Definition: s.loadPipeline("workflow-c")})
+
+	s.NoError(s.env.GetWorkflowError())
+	var result string
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(`{"activity1_processed":false}`, result)
+}
+
+func (s *WorkflowsTestSuite) Test_DSLWorkflow_ParallelAndTemplate() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("r1", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "input").Return("r2", nil).Once()
+	s.env.OnActivity("Activity4", mock.Anything, "input").Return("r4", nil).Once()
+	s.env.OnActivity("Activity3", mock.Anything, `{"raw_results":["r1","r2","r4"]}`).Return("final", nil).Once()
+
+	s.env.ExecuteWorkflow(DSLWorkflow, dsl.Input{PipelineInput: contracts.PipelineInput{Data: "input"}, Definition: s.loadPipeline("workflow-d")})
+
+	s.NoError(s.env.GetWorkflowError())
+	var result string
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal("final", result)
+}
+
+// El pipeline workflow-d arma para Activity3 el mismo input que WorkflowD,
+// y la activity real lo acepta
+func (s *WorkflowsTestSuite) Test_DSLWorkflow_WorkflowDMatchesGoWorkflow() {
+	input := `{"message":"hola"}`
+	results := []string{
+		`{"message":"hola","activity1_processed":true}`,
+		`{"message":"hola","activity2_processed":true}`,
+		`{"message":"hola","activity4_processed":true}`,
+	}
+	s.env.OnActivity("Activity1", mock.Anything, input).Return(results[0], nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, input).Return(results[1], nil).Once()
+	s.env.OnActivity("Activity4", mock.Anything, input).Return(results[2], nil).Once()
+	consolidated, err := consolidateResults(results...)
+	s.NoError(err)
+	var activity3Input string
+	s.env.SetOnActivityStartedListener(func(info *activity.Info, ctx context.Context, args converter.EncodedValues) {
+		if info.ActivityType.Name == "Activity3" {
+			s.NoError(args.Get(&activity3Input))
+		}
+	})
+
+	s.env.ExecuteWorkflow(DSLWorkflow, dsl.Input{PipelineInput: contracts.PipelineInput{Data: input}, Definition: s.loadPipeline("workflow-d")})
+
+	s.NoError(s.env.GetWorkflowError())
+	s.JSONEq(consolidated, activity3Input)
+	var result string
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Contains(result, `"final_status":"SUCCESS"`)
+}
+
+func (s *WorkflowsTestSuite) Test_DSLWorkflow_ChildPipeline() {
+	s.env.RegisterWorkflow(DSLWorkflow)
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("result1", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "result1").Return("result2", nil).Once()
+	s.env.OnActivity("Activity4", mock.Anything, "result2").Return("result4", nil).Once()
+	s.env.OnActivity("Activity3", mock.Anything, "result4").Return("final", nil).Once()
+
+	s.env.ExecuteWorkflow(DSLWorkflow, dsl.Input{PipelineInput: contracts.PipelineInput{Data: "input"}, Definition: s.loadPipeline("workflow-a")})
+
+	s.NoError(s.env.GetWorkflowError())
+	var result string
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal("final", result)
+}
+
+func (s *WorkflowsTestSuite) Test_DSLWorkflow_ParallelBranchFails() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("r1", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "input").Return("", errors.New("boom")).Times(3)
+	s.env.OnActivity("Activity4", mock.Anything, "input").Return("r4", nil).Once()
+
+	s.env.ExecuteWorkflow(DSLWorkflow, dsl.Input{PipelineInput: contracts.PipelineInput{Data: "input"}, Definition: s.loadPipeline("workflow-d")})
+
+	err := s.env.GetWorkflowError()
+	s.Error(err)
+	s.Contains(err.Error(), "parallel branch 1")
+}
+
+func (s *WorkflowsTestSuite) Test_DSLWorkflow_RejectsActivityOutsideAllowList() {
+	definition := &dsl.Definition{
+		Name: "compensate",
+		Root: dsl.Statement{Activity: &dsl.ActivityStep{Name: contracts.CompensateActivity1}},
+	}
+
+	s.env.ExecuteWorkflow(DSLWorkflow, dsl.Input{PipelineInput: contracts.PipelineInput{Data: "input"}, Definition: definition})
+
+	s.True(s.env.IsWorkflowCompleted())
+	err := s.env.GetWorkflowError()
+	s.Require().Error(err)
+	s.Contains(err.Error(), `activity "CompensateActivity1" is not allowed in pipelines`)
+}
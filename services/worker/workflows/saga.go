@@ -0,0 +1,94 @@
+package workflows
+
+import (
+	"fmt"
+	"strings"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// SagaFailureErrorType es el tipo del ApplicationError que devuelve Saga.Fail
+const SagaFailureErrorType = "SagaFailure"
+
+// CompensationResult describe el resultado de una compensación ejecutada
+type CompensationResult struct {
+	Activity  string `json:"activity"`
+	Succeeded bool   `json:"succeeded"`
+	Error     string `json:"error,omitempty"`
+}
+
+// compensation es una activity de compensación registrada por un paso exitoso
+type compensation struct {
+	activity string
+	args     []interface{}
+}
+
+// Saga acumula compensaciones a medida que los pasos de un workflow terminan
+// bien y las ejecuta en orden inverso cuando un paso posterior falla
+type Saga struct {
+	compensations []compensation
+}
+
+// NewSaga crea una saga vacía. Cada compensación se ejecuta con las
+// ActivityOptions de su activity (perfil y task queue), como cualquier paso.
+func NewSaga() *Saga {
+	return &Saga{}
+}
+
+// AddCompensation registra la activity que deshace el paso que acaba de completarse
+func (s *Saga) AddCompensation(activity string, args ...interface{}) {
+	s.compensations = append(s.compensations, compensation{
+		activity: activity,
+		args:     args,
+	})
+}
+
+// Compensate ejecuta las compensaciones registradas en orden inverso.
+// Usa un contexto desconectado para que también corran si el workflow fue
+// cancelado, y continúa con las siguientes aunque alguna falle.
+func (s *Saga) Compensate(ctx workflow.Context) []CompensationResult {
+	logger := workflow.GetLogger(ctx)
+
+	compensationCtx, _ := workflow.NewDisconnectedContext(ctx)
+
+	results := make([]CompensationResult, 0, len(s.compensations))
+	for i := len(s.compensations) - 1; i >= 0; i-- {
+		c := s.compensations[i]
+		logger.Info("Running compensation", "activity", c.activity)
+
+		result := CompensationResult{Activity: c.activity, Succeeded: true}
+		activityCtx := withActivityOptions(compensationCtx, c.activity)
+		if err := workflow.ExecuteActivity(activityCtx, c.activity, c.args...).Get(activityCtx, nil); err != nil {
+			logger.Error("Compensation failed", "activity", c.activity, "error", err)
+			result.Succeeded = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	s.compensations = nil
+	return results
+}
+
+// Fail compensa los pasos completados y devuelve el error final del workflow,
+// que incluye qué compensaciones corrieron y si terminaron bien
+func (s *Saga) Fail(ctx workflow.Context, cause error) error {
+	if len(s.compensations) == 0 {
+		return cause
+	}
+
+	results := s.Compensate(ctx)
+
+	summary := make([]string, 0, len(results))
+	for _, r := range results {
+		status := "ok"
+		if !r.Succeeded {
+			status = "failed"
+		}
+		summary = append(summary, fmt.Sprintf("%s: %s", r.Activity, status))
+	}
+
+	message := fmt.Sprintf("%v; compensations: [%s]", cause, strings.Join(summary, ", "))
+	return temporal.NewApplicationErrorWithCause(message, SagaFailureErrorType, cause, results)
+}
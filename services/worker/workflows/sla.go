@@ -0,0 +1,194 @@
+package workflows
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/worker/activities"
+)
+
+// SLAReport es el resultado del SLA que se agrega al resultado del workflow.
+// Margin es positivo si terminó antes del plazo y negativo si se pasó.
+type SLAReport struct {
+	Duration    string    `json:"duration"`
+	DeadlineAt  time.Time `json:"deadlineAt"`
+	CompletedAt time.Time `json:"completedAt"`
+	Met         bool      `json:"met"`
+	Margin      string    `json:"margin"`
+	Escalated   bool      `json:"escalated"`
+}
+
+// SLAErrorDetails son los detalles del error de un workflow con SLA que
+// termina fallando: el reporte y los detalles del error original
+type SLAErrorDetails struct {
+	SLA     SLAReport   `json:"sla"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// slaTracker corre un timer durable en paralelo a los pasos del workflow.
+// Si vence, ejecuta la activity Escalate pero no interrumpe la ejecución.
+type slaTracker struct {
+	duration    time.Duration
+	deadline    time.Time
+	cancelTimer workflow.CancelFunc
+	done        bool
+	escalated   bool
+}
+
+// startSLA arranca el tracker si el caller envió un SLA en el memo.
+// Sin SLA no se generan comandos, así las ejecuciones previas a este
+// cambio siguen siendo deterministas sin necesidad de GetVersion.
+func startSLA(ctx workflow.Context) (*slaTracker, error) {
+	policy, ok := slaPolicy(workflow.GetInfo(ctx))
+	if !ok {
+		return nil, nil
+	}
+	return newSLATracker(ctx, policy)
+}
+
+// newSLATracker arranca el timer del SLA a partir del inicio de la ejecución
+func newSLATracker(ctx workflow.Context, policy contracts.SLAPolicy) (*slaTracker, error) {
+	duration, err := time.ParseDuration(policy.Duration)
+	if err != nil || duration <= 0 {
+		return nil, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("invalid SLA duration %q", policy.Duration), contracts.ValidationErrorType, nil)
+	}
+
+	info := workflow.GetInfo(ctx)
+	t := &slaTracker{
+		duration: duration,
+		deadline: info.WorkflowStartTime.Add(duration),
+	}
+
+	timerCtx, cancelTimer := workflow.WithCancel(ctx)
+	t.cancelTimer = cancelTimer
+
+	workflow.Go(timerCtx, func(gCtx workflow.Context) {
+		defer func() { t.done = true }()
+
+		if remaining := t.deadline.Sub(workflow.Now(gCtx)); remaining > 0 {
+			if err := workflow.NewTimer(gCtx, remaining).Get(gCtx, nil); err != nil {
+				// Timer cancelado: el workflow terminó antes del plazo
+				return
+			}
+		}
+
+		logger := workflow.GetLogger(gCtx)
+		logger.Warn("SLA breached, escalating", "sla", policy.Duration, "deadline", t.deadline)
+		t.escalated = true
+		escalation := activities.Escalation{
+			Kind:       "sla",
+			WorkflowID: info.WorkflowExecution.ID,
+			RunID:      info.WorkflowExecution.RunID,
+			Message:    fmt.Sprintf("%s exceeded its SLA of %s (deadline %s)", info.WorkflowType.Name, policy.Duration, t.deadline.Format(time.RFC3339)),
+		}
+		err := workflow.ExecuteActivity(withActivityOptions(gCtx, contracts.Escalate), contracts.Escalate, escalation).Get(gCtx, nil)
+		switch {
+		case temporal.IsCanceledError(err):
+			logger.Warn("SLA escalation canceled, workflow finished first")
+		case err != nil:
+			logger.Error("SLA escalation failed", "error", err)
+		}
+	})
+	return t, nil
+}
+
+// finish detiene el timer, cancela una escalación en curso y arma el reporte.
+// La escalación no retiene el cierre del workflow: su activity reintenta
+// con el perfil de Escalate y podría tardar mucho más que el propio workflow.
+func (t *slaTracker) finish(ctx workflow.Context) SLAReport {
+	// Las ejecuciones anteriores a este cambio esperaban la escalación
+	if !t.escalated || t.done || workflow.GetVersion(ctx, "sla-cancel-escalation", workflow.DefaultVersion, 1) == 1 {
+		t.cancelTimer()
+	}
+	_ = workflow.Await(ctx, func() bool { return t.done })
+
+	completedAt := workflow.Now(ctx)
+	margin := t.deadline.Sub(completedAt)
+	return SLAReport{
+		Duration:    t.duration.String(),
+		DeadlineAt:  t.deadline,
+		CompletedAt: completedAt,
+		Met:         margin >= 0,
+		Margin:      margin.String(),
+		Escalated:   t.escalated,
+	}
+}
+
+// complete cierra el SLA y agrega el reporte al resultado del workflow.
+// Sin SLA (t nil) devuelve el resultado sin cambios.
+func (t *slaTracker) complete(ctx workflow.Context, result string) (string, error) {
+	if t == nil {
+		return result, nil
+	}
+	report := t.finish(ctx)
+	workflow.GetLogger(ctx).Info("SLA evaluated", "met", report.Met, "margin", report.Margin, "escalated", report.Escalated)
+	return withSLAReport(result, report)
+}
+
+// fail cierra el SLA de un workflow que termina con err y agrega el reporte
+// a los detalles del error. Conserva el tipo, el mensaje y los detalles del
+// ApplicationError que err envuelve; una cancelación se devuelve sin cambios para que la
+// ejecución siga terminando como Canceled.
+func (t *slaTracker) fail(ctx workflow.Context, err error) error {
+	if t == nil || err == nil || temporal.IsCanceledError(err) {
+		return err
+	}
+	report := t.finish(ctx)
+	workflow.GetLogger(ctx).Info("SLA evaluated on failure", "met", report.Met, "margin", report.Margin, "escalated", report.Escalated)
+
+	details := SLAErrorDetails{SLA: report}
+	errType := ""
+	nonRetryable := false
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) {
+		errType = appErr.Type()
+		nonRetryable = appErr.NonRetryable()
+		if appErr.HasDetails() {
+			_ = appErr.Details(&details.Details)
+		}
+	}
+	if nonRetryable {
+		return temporal.NewNonRetryableApplicationError(err.Error(), errType, err, details)
+	}
+	return temporal.NewApplicationErrorWithCause(err.Error(), errType, err, details)
+}
+
+// withSLAReport agrega el reporte al resultado JSON del workflow bajo la
+// clave "sla"; si el resultado no es un objeto JSON lo envuelve en "result"
+func withSLAReport(result string, report SLAReport) (string, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(result), &data); err != nil || data == nil {
+		data = map[string]interface{}{"result": result}
+	}
+	data["sla"] = report
+
+	combined, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(combined), nil
+}
+
+// slaPolicy lee la política del memo; ok es false si el caller no envió SLA
+func slaPolicy(info *workflow.Info) (contracts.SLAPolicy, bool) {
+	if info.Memo == nil {
+		return contracts.SLAPolicy{}, false
+	}
+	payload, ok := info.Memo.GetFields()[contracts.SLAMemoKey]
+	if !ok {
+		return contracts.SLAPolicy{}, false
+	}
+
+	var policy contracts.SLAPolicy
+	if err := contracts.DataConverter().FromPayload(payload, &policy); err != nil || policy.Duration == "" {
+		return contracts.SLAPolicy{}, false
+	}
+	return policy, true
+}
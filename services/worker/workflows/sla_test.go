@@ -0,0 +1,143 @@
+package workflows
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
+	"github.com/temporal-aws-poc/worker/activities"
+)
+
+// slaTestWorkflow espera step y aplica el SLA como lo hacen WorkflowA y WorkflowC
+// (el entorno de pruebas no propaga el memo, así que la política llega por input)
+func slaTestWorkflow(ctx workflow.Context, policy contracts.SLAPolicy, step time.Duration) (string, error) {
+	sla, err := newSLATracker(ctx, policy)
+	if err != nil {
+		return "", err
+	}
+	if err := workflow.Sleep(ctx, step); err != nil {
+		return "", err
+	}
+	return sla.complete(ctx, `{"final_status":"SUCCESS"}`)
+}
+
+func (s *WorkflowsTestSuite) executeSLA(policy contracts.SLAPolicy, step time.Duration) map[string]interface{} {
+	s.env.RegisterWorkflow(slaTestWorkflow)
+	s.env.ExecuteWorkflow(slaTestWorkflow, policy, step)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Require().NoError(s.env.GetWorkflowError())
+
+	var result string
+	s.Require().NoError(s.env.GetWorkflowResult(&result))
+	var data map[string]interface{}
+	s.Require().NoError(json.Unmarshal([]byte(result), &data))
+	s.Equal("SUCCESS", data["final_status"])
+	return data["sla"].(map[string]interface{})
+}
+
+func (s *WorkflowsTestSuite) Test_SLA_Met() {
+	report := s.executeSLA(contracts.SLAPolicy{Duration: "1h"}, 20*time.Minute)
+
+	s.Equal(true, report["met"])
+	s.Equal("40m0s", report["margin"])
+	s.Equal(false, report["escalated"])
+}
+
+func (s *WorkflowsTestSuite) Test_SLA_BreachedEscalatesWithoutAborting() {
+	s.env.OnActivity("Escalate", mock.Anything, mock.MatchedBy(func(e activities.Escalation) bool {
+		return e.Kind == "sla"
+	})).Return(nil).Once()
+
+	report := s.executeSLA(contracts.SLAPolicy{Duration: "30m"}, time.Hour)
+
+	s.Equal(false, report["met"])
+	s.Equal("-30m0s", report["margin"])
+	s.Equal(true, report["escalated"])
+}
+
+func (s *WorkflowsTestSuite) Test_SLA_SlowEscalationDoesNotDelayCompletion() {
+	s.env.OnActivity("Escalate", mock.Anything, mock.Anything).After(24 * time.Hour).Return(nil)
+
+	report := s.executeSLA(contracts.SLAPolicy{Duration: "30m"}, time.Hour)
+
+	s.Equal(false, report["met"])
+	s.Equal("-30m0s", report["margin"])
+	s.Equal(true, report["escalated"])
+}
+
+// slaFailingTestWorkflow falla después de step con un error de validación,
+// envuelto como lo hacen los workflows si wrap
+func slaFailingTestWorkflow(ctx workflow.Context, policy contracts.SLAPolicy, step time.Duration, wrap bool) (err error) {
+	sla, err := newSLATracker(ctx, policy)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = sla.fail(ctx, err)
+		}
+	}()
+	if err := workflow.Sleep(ctx, step); err != nil {
+		return err
+	}
+	err = temporal.NewNonRetryableApplicationError("invalid input", contracts.ValidationErrorType, nil, "field")
+	if wrap {
+		err = fmt.Errorf("Activity2 failed: %w", err)
+	}
+	return err
+}
+
+func (s *WorkflowsTestSuite) Test_SLA_FailureCarriesReport() {
+	s.assertSLAFailure(false)
+}
+
+func (s *WorkflowsTestSuite) Test_SLA_WrappedFailureKeepsApplicationError() {
+	s.assertSLAFailure(true)
+}
+
+func (s *WorkflowsTestSuite) assertSLAFailure(wrap bool) {
+	s.env.OnActivity("Escalate", mock.Anything, mock.Anything).Return(nil).Once()
+
+	s.env.RegisterWorkflow(slaFailingTestWorkflow)
+	s.env.ExecuteWorkflow(slaFailingTestWorkflow, contracts.SLAPolicy{Duration: "30m"}, time.Hour, wrap)
+
+	s.True(s.env.IsWorkflowCompleted())
+	err := s.env.GetWorkflowError()
+	s.Require().Error(err)
+
+	var appErr *temporal.ApplicationError
+	s.Require().ErrorAs(err, &appErr)
+	s.Equal(contracts.ValidationErrorType, appErr.Type())
+	s.True(appErr.NonRetryable())
+
+	var details SLAErrorDetails
+	s.Require().NoError(appErr.Details(&details))
+	s.False(details.SLA.Met)
+	s.True(details.SLA.Escalated)
+	s.Equal("-30m0s", details.SLA.Margin)
+	s.Equal("field", details.Details)
+}
+
+func (s *WorkflowsTestSuite) Test_SLA_InvalidDuration() {
+	s.env.RegisterWorkflow(slaTestWorkflow)
+	s.env.ExecuteWorkflow(slaTestWorkflow, contracts.SLAPolicy{Duration: "soon"}, time.Minute)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+}
+
+func (s *WorkflowsTestSuite) Test_WithSLAReport_WrapsNonObjectResults() {
+	result, err := withSLAReport("plain", SLAReport{Duration: "1m0s", Met: true, Margin: "10s"})
+	s.Require().NoError(err)
+
+	var data map[string]interface{}
+	s.Require().NoError(json.Unmarshal([]byte(result), &data))
+	s.Equal("plain", data["result"])
+	s.Contains(data, "sla")
+}
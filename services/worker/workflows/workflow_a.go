@@ -6,63 +6,110 @@ import (
 
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
 )
 
 // WorkflowA es el workflow principal que orquesta múltiples activities
 // y ejecuta un workflow hijo (WorkflowB)
-func WorkflowA(ctx workflow.Context, input string) (string, error) {
+func WorkflowA(ctx workflow.Context, input string) (result string, err error) {
 	logger := workflow.GetLogger(ctx)
-	logger.Info("WorkflowA started", "input", input)
-
-	// Configurar opciones de activities
-	activityOptions := workflow.ActivityOptions{
-		StartToCloseTimeout: 30 * time.Second,
-		RetryPolicy: &temporal.RetryPolicy{
-			InitialInterval:    time.Second,
-			BackoffCoefficient: 2.0,
-			MaximumInterval:    time.Minute,
-			MaximumAttempts:    3,
-		},
+
+	// Las ActivityOptions salen del perfil de la ejecución (ver paquete profiles)
+	if _, err := activityProfile(ctx); err != nil {
+		return "", err
+	}
+
+	// SLA opcional: un timer durable mide el plazo en paralelo a los pasos
+	sla, err := startSLA(ctx)
+	if err != nil {
+		return "", err
+	}
+	// Si la ejecución falla, el error lleva también el reporte del SLA
+	defer func() {
+		if err != nil {
+			err = sla.fail(ctx, err)
+		}
+	}()
+
+	// Cada paso exitoso registra su compensación; si un paso posterior falla
+	// (o el workflow se cancela) se deshacen en orden inverso
+	saga := NewSaga()
+
+	// Estado de la aprobación manual, consultable en cualquier momento
+	approval := &contracts.ApprovalState{Status: contracts.ApprovalNotRequired}
+	err = workflow.SetQueryHandler(ctx, contracts.ApprovalQueryName, func() (contracts.ApprovalState, error) {
+		return *approval, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to register approval query: %w", err)
 	}
-	ctx = workflow.WithActivityOptions(ctx, activityOptions)
 
 	// ==========================================
 	// PASO 1: Ejecutar Activity1
 	// ==========================================
 	logger.Info("Executing Activity1...")
 	var result1 string
-	err := workflow.ExecuteActivity(ctx, "Activity1", input).Get(ctx, &result1)
+	err = workflow.ExecuteActivity(withActivityOptions(ctx, contracts.Activity1), contracts.Activity1, input).Get(ctx, &result1)
 	if err != nil {
 		logger.Error("Activity1 failed", "error", err)
 		return "", fmt.Errorf("Activity1 failed: %w", err)
 	}
 	logger.Info("Activity1 completed", "result", result1)
+	saga.AddCompensation(contracts.CompensateActivity1, result1)
 
 	// ==========================================
 	// PASO 2: Ejecutar Activity2
 	// ==========================================
 	logger.Info("Executing Activity2...")
 	var result2 string
-	err = workflow.ExecuteActivity(ctx, "Activity2", result1).Get(ctx, &result2)
+	err = workflow.ExecuteActivity(withActivityOptions(ctx, contracts.Activity2), contracts.Activity2, result1).Get(ctx, &result2)
 	if err != nil {
 		logger.Error("Activity2 failed", "error", err)
-		return "", fmt.Errorf("Activity2 failed: %w", err)
+		return "", saga.Fail(ctx, fmt.Errorf("Activity2 failed: %w", err))
 	}
 	logger.Info("Activity2 completed", "result", result2)
+	saga.AddCompensation(contracts.CompensateActivity2, result2)
+
+	// ==========================================
+	// PASO 2b: Aprobación manual para inputs de alto valor
+	// ==========================================
+	policy, optedIn := approvalPolicy(workflow.GetInfo(ctx))
+	if required, value := requiresApproval(policy, result2); required {
+		// Las ejecuciones iniciadas antes de este paso no esperan aprobación;
+		// solo la piden las que envían la política en el memo
+		version := workflow.GetVersion(ctx, "approval-gate", workflow.DefaultVersion, 1)
+		if version == 1 && optedIn {
+			logger.Info("Waiting for manual approval", "field", policy.Field, "value", value)
+			approval.Field = policy.Field
+			approval.Value = value
+			if err := awaitApproval(ctx, approval, policy); err != nil {
+				logger.Error("Approval failed", "error", err)
+				return "", saga.Fail(ctx, fmt.Errorf("Approval failed: %w", err))
+			}
+		}
+	}
 
 	// ==========================================
 	// PASO 3: Ejecutar Child Workflow (WorkflowB)
 	// ==========================================
 	logger.Info("Starting child workflow (WorkflowB)...")
 
-	childWorkflowOptions := workflow.ChildWorkflowOptions{
-		WorkflowID:          fmt.Sprintf("workflow-b-child-%d", workflow.Now(ctx).Unix()),
-		TaskQueue:           "hello-world-queue",
-		WorkflowRunTimeout:  5 * time.Minute,
-		WorkflowTaskTimeout: 1 * time.Minute,
-		RetryPolicy: &temporal.RetryPolicy{
-			MaximumAttempts: 3,
-		},
+	// ID derivado del padre (ID + run + paso) y task queue heredada del padre
+	childWorkflowOptions, err := newChildWorkflowOptions(ctx, "workflow-b")
+	if err != nil {
+		logger.Error("Invalid child workflow options", "error", err)
+		return "", saga.Fail(ctx, fmt.Errorf("Child workflow options failed: %w", err))
+	}
+	// Las ejecuciones iniciadas antes de los IDs derivados conservan el ID
+	// original basado en la hora del workflow
+	if workflow.GetVersion(ctx, "child-workflow-id", workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		childWorkflowOptions.WorkflowID = fmt.Sprintf("workflow-b-child-%d", workflow.Now(ctx).Unix())
+	}
+	childWorkflowOptions.WorkflowRunTimeout = 5 * time.Minute
+	childWorkflowOptions.WorkflowTaskTimeout = 1 * time.Minute
+	childWorkflowOptions.RetryPolicy = &temporal.RetryPolicy{
+		MaximumAttempts: 3,
 	}
 
 	childCtx := workflow.WithChildOptions(ctx, childWorkflowOptions)
@@ -74,7 +121,7 @@ func WorkflowA(ctx workflow.Context, input string) (string, error) {
 	err = childWorkflowFuture.Get(childCtx, &childResult)
 	if err != nil {
 		logger.Error("Child workflow (WorkflowB) failed", "error", err)
-		return "", fmt.Errorf("Child workflow failed: %w", err)
+		return "", saga.Fail(ctx, fmt.Errorf("Child workflow failed: %w", err))
 	}
 
 	// Obtener información del child workflow
@@ -84,22 +131,25 @@ func WorkflowA(ctx workflow.Context, input string) (string, error) {
 		"childWorkflowID", childExecution.ID,
 		"childRunID", childExecution.RunID,
 		"result", childResult)
+	saga.AddCompensation(contracts.CompensateActivity4, childResult)
 
 	// ==========================================
 	// PASO 4: Ejecutar Activity3 (actividad final)
 	// ==========================================
 	logger.Info("Executing Activity3 (final activity)...")
 	var finalResult string
-	err = workflow.ExecuteActivity(ctx, "Activity3", childResult).Get(ctx, &finalResult)
+	err = workflow.ExecuteActivity(withActivityOptions(ctx, contracts.Activity3), contracts.Activity3, childResult).Get(ctx, &finalResult)
 	if err != nil {
 		logger.Error("Activity3 failed", "error", err)
-		return "", fmt.Errorf("Activity3 failed: %w", err)
+		return "", saga.Fail(ctx, fmt.Errorf("Activity3 failed: %w", err))
 	}
 	logger.Info("Activity3 completed", "result", finalResult)
 
 	// ==========================================
 	// Workflow completado exitosamente
 	// ==========================================
-	logger.Info("WorkflowA completed successfully", "finalResult", finalResult)
+	if finalResult, err = sla.complete(ctx, finalResult); err != nil {
+		return "", err
+	}
 	return finalResult, nil
 }
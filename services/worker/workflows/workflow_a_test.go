@@ -0,0 +1,157 @@
+package workflows
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/worker/profiles"
+)
+
+// testRunID es el run ID que asigna el entorno de pruebas del SDK
+const testRunID = "default-test-run-id"
+
+func (s *WorkflowsTestSuite) Test_WorkflowA_Success() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("result1", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "result1").Return("result2", nil).Once()
+	s.env.OnActivity("Activity4", mock.Anything, "result2").Return("result4", nil).Once()
+	s.env.OnActivity("Activity3", mock.Anything, "result4").Return("final", nil).Once()
+
+	s.env.ExecuteWorkflow(WorkflowA, "input")
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result string
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal("final", result)
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowA_ChildUsesDerivedID() {
+	s.env.SetStartWorkflowOptions(client.StartWorkflowOptions{ID: "order-42", TaskQueue: "orders-queue"})
+
+	var childID, childQueue string
+	s.env.OnActivity("Activity1", mock.Anything, mock.Anything).Return("result1", nil)
+	s.env.OnActivity("Activity2", mock.Anything, mock.Anything).Return("result2", nil)
+	s.env.OnActivity("Activity3", mock.Anything, mock.Anything).Return("final", nil)
+	s.env.OnWorkflow(WorkflowB, mock.Anything, "result2").Return(func(ctx workflow.Context, input string) (string, error) {
+		info := workflow.GetInfo(ctx)
+		childID = info.WorkflowExecution.ID
+		childQueue = info.TaskQueueName
+		return "result4", nil
+	})
+
+	s.env.ExecuteWorkflow(WorkflowA, "input")
+
+	s.NoError(s.env.GetWorkflowError())
+	s.Equal("order-42-workflow-b-"+testRunID, childID)
+	s.Equal("orders-queue", childQueue)
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowA_RetriesTransientFailure() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("", errors.New("transient")).Once()
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("result1", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "result1").Return("result2", nil).Once()
+	s.env.OnActivity("Activity4", mock.Anything, "result2").Return("result4", nil).Once()
+	s.env.OnActivity("Activity3", mock.Anything, "result4").Return("final", nil).Once()
+
+	s.env.ExecuteWorkflow(WorkflowA, "input")
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowA_Activity1FailsWithoutCompensations() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("", errors.New("boom")).Times(3)
+
+	s.env.ExecuteWorkflow(WorkflowA, "input")
+
+	s.True(s.env.IsWorkflowCompleted())
+	err := s.env.GetWorkflowError()
+	s.Error(err)
+	s.Contains(err.Error(), "Activity1 failed")
+
+	var appErr *temporal.ApplicationError
+	s.True(errors.As(err, &appErr))
+	s.NotEqual(SagaFailureErrorType, appErr.Type())
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowA_ChildFailureCompensates() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("result1", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "result1").Return("result2", nil).Once()
+	s.env.OnActivity("Activity4", mock.Anything, "result2").Return("", errors.New("child boom"))
+	s.env.OnActivity("CompensateActivity2", mock.Anything, "result2").Return(nil).Once()
+	s.env.OnActivity("CompensateActivity1", mock.Anything, "result1").Return(nil).Once()
+
+	s.env.ExecuteWorkflow(WorkflowA, "input")
+
+	s.True(s.env.IsWorkflowCompleted())
+	err := s.env.GetWorkflowError()
+	s.Error(err)
+	s.Contains(err.Error(), "Child workflow failed")
+	s.Contains(err.Error(), "CompensateActivity2: ok, CompensateActivity1: ok")
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowA_Activity3FailureCompensatesInReverse() {
+	var order []string
+	record := func(name string) func(ctx context.Context, result string) error {
+		return func(ctx context.Context, result string) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("result1", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "result1").Return("result2", nil).Once()
+	s.env.OnActivity("Activity4", mock.Anything, "result2").Return("result4", nil).Once()
+	s.env.OnActivity("Activity3", mock.Anything, "result4").Return("", errors.New("final boom")).Times(3)
+	s.env.OnActivity("CompensateActivity4", mock.Anything, "result4").Return(record("CompensateActivity4")).Once()
+	s.env.OnActivity("CompensateActivity2", mock.Anything, "result2").Return(errors.New("cannot undo")).Times(5)
+	s.env.OnActivity("CompensateActivity1", mock.Anything, "result1").Return(record("CompensateActivity1")).Once()
+
+	s.env.ExecuteWorkflow(WorkflowA, "input")
+
+	s.True(s.env.IsWorkflowCompleted())
+	err := s.env.GetWorkflowError()
+	s.Error(err)
+
+	var appErr *temporal.ApplicationError
+	s.Require().True(errors.As(err, &appErr))
+	s.Equal(SagaFailureErrorType, appErr.Type())
+
+	var results []CompensationResult
+	s.Require().NoError(appErr.Details(&results))
+	s.Len(results, 3)
+	s.Equal("CompensateActivity4", results[0].Activity)
+	s.True(results[0].Succeeded)
+	s.Equal("CompensateActivity2", results[1].Activity)
+	s.False(results[1].Succeeded)
+	s.Equal("CompensateActivity1", results[2].Activity)
+	s.True(results[2].Succeeded)
+	s.Equal([]string{"CompensateActivity4", "CompensateActivity1"}, order)
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowA_CompensationsFollowTaskQueueRouting() {
+	profiles.Current().SetTaskQueues(map[string]string{"CompensateActivity1": "compensations-queue"})
+	defer profiles.Current().SetTaskQueues(nil)
+
+	taskQueues := map[string]string{}
+	s.env.SetOnActivityStartedListener(func(info *activity.Info, ctx context.Context, args converter.EncodedValues) {
+		taskQueues[info.ActivityType.Name] = info.TaskQueue
+	})
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("result1", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "result1").Return("", temporal.NewNonRetryableApplicationError("boom", "Permanent", nil)).Once()
+	s.env.OnActivity("CompensateActivity1", mock.Anything, "result1").Return(nil).Once()
+
+	s.env.ExecuteWorkflow(WorkflowA, "input")
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+	s.Equal("compensations-queue", taskQueues["CompensateActivity1"])
+}
@@ -2,36 +2,40 @@ package workflows
 
 import (
 	"fmt"
-	"time"
 
-	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
 )
 
 // WorkflowC es un workflow de validación simple
 // Ejecuta activities secuenciales para validar y procesar datos
-func WorkflowC(ctx workflow.Context, input string) (string, error) {
+func WorkflowC(ctx workflow.Context, input string) (result string, err error) {
 	logger := workflow.GetLogger(ctx)
-	logger.Info("WorkflowC (validation) started", "input", input)
-
-	// Configurar opciones de activities
-	activityOptions := workflow.ActivityOptions{
-		StartToCloseTimeout: 30 * time.Second,
-		RetryPolicy: &temporal.RetryPolicy{
-			InitialInterval:    time.Second,
-			BackoffCoefficient: 2.0,
-			MaximumInterval:    time.Minute,
-			MaximumAttempts:    3,
-		},
+
+	// Las ActivityOptions salen del perfil de la ejecución (ver paquete profiles)
+	if _, err := activityProfile(ctx); err != nil {
+		return "", err
+	}
+
+	// SLA opcional: un timer durable mide el plazo en paralelo a los pasos
+	sla, err := startSLA(ctx)
+	if err != nil {
+		return "", err
 	}
-	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+	// Si la ejecución falla, el error lleva también el reporte del SLA
+	defer func() {
+		if err != nil {
+			err = sla.fail(ctx, err)
+		}
+	}()
 
 	// ==========================================
 	// PASO 1: Validar input con Activity1
 	// ==========================================
 	logger.Info("WorkflowC: Validating input with Activity1...")
 	var validationResult string
-	err := workflow.ExecuteActivity(ctx, "Activity1", input).Get(ctx, &validationResult)
+	err = workflow.ExecuteActivity(withActivityOptions(ctx, contracts.Activity1), contracts.Activity1, input).Get(ctx, &validationResult)
 	if err != nil {
 		logger.Error("WorkflowC: Validation failed", "error", err)
 		return "", fmt.Errorf("validation failed: %w", err)
@@ -43,7 +47,7 @@ func WorkflowC(ctx workflow.Context, input string) (string, error) {
 	// ==========================================
 	logger.Info("WorkflowC: Processing validated data with Activity2...")
 	var processResult string
-	err = workflow.ExecuteActivity(ctx, "Activity2", validationResult).Get(ctx, &processResult)
+	err = workflow.ExecuteActivity(withActivityOptions(ctx, contracts.Activity2), contracts.Activity2, validationResult).Get(ctx, &processResult)
 	if err != nil {
 		logger.Error("WorkflowC: Processing failed", "error", err)
 		return "", fmt.Errorf("processing failed: %w", err)
@@ -53,6 +57,8 @@ func WorkflowC(ctx workflow.Context, input string) (string, error) {
 	// ==========================================
 	// WorkflowC completado exitosamente
 	// ==========================================
-	logger.Info("WorkflowC completed successfully", "finalResult", processResult)
+	if processResult, err = sla.complete(ctx, processResult); err != nil {
+		return "", err
+	}
 	return processResult, nil
 }
@@ -0,0 +1,62 @@
+package workflows
+
+import (
+	"errors"
+
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/temporal-aws-poc/contracts"
+)
+
+func (s *WorkflowsTestSuite) Test_WorkflowC_Success() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("validated", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "validated").Return("processed", nil).Once()
+
+	s.env.ExecuteWorkflow(WorkflowC, "input")
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result string
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal("processed", result)
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowC_RetriesProcessing() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("validated", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "validated").Return("", errors.New("transient")).Twice()
+	s.env.OnActivity("Activity2", mock.Anything, "validated").Return("processed", nil).Once()
+
+	s.env.ExecuteWorkflow(WorkflowC, "input")
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowC_ValidationFails() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("", errors.New("invalid")).Times(3)
+
+	s.env.ExecuteWorkflow(WorkflowC, "input")
+
+	s.True(s.env.IsWorkflowCompleted())
+	err := s.env.GetWorkflowError()
+	s.Error(err)
+	s.Contains(err.Error(), "validation failed")
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowC_DoesNotRetryValidationErrors() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("validated", nil).Once()
+	// Sin marcar NonRetryable: es la RetryPolicy del perfil la que corta los reintentos
+	s.env.OnActivity("Activity2", mock.Anything, "validated").
+		Return("", temporal.NewApplicationError("bad input", contracts.ValidationErrorType)).Once()
+
+	s.env.ExecuteWorkflow(WorkflowC, "input")
+
+	s.True(s.env.IsWorkflowCompleted())
+	var activityErr *temporal.ActivityError
+	s.Require().True(errors.As(s.env.GetWorkflowError(), &activityErr))
+	var appErr *temporal.ApplicationError
+	s.Require().True(errors.As(activityErr.Unwrap(), &appErr))
+	s.Equal(contracts.ValidationErrorType, appErr.Type())
+}
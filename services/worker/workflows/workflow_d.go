@@ -1,30 +1,24 @@
 package workflows
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
-	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
+
+	"github.com/temporal-aws-poc/contracts"
 )
 
 // WorkflowD es un workflow de procesamiento que ejecuta activities en paralelo
 // Demuestra el uso de workflow.Go() para ejecución concurrente
 func WorkflowD(ctx workflow.Context, input string) (string, error) {
 	logger := workflow.GetLogger(ctx)
-	logger.Info("WorkflowD (parallel processing) started", "input", input)
-
-	// Configurar opciones de activities
-	activityOptions := workflow.ActivityOptions{
-		StartToCloseTimeout: 30 * time.Second,
-		RetryPolicy: &temporal.RetryPolicy{
-			InitialInterval:    time.Second,
-			BackoffCoefficient: 2.0,
-			MaximumInterval:    time.Minute,
-			MaximumAttempts:    3,
-		},
+
+	// Las ActivityOptions salen del perfil de la ejecución (ver paquete profiles)
+	if _, err := activityProfile(ctx); err != nil {
+		return "", err
 	}
-	ctx = workflow.WithActivityOptions(ctx, activityOptions)
 
 	// ==========================================
 	// PASO 1: Ejecutar 3 activities en paralelo
@@ -37,7 +31,7 @@ func WorkflowD(ctx workflow.Context, input string) (string, error) {
 
 	// Ejecutar Activity1 en paralelo
 	workflow.Go(ctx, func(gCtx workflow.Context) {
-		activity1Err = workflow.ExecuteActivity(gCtx, "Activity1", input).Get(gCtx, &activity1Result)
+		activity1Err = workflow.ExecuteActivity(withActivityOptions(gCtx, contracts.Activity1), contracts.Activity1, input).Get(gCtx, &activity1Result)
 		if activity1Err != nil {
 			logger.Error("WorkflowD: Activity1 failed", "error", activity1Err)
 		} else {
@@ -47,7 +41,7 @@ func WorkflowD(ctx workflow.Context, input string) (string, error) {
 
 	// Ejecutar Activity2 en paralelo
 	workflow.Go(ctx, func(gCtx workflow.Context) {
-		activity2Err = workflow.ExecuteActivity(gCtx, "Activity2", input).Get(gCtx, &activity2Result)
+		activity2Err = workflow.ExecuteActivity(withActivityOptions(gCtx, contracts.Activity2), contracts.Activity2, input).Get(gCtx, &activity2Result)
 		if activity2Err != nil {
 			logger.Error("WorkflowD: Activity2 failed", "error", activity2Err)
 		} else {
@@ -57,7 +51,7 @@ func WorkflowD(ctx workflow.Context, input string) (string, error) {
 
 	// Ejecutar Activity4 en paralelo
 	workflow.Go(ctx, func(gCtx workflow.Context) {
-		activity4Err = workflow.ExecuteActivity(gCtx, "Activity4", input).Get(gCtx, &activity4Result)
+		activity4Err = workflow.ExecuteActivity(withActivityOptions(gCtx, contracts.Activity4), contracts.Activity4, input).Get(gCtx, &activity4Result)
 		if activity4Err != nil {
 			logger.Error("WorkflowD: Activity4 failed", "error", activity4Err)
 		} else {
@@ -85,12 +79,14 @@ func WorkflowD(ctx workflow.Context, input string) (string, error) {
 	// ==========================================
 	logger.Info("WorkflowD: All parallel activities completed, consolidating results...")
 
-	// Combinar resultados
-	consolidatedInput := fmt.Sprintf("Parallel results: [%s, %s, %s]",
-		activity1Result, activity2Result, activity4Result)
+	// Combinar resultados en el objeto JSON que espera Activity3
+	consolidatedInput, err := consolidateResults(activity1Result, activity2Result, activity4Result)
+	if err != nil {
+		return "", fmt.Errorf("consolidation failed: %w", err)
+	}
 
 	var finalResult string
-	err := workflow.ExecuteActivity(ctx, "Activity3", consolidatedInput).Get(ctx, &finalResult)
+	err = workflow.ExecuteActivity(withActivityOptions(ctx, contracts.Activity3), contracts.Activity3, consolidatedInput).Get(ctx, &finalResult)
 	if err != nil {
 		logger.Error("WorkflowD: Consolidation failed", "error", err)
 		return "", fmt.Errorf("consolidation failed: %w", err)
@@ -99,6 +95,33 @@ func WorkflowD(ctx workflow.Context, input string) (string, error) {
 	// ==========================================
 	// WorkflowD completado exitosamente
 	// ==========================================
-	logger.Info("WorkflowD completed successfully", "finalResult", finalResult)
 	return finalResult, nil
 }
+
+// consolidateResults junta los resultados de activities en paralelo en un
+// objeto JSON: los campos de los resultados que son objetos se combinan (los
+// de uno posterior pisan a los anteriores) y los que no lo son quedan en
+// raw_results. La usan WorkflowD y la plantilla "consolidate" de los pipelines.
+func consolidateResults(results ...string) (string, error) {
+	consolidated := map[string]interface{}{}
+	var raw []string
+	for _, result := range results {
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(result), &object); err != nil || object == nil {
+			raw = append(raw, result)
+			continue
+		}
+		for k, v := range object {
+			consolidated[k] = v
+		}
+	}
+	if len(raw) > 0 {
+		consolidated["raw_results"] = raw
+	}
+
+	data, err := json.Marshal(consolidated)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,73 @@
+package workflows
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func (s *WorkflowsTestSuite) Test_WorkflowD_Success() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("r1", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "input").Return("r2", nil).Once()
+	s.env.OnActivity("Activity4", mock.Anything, "input").Return("r4", nil).Once()
+	s.env.OnActivity("Activity3", mock.Anything, `{"raw_results":["r1","r2","r4"]}`).Return("final", nil).Once()
+
+	s.env.ExecuteWorkflow(WorkflowD, "input")
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result string
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal("final", result)
+}
+
+// Activity3 no se mockea: valida el input consolidado que arma WorkflowD con
+// resultados como los de Activity1, Activity2 y Activity4
+func (s *WorkflowsTestSuite) Test_WorkflowD_RealActivity3AcceptsConsolidatedInput() {
+	input := `{"message":"hola"}`
+	s.env.OnActivity("Activity1", mock.Anything, input).Return(`{"message":"hola","activity1_processed":true}`, nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, input).Return(`{"message":"hola","activity2_processed":true}`, nil).Once()
+	s.env.OnActivity("Activity4", mock.Anything, input).Return(`{"message":"hola","activity4_processed":true}`, nil).Once()
+
+	s.env.ExecuteWorkflow(WorkflowD, input)
+
+	s.NoError(s.env.GetWorkflowError())
+	var result string
+	s.NoError(s.env.GetWorkflowResult(&result))
+	var final struct {
+		FinalStatus string   `json:"final_status"`
+		Validations []string `json:"validations"`
+	}
+	s.NoError(json.Unmarshal([]byte(result), &final))
+	s.Equal("SUCCESS", final.FinalStatus)
+	s.Equal([]string{"Activity1: OK", "Activity2: OK", "Activity4 (WorkflowB): OK"}, final.Validations)
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowD_ParallelActivityFails() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("r1", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "input").Return("", errors.New("boom")).Times(3)
+	s.env.OnActivity("Activity4", mock.Anything, "input").Return("r4", nil).Once()
+
+	s.env.ExecuteWorkflow(WorkflowD, "input")
+
+	s.True(s.env.IsWorkflowCompleted())
+	err := s.env.GetWorkflowError()
+	s.Error(err)
+	s.Contains(err.Error(), "Activity2 failed")
+}
+
+func (s *WorkflowsTestSuite) Test_WorkflowD_ConsolidationFails() {
+	s.env.OnActivity("Activity1", mock.Anything, "input").Return("r1", nil).Once()
+	s.env.OnActivity("Activity2", mock.Anything, "input").Return("r2", nil).Once()
+	s.env.OnActivity("Activity4", mock.Anything, "input").Return("r4", nil).Once()
+	s.env.OnActivity("Activity3", mock.Anything, mock.Anything).Return("", errors.New("boom")).Times(3)
+
+	s.env.ExecuteWorkflow(WorkflowD, "input")
+
+	s.True(s.env.IsWorkflowCompleted())
+	err := s.env.GetWorkflowError()
+	s.Error(err)
+	s.Contains(err.Error(), "consolidation failed")
+}
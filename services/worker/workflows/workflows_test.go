@@ -0,0 +1,33 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/temporal-aws-poc/worker/activities"
+)
+
+// WorkflowsTestSuite comparte el entorno de pruebas de todos los workflows;
+// las activities se registran con sus nombres reales y se mockean con OnActivity
+type WorkflowsTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env *testsuite.TestWorkflowEnvironment
+}
+
+func TestWorkflowsTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkflowsTestSuite))
+}
+
+func (s *WorkflowsTestSuite) SetupTest() {
+	s.env = s.NewTestWorkflowEnvironment()
+	s.env.RegisterWorkflow(WorkflowB)
+	s.env.RegisterActivity(activities.NewActivities())
+}
+
+func (s *WorkflowsTestSuite) AfterTest(suiteName, testName string) {
+	s.env.AssertExpectations(s.T())
+}